@@ -72,12 +72,27 @@ func dataSourceLinodeAccount() *schema.Resource {
 				Description: "This Account's balance, in US dollars.",
 				Computed:    true,
 			},
+			"transfer_used": {
+				Type:        schema.TypeInt,
+				Description: "The amount of the network transfer pool used this month, in GB.",
+				Computed:    true,
+			},
+			"transfer_quota": {
+				Type:        schema.TypeInt,
+				Description: "The size of the network transfer pool shared by this Account's Linodes this month, in GB.",
+				Computed:    true,
+			},
+			"transfer_billable": {
+				Type:        schema.TypeInt,
+				Description: "The amount of network transfer used this month beyond the transfer pool, in GB, which will be billed.",
+				Computed:    true,
+			},
 		},
 	}
 }
 
 func dataSourceLinodeAccountRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	account, err := client.GetAccount(context.Background())
 	if err != nil {
@@ -100,7 +115,33 @@ func dataSourceLinodeAccountRead(d *schema.ResourceData, meta interface{}) error
 
 	d.Set("balance", account.Balance)
 
+	transfer, err := getAccountTransfer(client)
+	if err != nil {
+		return fmt.Errorf("Error getting account network transfer: %s", err)
+	}
+	d.Set("transfer_used", transfer.Used)
+	d.Set("transfer_quota", transfer.Quota)
+	d.Set("transfer_billable", transfer.Billable)
+
 	// We exclude the credit_card and tax_id fields because they are too sensitive
 
 	return nil
 }
+
+// accountTransfer is the network transfer pool usage of an Account for the current month, in GB
+type accountTransfer struct {
+	Used     int `json:"used"`
+	Quota    int `json:"quota"`
+	Billable int `json:"billable"`
+}
+
+func getAccountTransfer(client linodego.Client) (*accountTransfer, error) {
+	r, err := client.R(context.Background()).SetResult(&accountTransfer{}).Get("account/transfer")
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*accountTransfer), nil
+}
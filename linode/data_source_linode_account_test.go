@@ -31,6 +31,30 @@ func TestAccDataSourceLinodeAccount(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resourceName, "zip"),
 					resource.TestCheckResourceAttrSet(resourceName, "state"),
 					resource.TestCheckResourceAttrSet(resourceName, "balance"),
+					resource.TestCheckResourceAttrSet(resourceName, "transfer_used"),
+					resource.TestCheckResourceAttrSet(resourceName, "transfer_quota"),
+					resource.TestCheckResourceAttrSet(resourceName, "transfer_billable"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceLinodeAccount_mockTransfer(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resourceName := "data.linode_account.foo"
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeAccount(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "email", "mock@example.com"),
+					resource.TestCheckResourceAttr(resourceName, "transfer_used", "120"),
+					resource.TestCheckResourceAttr(resourceName, "transfer_quota", "2000"),
+					resource.TestCheckResourceAttr(resourceName, "transfer_billable", "0"),
 				),
 			},
 		},
@@ -0,0 +1,46 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/linode/linodego"
+)
+
+func dataSourceLinodeAPIStatus() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceLinodeAPIStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"reachable": {
+				Type:        schema.TypeBool,
+				Description: "Whether the Linode API could be reached with the configured token.",
+				Computed:    true,
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Description: "The username of the profile associated with the configured token.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceLinodeAPIStatusRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+
+	profile, err := client.GetProfile(context.Background())
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && (lerr.Code == 401 || lerr.Code == 403) {
+			return fmt.Errorf("Error authenticating with the Linode API, check the provider token: %s", err)
+		}
+		return fmt.Errorf("Error reaching the Linode API: %s", err)
+	}
+
+	d.SetId(profile.Username)
+	d.Set("reachable", true)
+	d.Set("username", profile.Username)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package linode
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceLinodeAPIStatus(t *testing.T) {
+	t.Parallel()
+
+	resourceName := "data.linode_api_status.foo"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeAPIStatus(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "reachable", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "username"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceLinodeAPIStatus_mock(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resourceName := "data.linode_api_status.foo"
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeAPIStatus(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "reachable", "true"),
+					resource.TestCheckResourceAttr(resourceName, "username", "mockuser"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceLinodeAPIStatus_mockBadToken(t *testing.T) {
+	api := newMockLinodeAPI(t)
+	api.overrides["GET profile"] = func(w http.ResponseWriter, r *http.Request) {
+		writeMockResponse(w, http.StatusUnauthorized, map[string]interface{}{
+			"errors": []map[string]string{{"reason": "Invalid Token"}},
+		})
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testDataSourceLinodeAPIStatus(),
+				ExpectError: regexp.MustCompile("check the provider token"),
+			},
+		},
+	})
+}
+
+func testDataSourceLinodeAPIStatus() string {
+	return `data "linode_api_status" "foo" {}`
+}
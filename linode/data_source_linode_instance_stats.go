@@ -0,0 +1,146 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/linode/linodego"
+)
+
+// instanceStatsAttributes maps the attributes of linode_instance_stats to the series of the stats the Linode API
+// reports for an instance, and the description of each series
+var instanceStatsAttributes = map[string]struct {
+	description string
+	series      func(*instanceStats) [][]float64
+}{
+	"cpu":               {"The CPU usage of the Linode, in percent of a single core.", func(s *instanceStats) [][]float64 { return s.Data.CPU }},
+	"io":                {"The disk IO of the Linode, in blocks per second.", func(s *instanceStats) [][]float64 { return s.Data.IO.IO }},
+	"swap":              {"The swap IO of the Linode, in blocks per second.", func(s *instanceStats) [][]float64 { return s.Data.IO.Swap }},
+	"netv4_in":          {"The public IPv4 traffic into the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV4.In }},
+	"netv4_out":         {"The public IPv4 traffic out of the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV4.Out }},
+	"netv4_private_in":  {"The private IPv4 traffic into the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV4.PrivateIn }},
+	"netv4_private_out": {"The private IPv4 traffic out of the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV4.PrivateOut }},
+	"netv6_in":          {"The public IPv6 traffic into the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV6.In }},
+	"netv6_out":         {"The public IPv6 traffic out of the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV6.Out }},
+	"netv6_private_in":  {"The private IPv6 traffic into the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV6.PrivateIn }},
+	"netv6_private_out": {"The private IPv6 traffic out of the Linode, in bits per second.", func(s *instanceStats) [][]float64 { return s.Data.NetV6.PrivateOut }},
+}
+
+func dataSourceLinodeInstanceStats() *schema.Resource {
+	statsSchema := map[string]*schema.Schema{
+		"linode_id": {
+			Type:        schema.TypeInt,
+			Description: "The ID of the Linode to read the stats of.",
+			Required:    true,
+		},
+		"title": {
+			Type:        schema.TypeString,
+			Description: "The title of the stats, which names the Linode and the period they cover.",
+			Computed:    true,
+		},
+		"sampled_at": {
+			Type:        schema.TypeInt,
+			Description: "The time of the most recent sample, in milliseconds since the Unix epoch.",
+			Computed:    true,
+		},
+	}
+	for attribute, stat := range instanceStatsAttributes {
+		statsSchema[attribute] = &schema.Schema{
+			Type:        schema.TypeFloat,
+			Description: fmt.Sprintf("%s This is the most recent sample.", stat.description),
+			Computed:    true,
+		}
+		statsSchema[attribute+"_average"] = &schema.Schema{
+			Type:        schema.TypeFloat,
+			Description: fmt.Sprintf("%s This is the average of the samples of the last 24 hours.", stat.description),
+			Computed:    true,
+		}
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceLinodeInstanceStatsRead,
+		Schema: statsSchema,
+	}
+}
+
+func dataSourceLinodeInstanceStatsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+
+	linodeID := d.Get("linode_id").(int)
+
+	stats, err := getInstanceStats(client, linodeID)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok {
+			switch lerr.Code {
+			case 404:
+				return fmt.Errorf("Linode Instance %d was not found", linodeID)
+			case 400:
+				// Stats are only collected once a Linode has been running for a while
+				return fmt.Errorf("Error getting the stats of Linode Instance %d, stats may not be available yet for new Linodes: %s", linodeID, err)
+			}
+		}
+		return fmt.Errorf("Error getting the stats of Linode Instance %d: %s", linodeID, err)
+	}
+
+	d.SetId(strconv.Itoa(linodeID))
+	d.Set("title", stats.Title)
+
+	var sampledAt float64
+	for attribute, stat := range instanceStatsAttributes {
+		latest, total, count := 0.0, 0.0, 0
+		for _, sample := range stat.series(stats) {
+			if len(sample) < 2 {
+				continue
+			}
+			latest, total, count = sample[1], total+sample[1], count+1
+			if sample[0] > sampledAt {
+				sampledAt = sample[0]
+			}
+		}
+		average := 0.0
+		if count > 0 {
+			average = total / float64(count)
+		}
+		d.Set(attribute, latest)
+		d.Set(attribute+"_average", average)
+	}
+	d.Set("sampled_at", int(sampledAt))
+
+	return nil
+}
+
+// instanceNetStats are the network series of the stats of an instance
+type instanceNetStats struct {
+	In         [][]float64 `json:"in"`
+	Out        [][]float64 `json:"out"`
+	PrivateIn  [][]float64 `json:"private_in"`
+	PrivateOut [][]float64 `json:"private_out"`
+}
+
+// instanceStats are the stats of an instance, which linodego does not model. Each series is a list of
+// [timestamp, value] samples, taken every 5 minutes over the last 24 hours.
+type instanceStats struct {
+	Title string `json:"title"`
+	Data  struct {
+		CPU [][]float64 `json:"cpu"`
+		IO  struct {
+			IO   [][]float64 `json:"io"`
+			Swap [][]float64 `json:"swap"`
+		} `json:"io"`
+		NetV4 instanceNetStats `json:"netv4"`
+		NetV6 instanceNetStats `json:"netv6"`
+	} `json:"data"`
+}
+
+func getInstanceStats(client linodego.Client, linodeID int) (*instanceStats, error) {
+	r, err := client.R(context.Background()).SetResult(&instanceStats{}).Get(fmt.Sprintf("linode/instances/%d/stats", linodeID))
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*instanceStats), nil
+}
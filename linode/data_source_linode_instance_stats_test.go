@@ -0,0 +1,111 @@
+package linode
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceLinodeInstanceStats(t *testing.T) {
+	t.Parallel()
+
+	label := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Stats are not collected until a Linode has been running for a while
+				Config:      testDataSourceLinodeInstanceStats(label),
+				ExpectError: regexp.MustCompile("stats may not be available yet"),
+			},
+		},
+	})
+}
+
+func TestDataSourceLinodeInstanceStats_mock(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resourceName := "linode_instance.foobar"
+	dataResourceName := "data.linode_instance_stats.foobar"
+
+	label := acctest.RandomWithPrefix("tf-test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeInstanceStats(label),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataResourceName, "linode_id", resourceName, "id"),
+					resource.TestCheckResourceAttr(dataResourceName, "title", label+" - Stats"),
+					resource.TestCheckResourceAttr(dataResourceName, "sampled_at", "1500000600000"),
+					resource.TestCheckResourceAttr(dataResourceName, "cpu", "12"),
+					resource.TestCheckResourceAttr(dataResourceName, "cpu_average", "11"),
+					resource.TestCheckResourceAttr(dataResourceName, "io", "22"),
+					resource.TestCheckResourceAttr(dataResourceName, "swap_average", "31"),
+					resource.TestCheckResourceAttr(dataResourceName, "netv4_in", "42"),
+					resource.TestCheckResourceAttr(dataResourceName, "netv4_private_out", "72"),
+					resource.TestCheckResourceAttr(dataResourceName, "netv6_out_average", "91"),
+					resource.TestCheckResourceAttr(dataResourceName, "netv6_private_in", "102"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceLinodeInstanceStats_mockUnavailable(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	label := acctest.RandomWithPrefix("tf-test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeInstanceStatsInstance(label),
+			},
+			{
+				PreConfig: func() {
+					id := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.overrides[fmt.Sprintf("GET linode/instances/%d/stats", id)] = func(w http.ResponseWriter, r *http.Request) {
+						writeMockResponse(w, http.StatusBadRequest, map[string]interface{}{
+							"errors": []map[string]string{{"reason": "Stats are unavailable at this time."}},
+						})
+					}
+				},
+				Config:      testDataSourceLinodeInstanceStats(label),
+				ExpectError: regexp.MustCompile("stats may not be available yet for new Linodes"),
+			},
+		},
+	})
+}
+
+func testDataSourceLinodeInstanceStatsInstance(label string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	image = "linode/containerlinux"
+	type = "g6-standard-1"
+	region = "us-east"
+}`, label)
+}
+
+func testDataSourceLinodeInstanceStats(label string) string {
+	return testDataSourceLinodeInstanceStatsInstance(label) + `
+
+data "linode_instance_stats" "foobar" {
+	linode_id = "${linode_instance.foobar.id}"
+}`
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/linode/linodego"
 )
 
 func dataSourceLinodeProfile() *schema.Resource {
@@ -103,7 +102,7 @@ func dataSourceLinodeProfile() *schema.Resource {
 }
 
 func dataSourceLinodeProfileRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	profile, err := client.GetProfile(context.Background())
 	if err != nil {
@@ -0,0 +1,91 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/linode/linodego"
+)
+
+// regionCapabilityAttributes maps the boolean attributes of linode_region_capabilities to the capabilities the
+// Linode API reports for a region
+var regionCapabilityAttributes = map[string]string{
+	"linodes":         "Linodes",
+	"nodebalancers":   "NodeBalancers",
+	"block_storage":   "Block Storage",
+	"object_storage":  "Object Storage",
+	"kubernetes":      "Kubernetes",
+	"cloud_firewall":  "Cloud Firewall",
+	"vlans":           "Vlans",
+	"gpu":             "GPU Linodes",
+	"metadata":        "Metadata",
+	"placement_group": "Placement Group",
+}
+
+func dataSourceLinodeRegionCapabilities() *schema.Resource {
+	capabilitiesSchema := map[string]*schema.Schema{
+		"region": {
+			Type:        schema.TypeString,
+			Description: "The unique ID of the Region.",
+			Required:    true,
+		},
+		"capabilities": {
+			Type:        schema.TypeSet,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "All of the capabilities the Linode API reports for the Region.",
+			Computed:    true,
+		},
+	}
+	for attribute, capability := range regionCapabilityAttributes {
+		capabilitiesSchema[attribute] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Description: fmt.Sprintf("Whether the Region has the %s capability.", capability),
+			Computed:    true,
+		}
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceLinodeRegionCapabilitiesRead,
+		Schema: capabilitiesSchema,
+	}
+}
+
+func dataSourceLinodeRegionCapabilitiesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+
+	reqRegion := d.Get("region").(string)
+
+	region, err := getRegionCapabilities(client, reqRegion)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			return fmt.Errorf("Linode Region %s was not found", reqRegion)
+		}
+		return fmt.Errorf("Error getting the capabilities of Linode Region %s: %s", reqRegion, err)
+	}
+
+	d.SetId(region.ID)
+	d.Set("capabilities", region.Capabilities)
+	for attribute, capability := range regionCapabilityAttributes {
+		d.Set(attribute, sliceContains(region.Capabilities, capability))
+	}
+
+	return nil
+}
+
+// regionCapabilities is a Region with the capabilities linodego does not decode
+type regionCapabilities struct {
+	ID           string   `json:"id"`
+	Capabilities []string `json:"capabilities"`
+}
+
+func getRegionCapabilities(client linodego.Client, regionID string) (*regionCapabilities, error) {
+	r, err := client.R(context.Background()).SetResult(&regionCapabilities{}).Get(fmt.Sprintf("regions/%s", regionID))
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*regionCapabilities), nil
+}
@@ -0,0 +1,72 @@
+package linode
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceLinodeRegionCapabilities(t *testing.T) {
+	t.Parallel()
+
+	resourceName := "data.linode_region_capabilities.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeRegionCapabilities("us-east"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "us-east"),
+					resource.TestCheckResourceAttr(resourceName, "linodes", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "capabilities.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceLinodeRegionCapabilities_mock(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resourceName := "data.linode_region_capabilities.foobar"
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceLinodeRegionCapabilities("us-east"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "us-east"),
+					resource.TestCheckResourceAttr(resourceName, "capabilities.#", "5"),
+					resource.TestCheckResourceAttr(resourceName, "block_storage", "true"),
+					resource.TestCheckResourceAttr(resourceName, "gpu", "true"),
+					resource.TestCheckResourceAttr(resourceName, "metadata", "true"),
+					resource.TestCheckResourceAttr(resourceName, "vlans", "false"),
+				),
+			},
+			{
+				Config: testDataSourceLinodeRegionCapabilities("eu-west"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "nodebalancers", "true"),
+					resource.TestCheckResourceAttr(resourceName, "block_storage", "false"),
+					resource.TestCheckResourceAttr(resourceName, "gpu", "false"),
+				),
+			},
+			{
+				Config:      testDataSourceLinodeRegionCapabilities("mars-north"),
+				ExpectError: regexp.MustCompile("Linode Region mars-north was not found"),
+			},
+		},
+	})
+}
+
+func testDataSourceLinodeRegionCapabilities(regionID string) string {
+	return fmt.Sprintf(`
+data "linode_region_capabilities" "foobar" {
+	region = "%s"
+}`, regionID)
+}
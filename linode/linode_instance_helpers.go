@@ -1,19 +1,28 @@
 package linode
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/linode/linodego"
 	"golang.org/x/crypto/sha3"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
@@ -43,6 +52,351 @@ func flattenProfileReferrals(referrals linodego.ProfileReferrals) []flattenedPro
 	}}
 }
 
+// accountSettings represents the account-wide defaults, which linodego does not yet support
+type accountSettings struct {
+	NetworkHelper bool `json:"network_helper"`
+}
+
+func getAccountSettings(client linodego.Client) (*accountSettings, error) {
+	r, err := client.R(context.Background()).SetResult(&accountSettings{}).Get("account/settings")
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*accountSettings), nil
+}
+
+// applyAccountNetworkHelperDefault sets the network helper of each config that specifies helpers
+// without an explicit network value to the account's Network Helper default
+func applyAccountNetworkHelperDefault(client linodego.Client, d *schema.ResourceData, cset []interface{}) error {
+	var settings *accountSettings
+
+	for i, v := range cset {
+		config, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		helpers, ok := config["helpers"].([]interface{})
+		if !ok || len(helpers) == 0 {
+			continue
+		}
+
+		helperMap, ok := helpers[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if _, networkOk := d.GetOkExists(fmt.Sprintf("config.%d.helpers.0.network", i)); networkOk {
+			continue
+		}
+
+		if settings == nil {
+			var err error
+			if settings, err = getAccountSettings(client); err != nil {
+				return fmt.Errorf("Error fetching the account Network Helper setting: %s", err)
+			}
+		}
+
+		helperMap["network"] = settings.NetworkHelper
+	}
+
+	return nil
+}
+
+// placementGroup represents a Placement Group, which linodego does not yet support
+type placementGroup struct {
+	ID     int    `json:"id"`
+	Label  string `json:"label"`
+	Region string `json:"region"`
+}
+
+func getPlacementGroup(client linodego.Client, groupID int) (*placementGroup, error) {
+	r, err := client.R(context.Background()).SetResult(&placementGroup{}).Get(fmt.Sprintf("placement/groups/%d", groupID))
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*placementGroup), nil
+}
+
+// validatePlacementGroupRegion verifies the placement group exists in the region
+func validatePlacementGroupRegion(client linodego.Client, groupID int, region string) error {
+	group, err := getPlacementGroup(client, groupID)
+	if err != nil {
+		return fmt.Errorf("Error fetching placement group %d, placement groups may not be supported in region %s: %s", groupID, region, err)
+	}
+	if group.Region != region {
+		return fmt.Errorf("Error validating placement group %d: the group is in region %s, not %s", groupID, group.Region, region)
+	}
+	return nil
+}
+
+func assignPlacementGroup(client linodego.Client, groupID int, instanceID int) error {
+	return changePlacementGroup(client, "assign", groupID, instanceID)
+}
+
+func unassignPlacementGroup(client linodego.Client, groupID int, instanceID int) error {
+	return changePlacementGroup(client, "unassign", groupID, instanceID)
+}
+
+func changePlacementGroup(client linodego.Client, action string, groupID int, instanceID int) error {
+	body := map[string][]int{"linodes": {instanceID}}
+	r, err := client.R(context.Background()).SetBody(body).Post(fmt.Sprintf("placement/groups/%d/%s", groupID, action))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error running %s of Linode instance %d for placement group %d: %s", action, instanceID, groupID, err)
+	}
+	return nil
+}
+
+// instanceConfigInterface is a network interface of an instance config, which linodego does not yet model
+type instanceConfigInterface struct {
+	Purpose     string `json:"purpose"`
+	Label       string `json:"label"`
+	IPAMAddress string `json:"ipam_address"`
+}
+
+// listInstanceConfigsWithInterfaces lists the configs of the instance together with their network interfaces by
+// config ID, which linodego does not decode, so that the configs are only listed once
+func listInstanceConfigsWithInterfaces(client linodego.Client, instanceID int) ([]linodego.InstanceConfig, map[int][]instanceConfigInterface, error) {
+	var configs []linodego.InstanceConfig
+	interfaces := make(map[int][]instanceConfigInterface)
+	for page, pages := 1, 1; page <= pages; page++ {
+		var result struct {
+			Pages int `json:"pages"`
+			Data  []struct {
+				linodego.InstanceConfig
+				Interfaces []instanceConfigInterface `json:"interfaces"`
+			} `json:"data"`
+		}
+		r, err := client.R(context.Background()).SetResult(&result).SetQueryParam("page", strconv.Itoa(page)).Get(fmt.Sprintf("linode/instances/%d/configs", instanceID))
+		if err == nil && r.IsError() {
+			err = linodego.NewError(r)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pages = result.Pages
+		for _, config := range result.Data {
+			config.Created, config.Updated = parseAPIDate(config.CreatedStr), parseAPIDate(config.UpdatedStr)
+			configs = append(configs, config.InstanceConfig)
+			interfaces[config.ID] = config.Interfaces
+		}
+	}
+	return configs, interfaces, nil
+}
+
+// parseAPIDate parses a date of the Linode API, for the objects which are not decoded by linodego
+func parseAPIDate(date string) *time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05", date)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func flattenInstanceConfigInterfaces(interfaces []instanceConfigInterface) []map[string]interface{} {
+	flattened := make([]map[string]interface{}, len(interfaces))
+	for i, iface := range interfaces {
+		flattened[i] = map[string]interface{}{
+			"purpose":      iface.Purpose,
+			"label":        iface.Label,
+			"ipam_address": iface.IPAMAddress,
+		}
+	}
+	return flattened
+}
+
+// validateStackscriptRevision verifies that the StackScript is still at the revision to be deployed, which is the
+// updated date of the revision as recorded in the pinned_revision of a linode_stackscript
+func validateStackscriptRevision(client linodego.Client, stackscriptID int, revision string) error {
+	if stackscriptID == 0 {
+		return fmt.Errorf("Error validating stackscript_revision: stackscript_id must be set")
+	}
+	stackscript, err := client.GetStackscript(context.Background(), stackscriptID)
+	if err != nil {
+		return fmt.Errorf("Error getting Linode Stackscript %d: %s", stackscriptID, err)
+	}
+	if stackscript.Updated == nil || stackscript.Updated.String() != revision {
+		current := ""
+		if stackscript.Updated != nil {
+			current = stackscript.Updated.String()
+		}
+		return fmt.Errorf("Error validating stackscript_revision: Linode Stackscript %d was revised since revision %s, it is now at revision %s (rev_note %q)", stackscriptID, revision, current, stackscript.RevNote)
+	}
+	return nil
+}
+
+// instanceExtras are the fields of an instance which linodego does not yet model
+type instanceExtras struct {
+	PlacementGroup *placementGroup `json:"placement_group"`
+	HostUUID       string          `json:"host_uuid"`
+	// MaintenancePolicy is the slug of the policy applied when the host of the instance is under maintenance
+	MaintenancePolicy string `json:"maintenance_policy"`
+}
+
+// instanceWithExtras decodes an instance together with the fields linodego does not yet model
+type instanceWithExtras struct {
+	linodego.Instance
+	instanceExtras
+}
+
+// getInstanceWithExtras gets the instance and its extra fields with a single request
+func getInstanceWithExtras(client linodego.Client, instanceID int) (*linodego.Instance, *instanceExtras, error) {
+	r, err := client.R(context.Background()).SetResult(&instanceWithExtras{}).Get(fmt.Sprintf("linode/instances/%d", instanceID))
+	if err != nil {
+		return nil, nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, nil, linodego.NewError(r)
+	}
+
+	result := r.Result().(*instanceWithExtras)
+	result.Created, result.Updated = parseAPIDate(result.CreatedStr), parseAPIDate(result.UpdatedStr)
+	return &result.Instance, &result.instanceExtras, nil
+}
+
+// reservedIP represents a reserved IPv4 address, which linodego does not yet support
+type reservedIP struct {
+	Address  string `json:"address"`
+	Region   string `json:"region"`
+	Reserved bool   `json:"reserved"`
+	LinodeID *int   `json:"linode_id"`
+}
+
+func getReservedIP(client linodego.Client, address string) (*reservedIP, error) {
+	r, err := client.R(context.Background()).SetResult(&reservedIP{}).Get(fmt.Sprintf("networking/reserved/ips/%s", address))
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*reservedIP), nil
+}
+
+// validateReservedIPRegion verifies that the reserved IP address exists in the region of the instance
+func validateReservedIPRegion(client linodego.Client, address string, region string) error {
+	ip, err := getReservedIP(client, address)
+	if err != nil {
+		return fmt.Errorf("Error fetching reserved IP %s: %s", address, err)
+	}
+	if ip.Region != region {
+		return fmt.Errorf("Error validating reserved IP %s: the address is in region %s, not %s", address, ip.Region, region)
+	}
+	return nil
+}
+
+// assignReservedIP attaches a reserved IPv4 address to the instance as an additional public address
+func assignReservedIP(client linodego.Client, instanceID int, address string) error {
+	body := map[string]interface{}{"type": "ipv4", "public": true, "address": address}
+	r, err := client.R(context.Background()).SetBody(body).Post(fmt.Sprintf("linode/instances/%d/ips", instanceID))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error assigning reserved IP %s to Linode instance %d: %s", address, instanceID, err)
+	}
+	return nil
+}
+
+// unassignReservedIP detaches a reserved IPv4 address from the instance, returning it to the account's reservations
+func unassignReservedIP(client linodego.Client, instanceID int, address string) error {
+	r, err := client.R(context.Background()).Delete(fmt.Sprintf("linode/instances/%d/ips/%s", instanceID, address))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error unassigning reserved IP %s from Linode instance %d: %s", address, instanceID, err)
+	}
+	return nil
+}
+
+// getInstanceIPAddressesWithAdditional gets the IP addresses of the instance with a single request, together with the
+// reserved IPv4 addresses attached to it and the IPv4 addresses of other instances which are shared with it, which
+// linodego does not yet model
+func getInstanceIPAddressesWithAdditional(client linodego.Client, instanceID int) (network *linodego.InstanceIPAddressResponse, reserved []string, shared []string, err error) {
+	r, err := client.R(context.Background()).SetResult(&linodego.InstanceIPAddressResponse{}).Get(fmt.Sprintf("linode/instances/%d/ips", instanceID))
+	if err != nil {
+		return nil, nil, nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, nil, nil, linodego.NewError(r)
+	}
+
+	additional := &struct {
+		IPv4 struct {
+			Public []reservedIP `json:"public"`
+			Shared []reservedIP `json:"shared"`
+		} `json:"ipv4"`
+	}{}
+	if err := json.Unmarshal(r.Body(), additional); err != nil {
+		return nil, nil, nil, err
+	}
+
+	reserved, shared = []string{}, []string{}
+	for _, ip := range additional.IPv4.Public {
+		if ip.Reserved {
+			reserved = append(reserved, ip.Address)
+		}
+	}
+	for _, ip := range additional.IPv4.Shared {
+		shared = append(shared, ip.Address)
+	}
+	return r.Result().(*linodego.InstanceIPAddressResponse), reserved, shared, nil
+}
+
+// addInstancePrivateIP adds a private IPv4 address to the instance. An instance may only have one private address,
+// so when adding it is rejected and the instance already has one, such as when an earlier apply added the address
+// but failed afterwards, the existing address is returned instead.
+func addInstancePrivateIP(client linodego.Client, instanceID int) (string, error) {
+	ip, err := client.AddInstanceIPAddress(context.Background(), instanceID, false)
+	if err == nil {
+		return ip.Address, nil
+	}
+
+	if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 400 {
+		network, netErr := client.GetInstanceIPAddresses(context.Background(), instanceID)
+		if netErr != nil {
+			return "", fmt.Errorf("Error getting the IPs for Linode instance %d: %s", instanceID, netErr)
+		}
+		if len(network.IPv4.Private) > 0 {
+			log.Printf("[INFO] Linode instance %d already has the private IP %s, which is used instead of adding one", instanceID, network.IPv4.Private[0].Address)
+			return network.IPv4.Private[0].Address, nil
+		}
+	}
+	return "", err
+}
+
+// updateInstanceIPv6RDNS sets the reverse DNS of the instance's IPv6 SLAAC address, an empty rdns removes it
+func updateInstanceIPv6RDNS(client linodego.Client, instanceID int, rdns string) error {
+	network, err := client.GetInstanceIPAddresses(context.Background(), instanceID)
+	if err != nil {
+		return fmt.Errorf("Error getting the IPs for Linode instance %d: %s", instanceID, err)
+	}
+	if network.IPv6 == nil || network.IPv6.SLAAC == nil || network.IPv6.SLAAC.Address == "" {
+		return fmt.Errorf("Error setting rdns_ipv6 for Linode instance %d: the instance has no IPv6 address", instanceID)
+	}
+
+	updateOpts := linodego.IPAddressUpdateOptions{}
+	if rdns != "" {
+		updateOpts.RDNS = &rdns
+	}
+	address := network.IPv6.SLAAC.Address
+	if _, err = client.UpdateIPAddress(context.Background(), address, updateOpts); err != nil {
+		return fmt.Errorf("Error setting the reverse DNS of %s for Linode instance %d: %s", address, instanceID, err)
+	}
+	return nil
+}
+
 func flattenInstanceSpecs(instance linodego.Instance) []map[string]int {
 	return []map[string]int{{
 		"vcpus":    instance.Specs.VCPUs,
@@ -52,6 +406,111 @@ func flattenInstanceSpecs(instance linodego.Instance) []map[string]int {
 	}}
 }
 
+// instanceTransferResetDate estimates when the network transfer used by instances is next reset. Neither the instance
+// nor the account transfer endpoints report the reset date, but transfer is counted per calendar month in UTC, so it
+// is expected to reset at the start of the month after now.
+func instanceTransferResetDate(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// instanceAlertThresholds are the names of the alert thresholds of an instance
+var instanceAlertThresholds = []string{"cpu", "io", "network_in", "network_out", "transfer_quota"}
+
+// expandInstanceAlerts overlays the thresholds of the alert profile, and then the alert thresholds set in the alerts
+// block, onto the current thresholds of the instance. Thresholds which are not set keep their current values, while
+// thresholds set to 0 disable the alert.
+func expandInstanceAlerts(d *schema.ResourceData, current *linodego.InstanceAlert, profile map[string]int) *linodego.InstanceAlert {
+	alerts := &linodego.InstanceAlert{}
+	if current != nil {
+		*alerts = *current
+	}
+
+	thresholds := map[string]*int{
+		"cpu":            &alerts.CPU,
+		"io":             &alerts.IO,
+		"network_in":     &alerts.NetworkIn,
+		"network_out":    &alerts.NetworkOut,
+		"transfer_quota": &alerts.TransferQuota,
+	}
+
+	for key, threshold := range thresholds {
+		if value, ok := profile[key]; ok {
+			*threshold = value
+		}
+		if value, ok := d.GetOkExists("alerts.0." + key); ok {
+			*threshold = value.(int)
+		}
+	}
+
+	return alerts
+}
+
+// instanceBackupsEnabled tells whether a new instance is enrolled in the Backup service. backups_enabled takes
+// precedence, so an instance can opt out of auto_enable_backups by setting it to false.
+func instanceBackupsEnabled(d *schema.ResourceData, config *Config) bool {
+	if enabled, ok := d.GetOkExists("backups_enabled"); ok {
+		return enabled.(bool)
+	}
+	return config.AutoEnableBackups
+}
+
+// customizeInstanceAlertProfileDiff plans the thresholds of the alert_profile of an instance. The API and the diff can't
+// tell a threshold set in alerts from one read back from the API, so a threshold follows the profile while it still
+// has the value the profile last applied, and is otherwise kept as an override. New instances apply the profile in
+// Create, which knows which thresholds are set in alerts.
+func customizeInstanceAlertProfileDiff(d *schema.ResourceDiff, profiles map[string]map[string]int) error {
+	name := d.Get("alert_profile").(string)
+	if name == "" {
+		if len(d.Get("alert_profile_thresholds").(map[string]interface{})) > 0 {
+			return d.SetNew("alert_profile_thresholds", map[string]interface{}{})
+		}
+		return nil
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("Error validating alert_profile: %s is not an alert_profile of the provider", name)
+	}
+
+	profileThresholds := make(map[string]interface{}, len(profile))
+	for key, value := range profile {
+		profileThresholds[key] = value
+	}
+
+	applied, _ := d.GetChange("alert_profile_thresholds")
+	appliedThresholds := applied.(map[string]interface{})
+	if d.Id() != "" {
+		alerts := make(map[string]interface{}, len(instanceAlertThresholds))
+		changed := false
+		for _, key := range instanceAlertThresholds {
+			value := d.Get("alerts.0." + key).(int)
+			alerts[key] = value
+
+			profileValue, inProfile := profile[key]
+			if !inProfile || d.HasChange("alerts.0."+key) || value == profileValue {
+				continue
+			}
+			if appliedValue, wasApplied := appliedThresholds[key]; wasApplied && appliedValue.(int) != value {
+				// The threshold was overridden after the profile was applied
+				continue
+			}
+			alerts[key] = profileValue
+			changed = true
+		}
+		if changed {
+			if err := d.SetNew("alerts", []interface{}{alerts}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(appliedThresholds, profileThresholds) {
+		return d.SetNew("alert_profile_thresholds", profileThresholds)
+	}
+	return nil
+}
+
 func flattenInstanceAlerts(instance linodego.Instance) []map[string]int {
 	return []map[string]int{{
 		"cpu":            instance.Alerts.CPU,
@@ -62,25 +521,168 @@ func flattenInstanceAlerts(instance linodego.Instance) []map[string]int {
 	}}
 }
 
-type flattenedInstanceBackupSchedule [1]struct {
-	day, window string
-}
+// flattenInstanceAlertsEnabled returns the names of the alerts of an instance which are enabled, in a fixed order
+func flattenInstanceAlertsEnabled(instance linodego.Instance) []string {
+	enabled := []string{}
+	if instance.Alerts == nil {
+		return enabled
+	}
 
-type flattenedInstanceBackup [1]struct {
-	enabled  bool
-	schedule flattenedInstanceBackupSchedule
+	thresholds := []struct {
+		name      string
+		threshold int
+	}{
+		{"cpu", instance.Alerts.CPU},
+		{"io", instance.Alerts.IO},
+		{"network_in", instance.Alerts.NetworkIn},
+		{"network_out", instance.Alerts.NetworkOut},
+		{"transfer_quota", instance.Alerts.TransferQuota},
+	}
+	for _, t := range thresholds {
+		if t.threshold > 0 {
+			enabled = append(enabled, t.name)
+		}
+	}
+	return enabled
 }
 
-func flattenInstanceBackups(instance linodego.Instance) flattenedInstanceBackup {
-	return flattenedInstanceBackup{{
-		instance.Backups.Enabled,
-		flattenedInstanceBackupSchedule{{
-			instance.Backups.Schedule.Day,
-			instance.Backups.Schedule.Window,
+func flattenInstanceBackups(instance linodego.Instance) []map[string]interface{} {
+	return []map[string]interface{}{{
+		"enabled": instance.Backups.Enabled,
+		"schedule": []map[string]interface{}{{
+			"day":    normalizeBackupScheduleDay(instance.Backups.Schedule.Day),
+			"window": normalizeBackupScheduleWindow(instance.Backups.Schedule.Window),
 		}},
 	}}
 }
 
+// flattenInstanceAvailableBackups returns the automatic backups followed by the current and in progress snapshots
+func flattenInstanceAvailableBackups(backups *linodego.InstanceBackupsResponse) []map[string]interface{} {
+	snapshots := append([]*linodego.InstanceSnapshot{}, backups.Automatic...)
+	if backups.Snapshot != nil {
+		snapshots = append(snapshots, backups.Snapshot.Current, backups.Snapshot.InProgress)
+	}
+
+	availableBackups := []map[string]interface{}{}
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		disks := make([]map[string]interface{}, 0, len(snapshot.Disks))
+		for _, disk := range snapshot.Disks {
+			disks = append(disks, map[string]interface{}{
+				"label":      disk.Label,
+				"size":       disk.Size,
+				"filesystem": disk.Filesystem,
+			})
+		}
+		backup := map[string]interface{}{
+			"id":       snapshot.ID,
+			"type":     snapshot.Type,
+			"label":    snapshot.Label,
+			"status":   string(snapshot.Status),
+			"configs":  snapshot.Configs,
+			"disks":    disks,
+			"created":  "",
+			"finished": "",
+		}
+		if snapshot.Created != nil {
+			backup["created"] = snapshot.Created.Format(time.RFC3339)
+		}
+		if snapshot.Finished != nil {
+			backup["finished"] = snapshot.Finished.Format(time.RFC3339)
+		}
+		availableBackups = append(availableBackups, backup)
+	}
+	return availableBackups
+}
+
+var backupScheduleDays = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// normalizeBackupScheduleDay converts day names and abbreviations ("monday", "Mon") to the API form ("Monday").
+// Values that are not days, such as "Scheduling", are returned unchanged.
+func normalizeBackupScheduleDay(day string) string {
+	if normalized, ok := parseBackupScheduleDay(day); ok {
+		return normalized
+	}
+	return day
+}
+
+func parseBackupScheduleDay(day string) (string, bool) {
+	trimmed := strings.TrimSpace(day)
+	for _, d := range backupScheduleDays {
+		if strings.EqualFold(trimmed, d) || strings.EqualFold(trimmed, d[:3]) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// normalizeBackupScheduleWindow converts window codes ("w2", "W02", "2", "02:00") to the API form ("W2").
+// Values that are not windows, such as "Scheduling", are returned unchanged.
+func normalizeBackupScheduleWindow(window string) string {
+	if normalized, ok := parseBackupScheduleWindow(window); ok {
+		return normalized
+	}
+	return window
+}
+
+func parseBackupScheduleWindow(window string) (string, bool) {
+	trimmed := strings.ToUpper(strings.TrimSpace(window))
+	trimmed = strings.TrimSuffix(strings.TrimPrefix(trimmed, "W"), ":00")
+	hour, err := strconv.Atoi(trimmed)
+	if err != nil || hour < 0 || hour > 22 || hour%2 != 0 {
+		return "", false
+	}
+	return fmt.Sprintf("W%d", hour), true
+}
+
+func validateBackupScheduleDay(v interface{}, k string) (ws []string, es []error) {
+	if _, ok := parseBackupScheduleDay(v.(string)); !ok {
+		es = append(es, fmt.Errorf("%q must be a day of the week (e.g. \"Monday\"), got %q", k, v))
+	}
+	return
+}
+
+func validateBackupScheduleWindow(v interface{}, k string) (ws []string, es []error) {
+	if _, ok := parseBackupScheduleWindow(v.(string)); !ok {
+		es = append(es, fmt.Errorf("%q must be an even-hour window from W0 to W22, got %q", k, v))
+	}
+	return
+}
+
+// backupScheduleDiffSuppressFunc ignores differences that normalize to the same schedule value
+func backupScheduleDiffSuppressFunc(normalize func(string) string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return normalize(old) == normalize(new)
+	}
+}
+
+// updateInstanceBackupSchedule sets the instance backup day and window. linodego does not serialize
+// the schedule with the field name the API expects, so the request body is built here.
+func updateInstanceBackupSchedule(client linodego.Client, instanceID int, day, window string) error {
+	schedule := map[string]string{}
+	if day != "" {
+		schedule["day"] = normalizeBackupScheduleDay(day)
+	}
+	if window != "" {
+		schedule["window"] = normalizeBackupScheduleWindow(window)
+	}
+	if len(schedule) == 0 {
+		return nil
+	}
+
+	body := map[string]interface{}{"backups": map[string]interface{}{"schedule": schedule}}
+	r, err := client.R(context.Background()).SetBody(body).Put(fmt.Sprintf("linode/instances/%d", instanceID))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error updating the backup schedule of Linode instance %d: %s", instanceID, err)
+	}
+	return nil
+}
+
 func flattenInstanceDisks(instanceDisks []linodego.InstanceDisk) (disks []map[string]interface{}, swapSize int) {
 	for _, disk := range instanceDisks {
 		// Determine if swap exists and the size.  If it does not exist, swap_size=0
@@ -92,24 +694,96 @@ func flattenInstanceDisks(instanceDisks []linodego.InstanceDisk) (disks []map[st
 			"size":       disk.Size,
 			"label":      disk.Label,
 			"filesystem": string(disk.Filesystem),
+			// The API does not report read_only, disks in state keep their value with retainInstanceDiskFields
+			"read_only": false,
 		})
 	}
 	return
 }
 
-func flattenInstanceConfigs(instanceConfigs []linodego.InstanceConfig, diskLabelIDMap map[int]string) (configs []map[string]interface{}) {
-	for _, config := range instanceConfigs {
+// retainInstanceDiskFields copies the named fields, which the API does not return, from the
+// disks in state to the flattened disks with the same label
+func retainInstanceDiskFields(disks []map[string]interface{}, stateDisks []interface{}, fields ...string) {
+	for _, disk := range disks {
+		for _, stateDisk := range stateDisks {
+			stateDiskMap, ok := stateDisk.(map[string]interface{})
+			if !ok || stateDiskMap["label"] != disk["label"] {
+				continue
+			}
+			for _, field := range fields {
+				if value, found := stateDiskMap[field]; found {
+					disk[field] = value
+				}
+			}
+		}
+	}
+}
 
-		devices := []map[string]interface{}{{
-			"sda": flattenInstanceConfigDevice(config.Devices.SDA, diskLabelIDMap),
-			"sdb": flattenInstanceConfigDevice(config.Devices.SDB, diskLabelIDMap),
-			"sdc": flattenInstanceConfigDevice(config.Devices.SDC, diskLabelIDMap),
-			"sdd": flattenInstanceConfigDevice(config.Devices.SDD, diskLabelIDMap),
-			"sde": flattenInstanceConfigDevice(config.Devices.SDE, diskLabelIDMap),
-			"sdf": flattenInstanceConfigDevice(config.Devices.SDF, diskLabelIDMap),
-			"sdg": flattenInstanceConfigDevice(config.Devices.SDG, diskLabelIDMap),
-			"sdh": flattenInstanceConfigDevice(config.Devices.SDH, diskLabelIDMap),
-		}}
+// sortInstanceDisksByState orders the flattened disks to follow the disk list in state, so
+// disks which the API returns in creation order do not cause a diff. Unknown disks are appended.
+func sortInstanceDisksByState(disks []map[string]interface{}, stateDisks []interface{}) []map[string]interface{} {
+	sorted := make([]map[string]interface{}, 0, len(disks))
+	used := make([]bool, len(disks))
+	for _, stateDisk := range stateDisks {
+		stateDiskMap, ok := stateDisk.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for i, disk := range disks {
+			if !used[i] && disk["label"] == stateDiskMap["label"] {
+				sorted = append(sorted, disk)
+				used[i] = true
+				break
+			}
+		}
+	}
+	for i, disk := range disks {
+		if !used[i] {
+			sorted = append(sorted, disk)
+		}
+	}
+	return sorted
+}
+
+// sortInstanceDisksByDevices orders the flattened disks to follow the device slots of config, sda first, for disks
+// which are not yet in state, such as on import. Disks which are not attached to config are appended.
+func sortInstanceDisksByDevices(disks []map[string]interface{}, config *linodego.InstanceConfig) []map[string]interface{} {
+	if config == nil || config.Devices == nil {
+		return disks
+	}
+	dmap := config.Devices
+	drives := []*linodego.InstanceConfigDevice{
+		dmap.SDA, dmap.SDB, dmap.SDC, dmap.SDD, dmap.SDE, dmap.SDF, dmap.SDG, dmap.SDH,
+	}
+
+	var deviceDisks []interface{}
+	for _, drive := range drives {
+		if drive == nil || drive.DiskID == 0 {
+			continue
+		}
+		for _, disk := range disks {
+			if disk["id"] == drive.DiskID {
+				deviceDisks = append(deviceDisks, map[string]interface{}{"label": disk["label"]})
+				break
+			}
+		}
+	}
+	return sortInstanceDisksByState(disks, deviceDisks)
+}
+
+func flattenInstanceConfigs(instanceConfigs []linodego.InstanceConfig, diskLabelIDMap map[int]string) (configs []map[string]interface{}) {
+	for _, config := range instanceConfigs {
+
+		devices := []map[string]interface{}{{
+			"sda": flattenInstanceConfigDevice(config.Devices.SDA, diskLabelIDMap),
+			"sdb": flattenInstanceConfigDevice(config.Devices.SDB, diskLabelIDMap),
+			"sdc": flattenInstanceConfigDevice(config.Devices.SDC, diskLabelIDMap),
+			"sdd": flattenInstanceConfigDevice(config.Devices.SDD, diskLabelIDMap),
+			"sde": flattenInstanceConfigDevice(config.Devices.SDE, diskLabelIDMap),
+			"sdf": flattenInstanceConfigDevice(config.Devices.SDF, diskLabelIDMap),
+			"sdg": flattenInstanceConfigDevice(config.Devices.SDG, diskLabelIDMap),
+			"sdh": flattenInstanceConfigDevice(config.Devices.SDH, diskLabelIDMap),
+		}}
 
 		// Determine if swap exists and the size.  If it does not exist, swap_size=0
 		c := map[string]interface{}{
@@ -120,14 +794,9 @@ func flattenInstanceConfigs(instanceConfigs []linodego.InstanceConfig, diskLabel
 			"comments":     config.Comments,
 			"memory_limit": config.MemoryLimit,
 			"label":        config.Label,
-			"helpers": []map[string]bool{{
-				"updatedb_disabled":  config.Helpers.UpdateDBDisabled,
-				"distro":             config.Helpers.Distro,
-				"modules_dep":        config.Helpers.ModulesDep,
-				"network":            config.Helpers.Network,
-				"devtmpfs_automount": config.Helpers.DevTmpFsAutomount,
-			}},
-			"devices": devices,
+			"initrd":       flattenInstanceConfigInitRD(config.InitRD, diskLabelIDMap),
+			"helpers":      flattenInstanceConfigHelpers(config.Helpers),
+			"devices":      devices,
 		}
 
 		// Work-Around API reporting root_device /dev/sda despite not existing and requesting a different root
@@ -142,12 +811,62 @@ func flattenInstanceConfigs(instanceConfigs []linodego.InstanceConfig, diskLabel
 			}
 		}
 
+		c["root_device_num"] = instanceConfigRootDeviceNum(c["root_device"].(string))
+
 		configs = append(configs, c)
 	}
 	return
 }
 
-func createInstanceConfigsFromSet(client linodego.Client, instanceID int, cset []interface{}, diskIDLabelMap map[string]int, detacher volumeDetacher) (map[int]linodego.InstanceConfig, error) {
+func flattenInstanceConfigHelpers(helpers *linodego.InstanceConfigHelpers) []map[string]bool {
+	return []map[string]bool{{
+		"updatedb_disabled":  helpers.UpdateDBDisabled,
+		"distro":             helpers.Distro,
+		"modules_dep":        helpers.ModulesDep,
+		"network":            helpers.Network,
+		"devtmpfs_automount": helpers.DevTmpFsAutomount,
+	}}
+}
+
+// updateInstanceDeployedConfigHelpers applies the top-level helpers to the configs of an instance deployed from an
+// Image, and returns whether any config changed. The network helper keeps its current value when it is not set.
+func updateInstanceDeployedConfigHelpers(client linodego.Client, d *schema.ResourceData, instanceID int) (bool, error) {
+	configs, err := client.ListInstanceConfigs(context.Background(), instanceID, nil)
+	if err != nil {
+		return false, fmt.Errorf("Error fetching the configs for Instance %d: %s", instanceID, err)
+	}
+	if d.Get("ignore_extra_configs").(bool) {
+		configs = managedInstanceConfigs(configs, nil, d.Get("boot_config_label").(string))
+	}
+
+	changed := false
+	for _, config := range configs {
+		helpers := &linodego.InstanceConfigHelpers{
+			UpdateDBDisabled:  d.Get("helpers.0.updatedb_disabled").(bool),
+			Distro:            d.Get("helpers.0.distro").(bool),
+			ModulesDep:        d.Get("helpers.0.modules_dep").(bool),
+			DevTmpFsAutomount: d.Get("helpers.0.devtmpfs_automount").(bool),
+		}
+		if network, networkOk := d.GetOkExists("helpers.0.network"); networkOk {
+			helpers.Network = network.(bool)
+		} else if config.Helpers != nil {
+			helpers.Network = config.Helpers.Network
+		}
+		if !instanceConfigHelpersChanged(config.Helpers, helpers) {
+			continue
+		}
+
+		updateOpts := config.GetUpdateOptions()
+		updateOpts.Helpers = helpers
+		if _, err := client.UpdateInstanceConfig(context.Background(), instanceID, config.ID, updateOpts); err != nil {
+			return changed, fmt.Errorf("Error updating the helpers of Instance %d Config %d: %s", instanceID, config.ID, err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+func createInstanceConfigsFromSet(client linodego.Client, instanceID int, cset []interface{}, diskIDLabelMap map[string]int, diskIDOrdered []int, detacher volumeDetacher) (map[int]linodego.InstanceConfig, error) {
 	configIDMap := make(map[int]linodego.InstanceConfig, len(cset))
 
 	for _, v := range cset {
@@ -162,6 +881,8 @@ func createInstanceConfigsFromSet(client linodego.Client, instanceID int, cset [
 		configOpts.Kernel = config["kernel"].(string)
 		configOpts.Label = config["label"].(string)
 		configOpts.Comments = config["comments"].(string)
+		configOpts.MemoryLimit = config["memory_limit"].(int)
+		configOpts.VirtMode = config["virt_mode"].(string)
 
 		if helpers, helpersOk := config["helpers"].([]interface{}); helpersOk {
 			for _, helper := range helpers {
@@ -196,12 +917,21 @@ func createInstanceConfigsFromSet(client linodego.Client, instanceID int, cset [
 			}
 		}
 
-		rootDevice := config["root_device"].(string)
+		rootDevice, err := expandInstanceConfigRootDevice(config, nil)
+		if err != nil {
+			return configIDMap, err
+		}
 		if rootDevice != "" {
 			configOpts.RootDevice = &rootDevice
 		}
-		// configOpts.InitRD = config["initrd"].(string)
-		// TODO(displague) need a disk_label to initrd lookup?
+		initrd, err := expandInstanceConfigInitRD(config["initrd"].(string), diskIDLabelMap)
+		if err != nil {
+			return configIDMap, err
+		}
+		if initrd != nil {
+			configOpts.InitRD = *initrd
+		}
+
 		devices, ok := config["devices"].([]interface{})
 		if !ok {
 			return configIDMap, fmt.Errorf("Error converting config devices")
@@ -225,499 +955,2160 @@ func createInstanceConfigsFromSet(client linodego.Client, instanceID int, cset [
 			//	configOpts.RootDevice = &empty
 			//}
 		}
-
-		//empty := ""
-		//configOpts.RootDevice = &empty
-		if err := detachConfigVolumes(configOpts.Devices, detacher); err != nil {
-			return configIDMap, err
+		if len(devices) == 0 {
+			configOpts.Devices = orderedInstanceConfigDevices(diskIDOrdered)
+		}
+
+		//empty := ""
+		//configOpts.RootDevice = &empty
+		if err := detachConfigVolumes(configOpts.Devices, detacher); err != nil {
+			return configIDMap, err
+		}
+
+		instanceConfig, err := client.CreateInstanceConfig(context.Background(), instanceID, configOpts)
+		if err != nil {
+			return configIDMap, fmt.Errorf("Error creating Instance Config: %s", err)
+		}
+		configIDMap[instanceConfig.ID] = *instanceConfig
+	}
+	return configIDMap, nil
+
+}
+
+func updateInstanceConfigs(client linodego.Client, d *schema.ResourceData, instance linodego.Instance, tfConfigsOld, tfConfigsNew interface{}, diskIDLabelMap map[string]int) (bool, map[string]int, []*linodego.InstanceConfig, error) {
+	var updatedConfigMap map[string]int
+	var rebootInstance bool
+	var updatedConfigs []*linodego.InstanceConfig
+
+	configs, err := client.ListInstanceConfigs(context.Background(), int(instance.ID), nil)
+	if err != nil {
+		return rebootInstance, updatedConfigMap, updatedConfigs, fmt.Errorf("Error fetching the config for Instance %d: %s", instance.ID, err)
+	}
+
+	configMap := make(map[string]linodego.InstanceConfig, len(configs))
+	for _, config := range configs {
+		if _, duplicate := configMap[config.Label]; duplicate {
+			return rebootInstance, updatedConfigMap, updatedConfigs, fmt.Errorf("Error indexing Instance %d Configs: Label '%s' is assigned to multiple configs", instance.ID, config.Label)
+		}
+		configMap[config.Label] = config
+	}
+
+	oldConfigLabels := make([]string, len(tfConfigsOld.([]interface{})))
+	oldConfigs := make(map[string]map[string]interface{}, len(tfConfigsOld.([]interface{})))
+
+	for _, tfConfigOld := range tfConfigsOld.([]interface{}) {
+		if oldConfig, ok := tfConfigOld.(map[string]interface{}); ok {
+			oldConfigLabels = append(oldConfigLabels, oldConfig["label"].(string))
+			oldConfigs[oldConfig["label"].(string)] = oldConfig
+		}
+	}
+	tfConfigs := tfConfigsNew.([]interface{})
+	if err = applyAccountNetworkHelperDefault(client, d, tfConfigs); err != nil {
+		return rebootInstance, updatedConfigMap, updatedConfigs, err
+	}
+
+	bootConfigLabel := instanceBootConfigLabel(tfConfigs, d.Get("boot_config_label").(string))
+
+	updatedConfigs = make([]*linodego.InstanceConfig, len(tfConfigs))
+	updatedConfigMap = make(map[string]int, len(tfConfigs))
+	for _, tfConfig := range tfConfigs {
+		tfc, _ := tfConfig.(map[string]interface{})
+		label, _ := tfc["label"].(string)
+		rootDevice, err := expandInstanceConfigRootDevice(tfc, oldConfigs[label])
+		if err != nil {
+			return rebootInstance, updatedConfigMap, updatedConfigs, err
+		}
+		if existingConfig, existing := configMap[label]; existing {
+			configUpdateOpts := existingConfig.GetUpdateOptions()
+			configUpdateOpts.Kernel = tfc["kernel"].(string)
+			configUpdateOpts.RunLevel = tfc["run_level"].(string)
+			configUpdateOpts.VirtMode = tfc["virt_mode"].(string)
+			configUpdateOpts.RootDevice = rootDevice
+			configUpdateOpts.Comments = tfc["comments"].(string)
+			configUpdateOpts.MemoryLimit = tfc["memory_limit"].(int)
+			if configUpdateOpts.InitRD, err = expandInstanceConfigInitRD(tfc["initrd"].(string), diskIDLabelMap); err != nil {
+				return rebootInstance, updatedConfigMap, updatedConfigs, err
+			}
+
+			// The kernel and initrd are sent in the same update, so the instance never boots a mismatched pair, and
+			// booting the new pair takes a single reboot however many of them changed
+			if label == bootConfigLabel && instanceConfigKernelChanged(existingConfig, configUpdateOpts) {
+				rebootInstance = true
+			}
+
+			tfcHelpersRaw, helpersFound := tfc["helpers"]
+			if tfcHelpers, ok := tfcHelpersRaw.([]interface{}); helpersFound && ok {
+				helpersMap := tfcHelpers[0].(map[string]interface{})
+				configUpdateOpts.Helpers = &linodego.InstanceConfigHelpers{
+					UpdateDBDisabled:  helpersMap["updatedb_disabled"].(bool),
+					Distro:            helpersMap["distro"].(bool),
+					ModulesDep:        helpersMap["modules_dep"].(bool),
+					Network:           helpersMap["network"].(bool),
+					DevTmpFsAutomount: helpersMap["devtmpfs_automount"].(bool),
+				}
+
+				// Helpers only take effect when the config is booted
+				if label == bootConfigLabel && instanceConfigHelpersChanged(existingConfig.Helpers, configUpdateOpts.Helpers) {
+					rebootInstance = true
+				}
+			}
+
+			tfcDevicesRaw, devicesFound := tfc["devices"]
+			if tfcDevices, ok := tfcDevicesRaw.([]interface{}); devicesFound && ok {
+				devices := tfcDevices[0].(map[string]interface{})
+
+				configUpdateOpts.Devices, err = expandInstanceConfigDeviceMap(devices, diskIDLabelMap)
+
+				if err != nil {
+					return rebootInstance, updatedConfigMap, updatedConfigs, err
+				}
+				if configUpdateOpts.Devices != nil && emptyConfigDeviceMap(*configUpdateOpts.Devices) {
+					configUpdateOpts.Devices = nil
+				}
+			} else {
+				configUpdateOpts.Devices = nil
+			}
+
+			if configUpdateOpts.Devices != nil {
+				detacher := makeVolumeDetacher(client, d)
+
+				if detachErr := detachConfigVolumes(*configUpdateOpts.Devices, detacher); detachErr != nil {
+					return rebootInstance, updatedConfigMap, updatedConfigs, detachErr
+				}
+			}
+
+			updatedConfig, err := client.UpdateInstanceConfig(context.Background(), instance.ID, existingConfig.ID, configUpdateOpts)
+			if err != nil {
+				return rebootInstance, updatedConfigMap, updatedConfigs, fmt.Errorf("Error updating Instance %d Config %d: %s", instance.ID, existingConfig.ID, err)
+			}
+
+			updatedConfigMap[updatedConfig.Label] = updatedConfig.ID
+		} else {
+			detacher := makeVolumeDetacher(client, d)
+
+			diskIDOrdered := orderedInstanceDiskIDs(d.Get("disk").([]interface{}), diskIDLabelMap)
+			configIDMap, err := createInstanceConfigsFromSet(client, instance.ID, []interface{}{tfc}, diskIDLabelMap, diskIDOrdered, detacher)
+			if err != nil {
+				return rebootInstance, updatedConfigMap, updatedConfigs, err
+			}
+			for _, config := range configIDMap {
+				updatedConfigMap[config.Label] = config.ID
+				updatedConfigs = append(updatedConfigs, &config)
+			}
+		}
+	}
+
+	updatedConfigMap, err = deleteInstanceConfigs(client, instance.ID, oldConfigLabels, updatedConfigMap, configMap)
+	if err != nil {
+		return rebootInstance, updatedConfigMap, updatedConfigs, err
+	}
+
+	return rebootInstance, updatedConfigMap, updatedConfigs, nil
+}
+
+// instanceBootConfigLabel returns the label of the config the instance boots with, which is the config labeled
+// bootConfigLabel or the first config when none matches
+func instanceBootConfigLabel(tfConfigs []interface{}, bootConfigLabel string) string {
+	var firstLabel string
+	for i, tfConfig := range tfConfigs {
+		tfc, _ := tfConfig.(map[string]interface{})
+		label, _ := tfc["label"].(string)
+		if label == bootConfigLabel && label != "" {
+			return label
+		}
+		if i == 0 {
+			firstLabel = label
+		}
+	}
+	return firstLabel
+}
+
+// instanceConfigKernelChanged tells whether the update changes the kernel or the initrd disk of a config
+func instanceConfigKernelChanged(current linodego.InstanceConfig, updated linodego.InstanceConfigUpdateOptions) bool {
+	if current.Kernel != updated.Kernel {
+		return true
+	}
+	if current.InitRD == nil || updated.InitRD == nil {
+		return current.InitRD != updated.InitRD
+	}
+	return *current.InitRD != *updated.InitRD
+}
+
+// instanceConfigHelpersChanged tells whether the updated helpers differ from the current helpers of a config
+func instanceConfigHelpersChanged(current, updated *linodego.InstanceConfigHelpers) bool {
+	if updated == nil {
+		return false
+	}
+	if current == nil {
+		return true
+	}
+	return *current != *updated
+}
+
+// instanceRebootNeeded tells whether changes which only take effect at boot require the instance to be rebooted.
+// Instances which are powered off, or which will be, pick up the changes the next time they are booted.
+func instanceRebootNeeded(status linodego.InstanceStatus, booted bool, changesNeedBoot bool) bool {
+	return changesNeedBoot && booted && status == linodego.InstanceRunning
+}
+
+func deleteInstanceConfigs(client linodego.Client, instanceID int, oldConfigLabels []string, newConfigLabels map[string]int, configMap map[string]linodego.InstanceConfig) (map[string]int, error) {
+	for _, oldLabel := range oldConfigLabels {
+		if _, found := newConfigLabels[oldLabel]; !found {
+			if listedConfig, found := configMap[oldLabel]; found {
+				if err := client.DeleteInstanceConfig(context.Background(), instanceID, listedConfig.ID); err != nil {
+					return newConfigLabels, err
+				}
+				delete(newConfigLabels, oldLabel)
+			}
+		}
+	}
+	return newConfigLabels, nil
+}
+
+// validateInstanceImageFitsType verifies that the image of a new instance fits on the disk of its type, so that a type
+// which is too small fails at plan time rather than part way through create. Images which are unknown at plan time, or
+// can not be found, are left for the API to validate.
+func validateInstanceImageFitsType(client linodego.Client, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("image") || !d.NewValueKnown("type") {
+		return nil
+	}
+	imageID, instanceType := d.Get("image").(string), d.Get("type").(string)
+	if imageID == "" || instanceType == "" {
+		return nil
+	}
+	// An existing instance keeps its deployed disks when only the type changes
+	if d.Id() != "" && !d.HasChange("image") {
+		return nil
+	}
+
+	image, err := client.GetImage(context.Background(), imageID)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			log.Printf("[WARN] Image %s was not found, skipping the disk size validation of type %s", imageID, instanceType)
+			return nil
+		}
+		return fmt.Errorf("Error fetching image %s: %s", imageID, err)
+	}
+	linodeType, err := client.GetType(context.Background(), instanceType)
+	if err != nil {
+		return fmt.Errorf("Error fetching Linode type %s: %s", instanceType, err)
+	}
+
+	// swap_size is unknown when it is not configured for a new instance, which gets the API default
+	swapSize := defaultInstanceSwapSize
+	if d.NewValueKnown("swap_size") && d.Get("swap_size").(string) != "" {
+		if swapSize, err = parseSwapSize(d.Get("swap_size").(string)); err != nil {
+			return err
+		}
+	}
+
+	return imageFitsType(image, linodeType, swapSize)
+}
+
+// imageFitsType returns an error if the image and a swap disk of swapSize MB require more disk space than the type
+// provides
+func imageFitsType(image *linodego.Image, linodeType *linodego.LinodeType, swapSize int) error {
+	if image.Size+swapSize <= linodeType.Disk {
+		return nil
+	}
+	if swapSize == 0 {
+		return fmt.Errorf("Error validating image %s: it requires a disk of at least %d MB, but type %s only provides %d MB", image.ID, image.Size, linodeType.ID, linodeType.Disk)
+	}
+	return fmt.Errorf("Error validating image %s: it requires a disk of at least %d MB and swap_size is %d MB, %d MB in total, but type %s only provides %d MB",
+		image.ID, image.Size, swapSize, image.Size+swapSize, linodeType.ID, linodeType.Disk)
+}
+
+// instanceTypeClassGPU is the class of the GPU Linode types, which linodego does not define
+const instanceTypeClassGPU linodego.LinodeTypeClass = "gpu"
+
+// regionCapabilityGPU is the capability of regions which offer GPU Linode types
+const regionCapabilityGPU = "GPU Linodes"
+
+// validateInstanceGPUType verifies that an instance of a GPU type is deployed to a region which offers GPU types, and
+// that its configs boot 64 bit kernels, which the GPU drivers require. Types, regions and kernels which are unknown at
+// plan time, or can not be found, are left for the API to validate.
+func validateInstanceGPUType(client linodego.Client, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("type") || d.Get("type").(string) == "" {
+		return nil
+	}
+	instanceType := d.Get("type").(string)
+
+	linodeType, err := client.GetType(context.Background(), instanceType)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error fetching Linode type %s: %s", instanceType, err)
+	}
+	if linodeType.Class != instanceTypeClassGPU {
+		return nil
+	}
+
+	if regionID := d.Get("region").(string); d.NewValueKnown("region") && regionID != "" {
+		region, err := getRegionCapabilities(client, regionID)
+		if err != nil {
+			if lerr, ok := err.(*linodego.Error); !ok || lerr.Code != 404 {
+				return fmt.Errorf("Error fetching the capabilities of region %s: %s", regionID, err)
+			}
+		} else if !sliceContains(region.Capabilities, regionCapabilityGPU) {
+			return fmt.Errorf("Error validating type %s: GPU types are not available in region %s", instanceType, regionID)
+		}
+	}
+
+	if !d.NewValueKnown("config") {
+		return nil
+	}
+	for _, tfConfig := range d.Get("config").([]interface{}) {
+		tfc, ok := tfConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kernelID, _ := tfc["kernel"].(string)
+		if kernelID == "" {
+			continue
+		}
+
+		kernel, err := client.GetKernel(context.Background(), kernelID)
+		if err != nil {
+			if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+				continue
+			}
+			return fmt.Errorf("Error fetching kernel %s: %s", kernelID, err)
+		}
+		if kernel.Architecture != "x86_64" {
+			return fmt.Errorf("Error validating kernel %s of config %s: GPU type %s requires a 64 bit kernel, but the kernel is %s", kernelID, tfc["label"], instanceType, kernel.Architecture)
+		}
+	}
+	return nil
+}
+
+// validateInstanceConfigMemoryLimits verifies that no config memory_limit exceeds the RAM of the instance type
+func validateInstanceConfigMemoryLimits(client linodego.Client, instanceType string, tfConfigs []interface{}) error {
+	var linodeType *linodego.LinodeType
+
+	for _, tfConfig := range tfConfigs {
+		tfc, ok := tfConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		memoryLimit, _ := tfc["memory_limit"].(int)
+		if memoryLimit == 0 {
+			continue
+		}
+
+		if linodeType == nil {
+			var err error
+			if linodeType, err = client.GetType(context.Background(), instanceType); err != nil {
+				return fmt.Errorf("Error fetching Linode type %s: %s", instanceType, err)
+			}
+		}
+
+		if memoryLimit > linodeType.Memory {
+			return fmt.Errorf("Error validating config %q: memory_limit %d exceeds the %d MB of RAM available to type %s", tfc["label"], memoryLimit, linodeType.Memory, instanceType)
+		}
+	}
+
+	return nil
+}
+
+// findBootConfig returns the config labeled bootConfigLabel, falling back to the first config when no
+// config matches. nil is returned when there are no configs.
+func findBootConfig(instanceConfigs []linodego.InstanceConfig, bootConfigLabel string) *linodego.InstanceConfig {
+	if len(instanceConfigs) == 0 {
+		return nil
+	}
+
+	for i := range instanceConfigs {
+		if instanceConfigs[i].Label == bootConfigLabel {
+			return &instanceConfigs[i]
+		}
+	}
+
+	return &instanceConfigs[0]
+}
+
+// managedInstanceConfigs returns the configs of instanceConfigs labeled by tfConfigs, or only the boot config when
+// tfConfigs is empty, so that the other configs of an instance which ignores extra configs are left untouched
+func managedInstanceConfigs(instanceConfigs []linodego.InstanceConfig, tfConfigs []interface{}, bootConfigLabel string) []linodego.InstanceConfig {
+	if len(tfConfigs) == 0 {
+		if bootConfig := findBootConfig(instanceConfigs, bootConfigLabel); bootConfig != nil {
+			return []linodego.InstanceConfig{*bootConfig}
+		}
+		return nil
+	}
+
+	labels := make(map[string]bool, len(tfConfigs))
+	for _, tfConfig := range tfConfigs {
+		if tfc, ok := tfConfig.(map[string]interface{}); ok {
+			labels[tfc["label"].(string)] = true
+		}
+	}
+
+	var managed []linodego.InstanceConfig
+	for _, config := range instanceConfigs {
+		if labels[config.Label] {
+			managed = append(managed, config)
+		}
+	}
+	return managed
+}
+
+func flattenInstanceConfigDevice(dev *linodego.InstanceConfigDevice, diskLabelIDMap map[int]string) []map[string]interface{} {
+	if dev == nil || emptyInstanceConfigDevice(*dev) {
+		return nil
+	}
+
+	if dev.DiskID > 0 {
+		ret := map[string]interface{}{
+			"disk_id": dev.DiskID,
+		}
+		if label, found := diskLabelIDMap[dev.DiskID]; found {
+			ret["disk_label"] = label
+		}
+		return []map[string]interface{}{ret}
+	}
+	return []map[string]interface{}{{
+		"volume_id": dev.VolumeID,
+	}}
+
+}
+
+// validateInstanceConfigInitRDs checks that each config initrd refers to a disk in the disk list
+func validateInstanceConfigInitRDs(tfDisks []interface{}, tfConfigs []interface{}) error {
+	diskLabels := make(map[string]int, len(tfDisks))
+	for _, tfDisk := range tfDisks {
+		if disk, ok := tfDisk.(map[string]interface{}); ok {
+			diskLabels[disk["label"].(string)] = 0
+		}
+	}
+	for _, tfConfig := range tfConfigs {
+		if config, ok := tfConfig.(map[string]interface{}); ok {
+			if _, err := expandInstanceConfigInitRD(config["initrd"].(string), diskLabels); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// instanceForceNewKeys returns the sorted changed attributes whose schema forces a new resource. List indexes and
+// nested blocks in the keys, such as disk.0.image, are followed to the schema of the nested attribute, while the
+// elements of primitive collections, such as authorized_keys.0, are reported as the collection.
+func instanceForceNewKeys(schemaMap map[string]*schema.Schema, changedKeys []string) []string {
+	found := make(map[string]bool)
+	var forceNewKeys []string
+	for _, key := range changedKeys {
+		addr := strings.Split(key, ".")
+		s, depth := nestedSchema(schemaMap, addr)
+		attribute := strings.Join(addr[:depth], ".")
+		if s != nil && s.ForceNew && !found[attribute] {
+			found[attribute] = true
+			forceNewKeys = append(forceNewKeys, attribute)
+		}
+	}
+	sort.Strings(forceNewKeys)
+	return forceNewKeys
+}
+
+// nestedSchema returns the schema of the attribute at the given address and the number of address parts naming
+// that attribute, or nil if there is none
+func nestedSchema(schemaMap map[string]*schema.Schema, addr []string) (*schema.Schema, int) {
+	s, ok := schemaMap[addr[0]]
+	if !ok {
+		return nil, 0
+	}
+	elem, isResource := s.Elem.(*schema.Resource)
+	// Blocks are followed past their list index or set hash, other attributes end at their name
+	if !isResource || len(addr) < 3 || (s.Type != schema.TypeList && s.Type != schema.TypeSet) {
+		return s, 1
+	}
+	nested, depth := nestedSchema(elem.Schema, addr[2:])
+	if nested == nil {
+		return nil, 0
+	}
+	return nested, depth + 2
+}
+
+// validateInstanceBootDisk verifies that the config a new instance is booted with does not boot from a blank disk.
+// Disks without an image have no operating system, so they can only be used as the root disk of an instance which
+// is left powered off, for example to install an operating system from Rescue Mode.
+func validateInstanceBootDisk(tfDisks []interface{}, tfConfigs []interface{}, bootConfigLabel string) error {
+	var bootConfig map[string]interface{}
+	for _, tfConfig := range tfConfigs {
+		config, ok := tfConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if bootConfig == nil || config["label"].(string) == bootConfigLabel {
+			bootConfig = config
+		}
+	}
+	if bootConfig == nil {
+		return nil
+	}
+
+	rootDiskLabel := instanceConfigRootDiskLabel(bootConfig, tfDisks)
+	for _, tfDisk := range tfDisks {
+		disk, ok := tfDisk.(map[string]interface{})
+		if !ok || disk["label"].(string) != rootDiskLabel {
+			continue
+		}
+		if disk["image"].(string) == "" {
+			return fmt.Errorf("Error validating config %q: its root disk %q is blank and has no operating system to boot; set booted = false to create the Linode powered off", bootConfig["label"], rootDiskLabel)
+		}
+	}
+	return nil
+}
+
+// instanceConfigRootDiskLabel returns the label of the disk a config boots from. The root_device slot is followed
+// when it names one, and sda is used otherwise. Configs without devices are assigned the disks in disk list order.
+func instanceConfigRootDiskLabel(config map[string]interface{}, tfDisks []interface{}) string {
+	slot := "sda"
+	if rootDevice, ok := config["root_device"].(string); ok && strings.HasPrefix(rootDevice, "/dev/sd") {
+		slot = strings.TrimPrefix(rootDevice, "/dev/")
+	}
+
+	devices, _ := config["devices"].([]interface{})
+	if len(devices) == 0 {
+		for i, deviceSlot := range instanceConfigDeviceSlots {
+			if deviceSlot != slot || i >= len(tfDisks) {
+				continue
+			}
+			if disk, ok := tfDisks[i].(map[string]interface{}); ok {
+				return disk["label"].(string)
+			}
+		}
+		return ""
+	}
+
+	for _, device := range devices {
+		deviceMap, ok := device.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		devSlots, _ := deviceMap[slot].([]interface{})
+		for _, rdev := range devSlots {
+			if dev, ok := rdev.(map[string]interface{}); ok {
+				if diskLabel, ok := dev["disk_label"].(string); ok && diskLabel != "" {
+					return diskLabel
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// expandInstanceConfigInitRD returns the ID of the initrd disk with the given label, or nil when no label is given
+func expandInstanceConfigInitRD(label string, diskIDLabelMap map[string]int) (*int, error) {
+	if label == "" {
+		return nil, nil
+	}
+	diskID, found := diskIDLabelMap[label]
+	if !found {
+		return nil, fmt.Errorf("Error mapping initrd disk label %s to ID", label)
+	}
+	return &diskID, nil
+}
+
+func flattenInstanceConfigInitRD(initrd *int, diskLabelIDMap map[int]string) string {
+	if initrd == nil {
+		return ""
+	}
+	return diskLabelIDMap[*initrd]
+}
+
+// expandInstanceConfigDeviceMap converts a terraform linode_instance config.*.devices map to a InstanceConfigDeviceMap for the Linode API
+func expandInstanceConfigDeviceMap(m map[string]interface{}, diskIDLabelMap map[string]int) (deviceMap *linodego.InstanceConfigDeviceMap, err error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	deviceMap = &linodego.InstanceConfigDeviceMap{}
+	for k, rdev := range m {
+		devSlots := rdev.([]interface{})
+		for _, rrdev := range devSlots {
+			dev := rrdev.(map[string]interface{})
+			tDevice := new(linodego.InstanceConfigDevice)
+			if err := assignConfigDevice(tDevice, dev, diskIDLabelMap); err != nil {
+				return nil, err
+			}
+
+			*deviceMap = changeInstanceConfigDevice(*deviceMap, k, tDevice)
+		}
+	}
+	return deviceMap, nil
+}
+
+var instanceConfigDeviceSlots = []string{"sda", "sdb", "sdc", "sdd", "sde", "sdf", "sdg", "sdh"}
+
+// instanceConfigRootDeviceNum returns the device slot number, from 1 (sda), of a root_device. Root devices which are
+// not a device slot, like /dev/root, are the first slot.
+func instanceConfigRootDeviceNum(rootDevice string) int {
+	for i, slot := range instanceConfigDeviceSlots {
+		if rootDevice == "/dev/"+slot {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// expandInstanceConfigRootDevice returns the root_device of a config, taking root_device_num into account. When the
+// config already existed as oldConfig, root_device_num is only used when it changed, since it is computed from the
+// root_device otherwise.
+func expandInstanceConfigRootDevice(config map[string]interface{}, oldConfig map[string]interface{}) (string, error) {
+	rootDevice, _ := config["root_device"].(string)
+	rootDeviceNum, _ := config["root_device_num"].(int)
+	if rootDeviceNum < 1 || rootDeviceNum > len(instanceConfigDeviceSlots) {
+		return rootDevice, nil
+	}
+	numDevice := "/dev/" + instanceConfigDeviceSlots[rootDeviceNum-1]
+
+	rootDeviceChanged := rootDevice != ""
+	if oldConfig != nil {
+		if oldNum, _ := oldConfig["root_device_num"].(int); oldNum == rootDeviceNum {
+			return rootDevice, nil
+		}
+		oldRootDevice, _ := oldConfig["root_device"].(string)
+		rootDeviceChanged = oldRootDevice != rootDevice
+	}
+	if rootDeviceChanged && rootDevice != numDevice {
+		return "", fmt.Errorf("Error validating config %q: root_device %q and root_device_num %d (%s) name different devices", config["label"], rootDevice, rootDeviceNum, numDevice)
+	}
+	return numDevice, nil
+}
+
+// validateInstanceConfigRootDeviceNums verifies that the device slot named by the root_device_num of each config has
+// a disk or volume attached. Configs without devices are assigned the disks in disk list order.
+func validateInstanceConfigRootDeviceNums(tfConfigs []interface{}, tfDisks []interface{}) error {
+	for _, tfConfig := range tfConfigs {
+		config, ok := tfConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rootDeviceNum, _ := config["root_device_num"].(int)
+		if rootDeviceNum < 1 || rootDeviceNum > len(instanceConfigDeviceSlots) {
+			continue
+		}
+		slot := instanceConfigDeviceSlots[rootDeviceNum-1]
+
+		devices, _ := config["devices"].([]interface{})
+		attached := len(devices) == 0 && (len(tfDisks) == 0 || rootDeviceNum <= len(tfDisks))
+		for _, device := range devices {
+			deviceMap, _ := device.(map[string]interface{})
+			devSlots, _ := deviceMap[slot].([]interface{})
+			for _, rdev := range devSlots {
+				dev, _ := rdev.(map[string]interface{})
+				diskLabel, _ := dev["disk_label"].(string)
+				diskID, _ := dev["disk_id"].(int)
+				volumeID, _ := dev["volume_id"].(int)
+				attached = attached || diskLabel != "" || diskID != 0 || volumeID != 0
+			}
+		}
+		if !attached {
+			return fmt.Errorf("Error validating config %q: root_device_num %d is device %s, but no disk or volume is attached to it", config["label"], rootDeviceNum, slot)
+		}
+	}
+	return nil
+}
+
+// orderedInstanceConfigDevices assigns the disks to device slots sda-sdh in the order they are given
+func orderedInstanceConfigDevices(diskIDOrdered []int) (deviceMap linodego.InstanceConfigDeviceMap) {
+	for i, diskID := range diskIDOrdered {
+		if i >= len(instanceConfigDeviceSlots) {
+			break
+		}
+		deviceMap = changeInstanceConfigDevice(deviceMap, instanceConfigDeviceSlots[i], &linodego.InstanceConfigDevice{DiskID: diskID})
+	}
+	return deviceMap
+}
+
+// orderedInstanceDiskIDs returns the IDs of the disks in the order of the disk list
+func orderedInstanceDiskIDs(tfDisks []interface{}, diskIDLabelMap map[string]int) []int {
+	diskIDOrdered := make([]int, 0, len(tfDisks))
+	for _, tfDisk := range tfDisks {
+		if disk, ok := tfDisk.(map[string]interface{}); ok {
+			if diskID, found := diskIDLabelMap[disk["label"].(string)]; found {
+				diskIDOrdered = append(diskIDOrdered, diskID)
+			}
+		}
+	}
+	return diskIDOrdered
+}
+
+// changeInstanceConfigDevice returns a copy of a config device map with the specified disk slot changed to the provided device
+func changeInstanceConfigDevice(deviceMap linodego.InstanceConfigDeviceMap, namedSlot string, device *linodego.InstanceConfigDevice) linodego.InstanceConfigDeviceMap {
+	tDevice := device
+	if tDevice != nil && emptyInstanceConfigDevice(*tDevice) {
+		tDevice = nil
+	}
+	switch namedSlot {
+	case "sda":
+		deviceMap.SDA = tDevice
+	case "sdb":
+		deviceMap.SDB = tDevice
+	case "sdc":
+		deviceMap.SDC = tDevice
+	case "sdd":
+		deviceMap.SDD = tDevice
+	case "sde":
+		deviceMap.SDE = tDevice
+	case "sdf":
+		deviceMap.SDF = tDevice
+	case "sdg":
+		deviceMap.SDG = tDevice
+	case "sdh":
+		deviceMap.SDH = tDevice
+	}
+
+	return deviceMap
+}
+
+// emptyInstanceConfigDevice returns true only when neither the disk or volume have been assigned to a config device
+func emptyInstanceConfigDevice(dev linodego.InstanceConfigDevice) bool {
+	return (dev.DiskID == 0 && dev.VolumeID == 0)
+}
+
+// emptyConfigDeviceMap returns true only when none of the disks in a config device map have been assigned
+func emptyConfigDeviceMap(dmap linodego.InstanceConfigDeviceMap) bool {
+	drives := []*linodego.InstanceConfigDevice{
+		dmap.SDA, dmap.SDB, dmap.SDC, dmap.SDD, dmap.SDE, dmap.SDF, dmap.SDG, dmap.SDH,
+	}
+	empty := true
+	for _, drive := range drives {
+		if drive != nil && !emptyInstanceConfigDevice(*drive) {
+			empty = false
+			break
+		}
+	}
+	return empty
+}
+
+type volumeDetacher func(context.Context, int, string) error
+
+func makeVolumeDetacher(client linodego.Client, d *schema.ResourceData) volumeDetacher {
+	return func(ctx context.Context, volumeID int, reason string) error {
+		log.Printf("[INFO] Detaching Linode Volume %d %s", volumeID, reason)
+		if err := client.DetachVolume(ctx, volumeID); err != nil {
+			return err
+		}
+
+		log.Printf("[INFO] Waiting for Linode Volume %d to detach ...", volumeID)
+		if _, err := client.WaitForVolumeLinodeID(ctx, volumeID, nil, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func expandInstanceConfigDevice(m map[string]interface{}) *linodego.InstanceConfigDevice {
+	var dev *linodego.InstanceConfigDevice
+	// be careful of `disk_label string` in m
+	if diskID, ok := m["disk_id"]; ok && diskID.(int) > 0 {
+		dev = &linodego.InstanceConfigDevice{
+			DiskID: diskID.(int),
+		}
+	} else if volumeID, ok := m["volume_id"]; ok && volumeID.(int) > 0 {
+		dev = &linodego.InstanceConfigDevice{
+			VolumeID: m["volume_id"].(int),
+		}
+	}
+	return dev
+}
+
+func createInstanceDisk(client linodego.Client, instance linodego.Instance, v interface{}, d *schema.ResourceData) (*linodego.InstanceDisk, error) {
+	disk, ok := v.(map[string]interface{})
+
+	if !ok {
+		return nil, fmt.Errorf("Error converting disk from Terraform Set to golang map")
+	}
+
+	diskOpts := linodego.InstanceDiskCreateOptions{
+		Label:      disk["label"].(string),
+		Filesystem: disk["filesystem"].(string),
+		Size:       disk["size"].(int),
+	}
+
+	if readOnly, ok := disk["read_only"].(bool); ok {
+		diskOpts.ReadOnly = readOnly
+	}
+
+	if image, ok := disk["image"]; ok {
+		diskOpts.Image = image.(string)
+
+		if rootPass, ok := disk["root_pass"]; ok && rootPass != "" {
+			diskOpts.RootPass = rootPass.(string)
+		} else {
+			var err error
+			diskOpts.RootPass, err = createRandomRootPassword()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if authorizedKeys, ok := disk["authorized_keys"]; ok {
+			diskOpts.AuthorizedKeys = expandAuthorizedKeys(authorizedKeys.([]interface{}))
+		}
+
+		if authorizedUsers, ok := disk["authorized_users"]; ok {
+			for _, user := range authorizedUsers.([]interface{}) {
+				diskOpts.AuthorizedUsers = append(diskOpts.AuthorizedUsers, user.(string))
+			}
+		}
+
+		if stackscriptID, ok := disk["stackscript_id"]; ok {
+			diskOpts.StackscriptID = stackscriptID.(int)
+		}
+
+		if stackscriptData, ok := disk["stackscript_data"]; ok {
+			for name, value := range stackscriptData.(map[string]interface{}) {
+				diskOpts.StackscriptData[name] = value.(string)
+			}
+		}
+	}
+
+	instanceDisk, err := createInstanceDiskWhenNotBusy(client, instance.ID, diskOpts, int(d.Timeout(schema.TimeoutCreate).Seconds()))
+
+	if err != nil {
+		return nil, fmt.Errorf("Error creating Linode instance %d disk: %s", instance.ID, err)
+	}
+
+	_, err = client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionDiskCreate, instanceDisk.Created, int(d.Timeout(schema.TimeoutCreate).Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("Error waiting for Linode instance %d disk: %s", instanceDisk.ID, err)
+	}
+
+	return instanceDisk, err
+}
+
+// instanceDiskBusyRetries is the number of times a disk creation rejected because the instance is busy is retried
+const instanceDiskBusyRetries = 5
+
+// instanceBusyPollInterval is the delay between checks for the pending jobs of a busy instance
+var instanceBusyPollInterval = 3 * time.Second
+
+// isInstanceBusyError reports whether err is the API rejecting a request because a job of the instance is in progress
+func isInstanceBusyError(err error) bool {
+	lerr, ok := err.(*linodego.Error)
+	if !ok || lerr.Code != 400 {
+		return false
+	}
+	message := strings.ToLower(lerr.Message)
+	return strings.Contains(message, "busy") || strings.Contains(message, "in progress")
+}
+
+// createInstanceDiskWhenNotBusy creates a disk of the instance, retrying when the API reports that the instance is
+// busy with a job which hasn't cleared yet, such as the creation of the previous disk.  Each retry waits for the
+// pending jobs of the instance to finish.
+func createInstanceDiskWhenNotBusy(client linodego.Client, instanceID int, diskOpts linodego.InstanceDiskCreateOptions, timeoutSeconds int) (*linodego.InstanceDisk, error) {
+	for retries := 0; ; retries++ {
+		instanceDisk, err := client.CreateInstanceDisk(context.Background(), instanceID, diskOpts)
+		if err == nil || retries == instanceDiskBusyRetries || !isInstanceBusyError(err) {
+			return instanceDisk, err
+		}
+
+		log.Printf("[INFO] Linode Instance %d is busy, waiting for its pending jobs before creating disk %s", instanceID, diskOpts.Label)
+		if err := waitForInstancePendingJobs(client, instanceID, timeoutSeconds); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForInstancePendingJobs waits until the instance has no scheduled or started events. The events are listed for the
+// instance alone, so the jobs of other entities on the account neither hide its jobs nor keep it waiting.
+func waitForInstancePendingJobs(client linodego.Client, instanceID int, timeoutSeconds int) error {
+	deadline := time.Now().Add(time.Duration(timeoutSeconds) * time.Second)
+	for {
+		events, err := listInstanceEvents(client, instanceID)
+		if err != nil {
+			return fmt.Errorf("Error fetching the events of Instance %d: %s", instanceID, err)
+		}
+		if countPendingInstanceJobs(events) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Error waiting for the pending jobs of Instance %d to finish: timed out after %d seconds", instanceID, timeoutSeconds)
+		}
+		time.Sleep(instanceBusyPollInterval)
+	}
+}
+
+func updateInstanceDisks(client linodego.Client, d *schema.ResourceData, instance linodego.Instance, tfDisksOld interface{}, tfDisksNew interface{}) (bool, map[string]int, error) {
+	var diskIDLabelMap map[string]int
+	var rebootInstance bool
+
+	disks, err := client.ListInstanceDisks(context.Background(), int(instance.ID), nil)
+	if err != nil {
+		return rebootInstance, diskIDLabelMap, fmt.Errorf("Error fetching the disks for Instance %d: %s", instance.ID, err)
+	}
+
+	diskMap := make(map[string]linodego.InstanceDisk, len(disks))
+	for _, disk := range disks {
+		if _, duplicate := diskMap[disk.Label]; duplicate {
+			return rebootInstance, diskIDLabelMap, fmt.Errorf("Error indexing Instance %d Disks: Label '%s' is assigned to multiple disks", instance.ID, disk.Label)
+		}
+		diskMap[disk.Label] = disk
+	}
+
+	oldDiskLabels := make([]string, len(tfDisksOld.([]interface{})))
+
+	for _, tfDiskOld := range tfDisksOld.([]interface{}) {
+		if oldDisk, ok := tfDiskOld.(map[string]interface{}); ok {
+			oldDiskLabels = append(oldDiskLabels, oldDisk["label"].(string))
+		}
+	}
+	tfDisks := tfDisksNew.([]interface{})
+
+	//updatedDisks := make([]*linodego.InstanceDisk, tfDisks.Len())
+	diskIDLabelMap = make(map[string]int, len(tfDisks))
+
+	for _, tfDisk := range tfDisks {
+		tfd := tfDisk.(map[string]interface{})
+
+		labelStr, found := tfd["label"]
+		if !found {
+			return rebootInstance, diskIDLabelMap, fmt.Errorf("Error parsing disk label")
+		}
+
+		label, ok := labelStr.(string)
+		if !ok {
+			return rebootInstance, diskIDLabelMap, fmt.Errorf("Error parsing disk label")
+		}
+
+		existingDisk, existing := diskMap[label]
+
+		if existing {
+			// The only non-destructive change supported is resize, which requires a reboot
+			// Label renames are not supported because this TF provider relies on the label as an identifier
+			// Sizes are only applied when the disks changed, since resizing the instance may have grown a disk which
+			// is not configured and whose size in the state is out of date
+			if d.HasChange("disk") && tfd["size"].(int) != existingDisk.Size {
+				if err := changeInstanceDiskSize(&client, instance, existingDisk, tfd["size"].(int), d); err != nil {
+					return rebootInstance, diskIDLabelMap, err
+				}
+				rebootInstance = true
+			}
+			if strings.Compare(tfd["filesystem"].(string), string(existingDisk.Filesystem)) != 0 {
+				return rebootInstance, diskIDLabelMap, fmt.Errorf("Error updating Instance %d Disk %d: Filesystem changes are not supported ('%s' != '%s')", instance.ID, existingDisk.ID, tfd["filesystem"], existingDisk.Filesystem)
+			}
+			diskIDLabelMap[existingDisk.Label] = existingDisk.ID
+
+		} else {
+			instanceDisk, err := createInstanceDisk(client, instance, tfd, d)
+			if err != nil {
+				return rebootInstance, diskIDLabelMap, err
+			}
+			rebootInstance = true
+			diskIDLabelMap[instanceDisk.Label] = instanceDisk.ID
+		}
+	}
+
+	for _, oldLabel := range oldDiskLabels {
+		if _, found := diskIDLabelMap[oldLabel]; !found {
+			if listedDisk, found := diskMap[oldLabel]; found {
+				if err := client.DeleteInstanceDisk(context.Background(), instance.ID, listedDisk.ID); err != nil {
+					return rebootInstance, diskIDLabelMap, err
+				}
+				_, err = client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionDiskDelete, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds()))
+				if err != nil {
+					return rebootInstance, diskIDLabelMap, fmt.Errorf("Error waiting for Instance %d Disk %d to finish deleting: %s", instance.ID, listedDisk.ID, err)
+				}
+				delete(diskIDLabelMap, oldLabel)
+			}
+		}
+	}
+
+	return rebootInstance, diskIDLabelMap, nil
+}
+
+// getTotalDiskSize returns the number of disks and their total size.
+func getTotalDiskSize(client *linodego.Client, linodeID int) (totalDiskSize int, err error) {
+	disks, err := client.ListInstanceDisks(context.Background(), linodeID, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, disk := range disks {
+		totalDiskSize += disk.Size
+	}
+
+	return totalDiskSize, nil
+}
+
+// getBiggestDisk returns the ID and Size of the largest disk attached to the Linode
+func getBiggestDisk(client *linodego.Client, linodeID int) (biggestDiskID int, biggestDiskSize int, err error) {
+	diskFilter := "{\"+order_by\": \"size\", \"+order\": \"desc\"}"
+	disks, err := client.ListInstanceDisks(context.Background(), linodeID, linodego.NewListOptions(1, diskFilter))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, disk := range disks {
+		// Find Biggest Disk ID & Size
+		if disk.Size > biggestDiskSize {
+			biggestDiskID = disk.ID
+			biggestDiskSize = disk.Size
+		}
+	}
+	return biggestDiskID, biggestDiskSize, nil
+}
+
+// expandAuthorizedKeys returns the SSH public keys to deploy. Each element may contain several keys, one per line,
+// as when reading an authorized_keys file with file(); blank lines and # comments are dropped.
+func expandAuthorizedKeys(keys []interface{}) []string {
+	var authorizedKeys []string
+	for _, key := range keys {
+		for _, line := range strings.Split(key.(string), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			authorizedKeys = append(authorizedKeys, line)
+		}
+	}
+	return authorizedKeys
+}
+
+// sshKeyState hashes a string passed in as an interface
+func sshKeyState(val interface{}) string {
+	return hashString(strings.Join(val.([]string), "\n"))
+}
+
+// instanceDiskLabelMaxLength is the maximum length of a disk label
+const instanceDiskLabelMaxLength = 48
+
+// defaultInstanceSwapSize is the size in MB of the swap disk the API creates when swap_size is not given
+const defaultInstanceSwapSize = 512
+
+// parseSwapSize parses a swap size in MB, which may be given with an MB or GB suffix ("512", "512MB", "2GB").
+// A GB is 1024 MB, as in the disk sizes of Linode types.
+func parseSwapSize(size string) (int, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(value, "GB"):
+		value, multiplier = strings.TrimSuffix(value, "GB"), 1024
+	case strings.HasSuffix(value, "MB"):
+		value = strings.TrimSuffix(value, "MB")
+	}
+
+	mb, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || mb < 0 {
+		return 0, fmt.Errorf("Error parsing swap_size %q: expected a number of MB, optionally with an MB or GB suffix", size)
+	}
+	return mb * multiplier, nil
+}
+
+func validateSwapSize(v interface{}, k string) (ws []string, es []error) {
+	if _, err := parseSwapSize(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%q must be a number of MB, optionally with an MB or GB suffix (e.g. \"512MB\", \"2GB\"), got %q", k, v))
+	}
+	return
+}
+
+// swapSizeState stores a swap size in MB, the way the API reports it
+func swapSizeState(val interface{}) string {
+	mb, err := parseSwapSize(val.(string))
+	if err != nil {
+		return val.(string)
+	}
+	return strconv.Itoa(mb)
+}
+
+// instanceSwapSizeChange returns the swap_size before and after a change, in MB
+func instanceSwapSizeChange(d *schema.ResourceData) (oldSize int, newSize int, err error) {
+	o, n := d.GetChange("swap_size")
+	if o.(string) != "" {
+		if oldSize, err = parseSwapSize(o.(string)); err != nil {
+			return 0, 0, err
+		}
+	}
+	if newSize, err = parseSwapSize(n.(string)); err != nil {
+		return 0, 0, err
+	}
+	return oldSize, newSize, nil
+}
+
+// applyInstanceSwapSize changes the swap disk of an instance deployed from an image to swapSize MB, and reboots the
+// instance into the change when reboot is true and the instance is running
+func applyInstanceSwapSize(client linodego.Client, d *schema.ResourceData, instanceID int, swapSize int, reboot bool) error {
+	instance, err := client.GetInstance(context.Background(), instanceID)
+	if err != nil {
+		return fmt.Errorf("Error fetching data about the current linode: %s", err)
+	}
+
+	changed, err := updateInstanceSwapDisk(client, d, *instance, swapSize)
+	if err != nil {
+		return err
+	}
+
+	if reboot && instanceRebootNeeded(instance.Status, d.Get("booted").(bool), changed) {
+		if err = client.RebootInstance(context.Background(), instance.ID, 0); err != nil {
+			return fmt.Errorf("Error rebooting Instance %d: %s", instance.ID, err)
+		}
+		if _, err = client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionLinodeReboot, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+			return fmt.Errorf("Error waiting for Instance %d to finish rebooting: %s", instance.ID, err)
+		}
+	}
+	return nil
+}
+
+// setInstanceDisksAndConfigs sets the disks and configs of an instance as they are after a swap_size change, so that
+// they are synced against the swap disk which was created or deleted rather than the state from before the change.
+// swap_size conflicts with disk and config blocks, so there are no configured disks or configs to keep.
+func setInstanceDisksAndConfigs(client linodego.Client, d *schema.ResourceData, instanceID int) error {
+	instanceDisks, err := client.ListInstanceDisks(context.Background(), instanceID, nil)
+	if err != nil {
+		return fmt.Errorf("Error fetching the disks for Instance %d: %s", instanceID, err)
+	}
+	instanceConfigs, err := client.ListInstanceConfigs(context.Background(), instanceID, nil)
+	if err != nil {
+		return fmt.Errorf("Error fetching the configs for Instance %d: %s", instanceID, err)
+	}
+
+	disks, _ := flattenInstanceDisks(instanceDisks)
+	if err := d.Set("disk", sortInstanceDisksByState(disks, d.Get("disk").([]interface{}))); err != nil {
+		return fmt.Errorf("Erroring setting Linode Instance disk: %s", err)
+	}
+
+	diskLabelIDMap := make(map[int]string, len(instanceDisks))
+	for _, disk := range instanceDisks {
+		diskLabelIDMap[disk.ID] = disk.Label
+	}
+	if err := d.Set("config", flattenInstanceConfigs(instanceConfigs, diskLabelIDMap)); err != nil {
+		return fmt.Errorf("Erroring setting Linode Instance config: %s", err)
+	}
+	return nil
+}
+
+// updateInstanceSwapDisk resizes the swap disk of an instance to swapSize MB. When the instance has no swap disk one
+// is created and added to the first free device of each config, and when swapSize is 0 the swap disk is removed from
+// the configs and deleted. It returns whether the disks changed, which takes a reboot to take effect.
+func updateInstanceSwapDisk(client linodego.Client, d *schema.ResourceData, instance linodego.Instance, swapSize int) (bool, error) {
+	disks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+	if err != nil {
+		return false, fmt.Errorf("Error fetching the disks for Instance %d: %s", instance.ID, err)
+	}
+
+	var swap *linodego.InstanceDisk
+	for i, disk := range disks {
+		if disk.Filesystem != "swap" {
+			continue
+		}
+		if swap != nil {
+			return false, fmt.Errorf("Error updating swap_size of Instance %d: it has more than one swap disk", instance.ID)
+		}
+		swap = &disks[i]
+	}
+
+	switch {
+	case swap != nil && swapSize > 0:
+		if swap.Size == swapSize {
+			return false, nil
+		}
+		if err := changeInstanceDiskSize(&client, instance, *swap, swapSize, d); err != nil {
+			return false, err
+		}
+	case swap == nil && swapSize > 0:
+		disk, err := createInstanceDisk(client, instance, map[string]interface{}{
+			"label":      fmt.Sprintf("%d MB Swap Image", swapSize),
+			"filesystem": "swap",
+			"size":       swapSize,
+		}, d)
+		if err != nil {
+			return false, err
+		}
+		if err := updateInstanceSwapDevices(client, instance.ID, 0, disk.ID); err != nil {
+			return false, err
+		}
+	case swap != nil:
+		if err := updateInstanceSwapDevices(client, instance.ID, swap.ID, 0); err != nil {
+			return false, err
+		}
+		if err := client.DeleteInstanceDisk(context.Background(), instance.ID, swap.ID); err != nil {
+			return false, fmt.Errorf("Error deleting Instance %d swap Disk %d: %s", instance.ID, swap.ID, err)
+		}
+		if _, err := client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionDiskDelete, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+			return false, fmt.Errorf("Error waiting for Instance %d Disk %d to finish deleting: %s", instance.ID, swap.ID, err)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// updateInstanceSwapDevices replaces the swap disk of the configs of an instance. A removedDiskID of 0 adds the
+// disk to the first free device of each config, and an addedDiskID of 0 only removes the disk.
+func updateInstanceSwapDevices(client linodego.Client, instanceID int, removedDiskID int, addedDiskID int) error {
+	configs, err := client.ListInstanceConfigs(context.Background(), instanceID, nil)
+	if err != nil {
+		return fmt.Errorf("Error fetching the configs for Instance %d: %s", instanceID, err)
+	}
+
+	for _, config := range configs {
+		devices := linodego.InstanceConfigDeviceMap{}
+		if config.Devices != nil {
+			devices = *config.Devices
+		}
+		slots := []**linodego.InstanceConfigDevice{
+			&devices.SDA, &devices.SDB, &devices.SDC, &devices.SDD, &devices.SDE, &devices.SDF, &devices.SDG, &devices.SDH,
+		}
+
+		changed := false
+		for _, slot := range slots {
+			empty := *slot == nil || emptyInstanceConfigDevice(**slot)
+			if removedDiskID == 0 && empty {
+				*slot = &linodego.InstanceConfigDevice{DiskID: addedDiskID}
+				changed = true
+				break
+			}
+			if removedDiskID != 0 && !empty && (*slot).DiskID == removedDiskID {
+				*slot = nil
+				if addedDiskID != 0 {
+					*slot = &linodego.InstanceConfigDevice{DiskID: addedDiskID}
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		updateOpts := config.GetUpdateOptions()
+		updateOpts.Devices = &devices
+		if _, err := client.UpdateInstanceConfig(context.Background(), instanceID, config.ID, updateOpts); err != nil {
+			return fmt.Errorf("Error updating the devices of Instance %d Config %d: %s", instanceID, config.ID, err)
+		}
+	}
+	return nil
+}
+
+// normalizeGroup normalizes a display group for comparison, trimming surrounding whitespace and composing unicode
+// characters (NFC) so that cosmetically identical groups compare equal
+func normalizeGroup(group string) string {
+	return norm.NFC.String(strings.TrimSpace(group))
+}
+
+// instanceTagsGetter reads the tag attributes of an instance from either its state or its planned diff
+type instanceTagsGetter interface {
+	Get(key string) interface{}
+}
+
+// expandInstanceTags returns the tags to apply to an instance, including the tags applied from its group
+func expandInstanceTags(d instanceTagsGetter) []string {
+	tags := expandTags(d.Get("tags").(*schema.Set))
+
+	for _, tag := range instanceGroupTags(d) {
+		if !sliceContains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// flattenInstanceTags returns the tags of an instance without the tags applied from its group by group_tag or
+// group_migration, so those tags are not drift. A group tag which is also listed in tags is kept.
+func flattenInstanceTags(d *schema.ResourceData, instanceTags []string) []string {
+	hidden := []string{}
+	for _, tag := range instanceGroupTags(d) {
+		if !d.Get("tags").(*schema.Set).Contains(tag) {
+			hidden = append(hidden, tag)
+		}
+	}
+	if len(hidden) == 0 {
+		return instanceTags
+	}
+
+	tags := make([]string, 0, len(instanceTags))
+	for _, tag := range instanceTags {
+		if !sliceContains(hidden, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// instanceGroupTags returns the tags applied from the group of an instance: the group mirrored by group_tag, and
+// the group which was migrated to a tag by group_migration
+func instanceGroupTags(d instanceTagsGetter) []string {
+	var tags []string
+	if group := normalizeGroup(d.Get("group").(string)); d.Get("group_tag").(bool) && group != "" {
+		tags = append(tags, group)
+	}
+	if migrated := d.Get("migrated_group").(string); migrated != "" && !sliceContains(tags, migrated) {
+		tags = append(tags, migrated)
+	}
+	return tags
+}
+
+// customizeInstanceGroupMigrationDiff plans the one-time migration of the group of an instance to a tag. The group is
+// recorded in migrated_group, which keeps later changes to the group from being migrated again. Removing
+// group_migration ends the migration, after which the tag is only kept when it is listed in tags.
+func customizeInstanceGroupMigrationDiff(d *schema.ResourceDiff) error {
+	if d.Get("group_migration").(string) == "" {
+		if d.Get("migrated_group").(string) != "" {
+			return d.SetNew("migrated_group", "")
+		}
+		return nil
+	}
+
+	if d.Get("migrated_group").(string) != "" || !d.NewValueKnown("group") {
+		return nil
+	}
+	if group := normalizeGroup(d.Get("group").(string)); group != "" {
+		return d.SetNew("migrated_group", group)
+	}
+	return nil
+}
+
+// clearInstanceGroup removes the display group of an instance. linodego omits an empty group from updates, so
+// the request body is built here.
+func clearInstanceGroup(client linodego.Client, instanceID int) error {
+	body := map[string]interface{}{"group": ""}
+	r, err := client.R(context.Background()).SetBody(body).Put(fmt.Sprintf("linode/instances/%d", instanceID))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error clearing the group of Linode instance %d: %s", instanceID, err)
+	}
+	return nil
+}
+
+// maintenancePolicy is a host maintenance policy, which linodego does not yet support
+type maintenancePolicy struct {
+	Slug      string `json:"slug"`
+	Label     string `json:"label"`
+	IsDefault bool   `json:"is_default"`
+}
+
+func listMaintenancePolicies(client linodego.Client) ([]maintenancePolicy, error) {
+	var policies struct {
+		Data []maintenancePolicy `json:"data"`
+	}
+	r, err := client.R(context.Background()).SetResult(&policies).Get("maintenance/policies")
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return policies.Data, nil
+}
+
+// validateInstanceMaintenancePolicy checks maintenance_policy against the policies the API accepts. Policies are
+// not validated when the API does not list them.
+func validateInstanceMaintenancePolicy(client linodego.Client, d *schema.ResourceDiff) error {
+	policy := d.Get("maintenance_policy").(string)
+	if !d.NewValueKnown("maintenance_policy") || policy == "" {
+		return nil
+	}
+
+	policies, err := listMaintenancePolicies(client)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error listing the Linode maintenance policies: %s", err)
+	}
+
+	slugs := make([]string, len(policies))
+	for i, p := range policies {
+		if p.Slug == policy {
+			return nil
+		}
+		slugs[i] = p.Slug
+	}
+	return fmt.Errorf("Error validating maintenance_policy: %q is not one of the policies the Linode API accepts (%s)", policy, strings.Join(slugs, ", "))
+}
+
+// updateInstanceMaintenancePolicy sets the maintenance policy of an instance, which linodego does not yet support
+func updateInstanceMaintenancePolicy(client linodego.Client, instanceID int, policy string) error {
+	body := map[string]interface{}{"maintenance_policy": policy}
+	r, err := client.R(context.Background()).SetBody(body).Put(fmt.Sprintf("linode/instances/%d", instanceID))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error updating the maintenance policy of Linode instance %d: %s", instanceID, err)
+	}
+	return nil
+}
+
+// cloudInitPollInterval is the time between cloud-init status checks, and the timeout of each SSH connection
+var cloudInitPollInterval = 10 * time.Second
+
+// cloudInitSSHPort is the port of the SSH server the cloud-init wait logs in to
+var cloudInitSSHPort = "22"
+
+// validateInstanceCloudInitWait checks that a new instance which waits for cloud-init is deployed from an Image and
+// booted, since the wait logs in over SSH as root once the instance is running
+func validateInstanceCloudInitWait(d *schema.ResourceDiff) error {
+	if d.Id() != "" || !d.Get("wait_for_cloud_init").(bool) {
+		return nil
+	}
+	if image := d.Get("image").(string); d.NewValueKnown("image") && image == "" {
+		return fmt.Errorf("Error validating wait_for_cloud_init: the Linode Instance must be deployed from an image")
+	}
+	if booted, ok := d.GetOkExists("booted"); (ok && !booted.(bool)) || !d.Get("create_config").(bool) {
+		return fmt.Errorf("Error validating wait_for_cloud_init: the Linode Instance must be booted")
+	}
+	return nil
+}
+
+// validateSSHHostKey checks that a host key is an SSH public key in the authorized_keys format
+func validateSSHHostKey(val interface{}, key string) ([]string, []error) {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(val.(string))); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a public key in the authorized_keys format: %s", key, err)}
+	}
+	return nil, nil
+}
+
+// instanceCloudInitSSHConfig returns the SSH client config the cloud-init wait logs in as root with. It authenticates
+// with privateKey, or with the keys of the SSH agent at SSH_AUTH_SOCK when privateKey is empty, and the returned func
+// closes the connection to the agent. The host key must match hostKey. Without a hostKey, the first host key the
+// instance presents is trusted for the rest of the wait, since the host key of a new instance is not known in advance.
+func instanceCloudInitSSHConfig(privateKey, hostKey string) (*ssh.ClientConfig, func(), error) {
+	config := &ssh.ClientConfig{
+		User:    "root",
+		Timeout: cloudInitPollInterval,
+	}
+
+	closeAgent := func() {}
+	if privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error parsing wait_for_cloud_init_private_key: %s", err)
+		}
+		config.Auth = []ssh.AuthMethod{ssh.PublicKeys(signer)}
+	} else {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, nil, fmt.Errorf("Error connecting to the SSH agent: wait_for_cloud_init_private_key is not set and SSH_AUTH_SOCK is empty")
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error connecting to the SSH agent: %s", err)
 		}
+		config.Auth = []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(conn).Signers)}
+		closeAgent = func() { conn.Close() }
+	}
 
-		instanceConfig, err := client.CreateInstanceConfig(context.Background(), instanceID, configOpts)
+	if hostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
 		if err != nil {
-			return configIDMap, fmt.Errorf("Error creating Instance Config: %s", err)
+			closeAgent()
+			return nil, nil, fmt.Errorf("Error parsing wait_for_cloud_init_host_key: %s", err)
 		}
-		configIDMap[instanceConfig.ID] = *instanceConfig
+		config.HostKeyCallback = ssh.FixedHostKey(key)
+		return config, closeAgent, nil
 	}
-	return configIDMap, nil
 
+	var trusted ssh.PublicKey
+	config.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if trusted == nil {
+			log.Printf("[INFO] Trusting the %s host key %s of %s for the cloud-init wait", key.Type(), ssh.FingerprintSHA256(key), hostname)
+			trusted = key
+			return nil
+		}
+		if !bytes.Equal(key.Marshal(), trusted.Marshal()) {
+			return fmt.Errorf("the host key of %s changed from %s to %s", hostname, ssh.FingerprintSHA256(trusted), ssh.FingerprintSHA256(key))
+		}
+		return nil
+	}
+	return config, closeAgent, nil
 }
 
-func updateInstanceConfigs(client linodego.Client, d *schema.ResourceData, instance linodego.Instance, tfConfigsOld, tfConfigsNew interface{}, diskIDLabelMap map[string]int) (bool, map[string]int, []*linodego.InstanceConfig, error) {
-	var updatedConfigMap map[string]int
-	var rebootInstance bool
-	var updatedConfigs []*linodego.InstanceConfig
+// waitForInstanceCloudInit logs in to address over SSH with config and polls `cloud-init status` until cloud-init is
+// done, returning the last status found, or an empty status when none was. Connections are retried until the SSH
+// server accepts them or the timeout passes, since the SSH server starts some time after the instance boots. It fails
+// when cloud-init reports an error or is disabled.
+func waitForInstanceCloudInit(address string, config *ssh.ClientConfig, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	lastStatus := ""
+	for {
+		status, output, err := instanceCloudInitStatus(address, config)
+		if status != "" {
+			lastStatus = status
+		}
+		switch {
+		case err != nil:
+			log.Printf("[DEBUG] Checking the cloud-init status of %s: %s", address, err)
+		case status == "done":
+			return status, nil
+		case status == "error" || status == "disabled" || status == "not installed":
+			return status, fmt.Errorf("Error waiting for cloud-init on %s: cloud-init is %s: %s", address, status, output)
+		default:
+			log.Printf("[DEBUG] cloud-init on %s is %s", address, status)
+		}
 
-	configs, err := client.ListInstanceConfigs(context.Background(), int(instance.ID), nil)
-	if err != nil {
-		return rebootInstance, updatedConfigMap, updatedConfigs, fmt.Errorf("Error fetching the config for Instance %d: %s", instance.ID, err)
+		if time.Now().Add(cloudInitPollInterval).After(deadline) {
+			if err != nil {
+				return lastStatus, fmt.Errorf("Timed-out waiting for cloud-init on %s: %s", address, err)
+			}
+			return lastStatus, fmt.Errorf("Timed-out waiting for cloud-init on %s: cloud-init is %s", address, status)
+		}
+		time.Sleep(cloudInitPollInterval)
 	}
+}
 
-	configMap := make(map[string]linodego.InstanceConfig, len(configs))
-	for _, config := range configs {
-		if _, duplicate := configMap[config.Label]; duplicate {
-			return rebootInstance, updatedConfigMap, updatedConfigs, fmt.Errorf("Error indexing Instance %d Configs: Label '%s' is assigned to multiple configs", instance.ID, config.Label)
-		}
-		configMap[config.Label] = config
+// customizeInstanceCloudInitStatusDiff replaces an instance whose cloud-init wait did not end with done when it was
+// created, the way Terraform replaces a tainted resource
+func customizeInstanceCloudInitStatusDiff(d *schema.ResourceDiff) error {
+	if status := d.Get("cloud_init_status").(string); d.Id() == "" || status == "" || status == "done" {
+		return nil
 	}
+	if err := d.SetNewComputed("cloud_init_status"); err != nil {
+		return err
+	}
+	return d.ForceNew("cloud_init_status")
+}
 
-	oldConfigLabels := make([]string, len(tfConfigsOld.([]interface{})))
+// instanceCloudInitStatus returns the status reported by `cloud-init status`, such as running or done, and the output
+// of the command
+func instanceCloudInitStatus(address string, config *ssh.ClientConfig) (string, string, error) {
+	client, err := ssh.Dial("tcp", address, config)
+	if err != nil {
+		return "", "", err
+	}
+	defer client.Close()
 
-	for _, tfConfigOld := range tfConfigsOld.([]interface{}) {
-		if oldConfig, ok := tfConfigOld.(map[string]interface{}); ok {
-			oldConfigLabels = append(oldConfigLabels, oldConfig["label"].(string))
+	session, err := client.NewSession()
+	if err != nil {
+		return "", "", err
+	}
+	defer session.Close()
+
+	// cloud-init status exits with a non-zero status when cloud-init failed, which the output reports as well
+	out, err := session.CombinedOutput("cloud-init status")
+	output := strings.TrimSpace(string(out))
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "status:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "status:")), output, nil
 		}
 	}
-	tfConfigs := tfConfigsNew.([]interface{})
-	updatedConfigs = make([]*linodego.InstanceConfig, len(tfConfigs))
-	updatedConfigMap = make(map[string]int, len(tfConfigs))
-	for _, tfConfig := range tfConfigs {
-		tfc, _ := tfConfig.(map[string]interface{})
-		label, _ := tfc["label"].(string)
-		rootDevice, _ := tfc["root_device"].(string)
-		if existingConfig, existing := configMap[label]; existing {
-			configUpdateOpts := existingConfig.GetUpdateOptions()
-			configUpdateOpts.Kernel = tfc["kernel"].(string)
-			configUpdateOpts.RunLevel = tfc["run_level"].(string)
-			configUpdateOpts.VirtMode = tfc["virt_mode"].(string)
-			configUpdateOpts.RootDevice = rootDevice
-			configUpdateOpts.Comments = tfc["comments"].(string)
-			configUpdateOpts.MemoryLimit = tfc["memory_limit"].(int)
-
-			tfcHelpersRaw, helpersFound := tfc["helpers"]
-			if tfcHelpers, ok := tfcHelpersRaw.([]interface{}); helpersFound && ok {
-				helpersMap := tfcHelpers[0].(map[string]interface{})
-				configUpdateOpts.Helpers = &linodego.InstanceConfigHelpers{
-					UpdateDBDisabled:  helpersMap["updatedb_disabled"].(bool),
-					Distro:            helpersMap["distro"].(bool),
-					ModulesDep:        helpersMap["modules_dep"].(bool),
-					Network:           helpersMap["network"].(bool),
-					DevTmpFsAutomount: helpersMap["devtmpfs_automount"].(bool),
-				}
-
-			}
+	if exitErr, ok := err.(*ssh.ExitError); ok && exitErr.ExitStatus() == 127 {
+		return "not installed", output, nil
+	}
+	if err != nil {
+		return "", output, fmt.Errorf("Error running cloud-init status: %s", err)
+	}
+	return "", output, fmt.Errorf("Error parsing the cloud-init status %q", output)
+}
 
-			tfcDevicesRaw, devicesFound := tfc["devices"]
-			if tfcDevices, ok := tfcDevicesRaw.([]interface{}); devicesFound && ok {
-				devices := tfcDevices[0].(map[string]interface{})
+// labelState normalizes a label the way the Linode API does, by trimming surrounding whitespace
+func labelState(val interface{}) string {
+	return strings.TrimSpace(val.(string))
+}
 
-				configUpdateOpts.Devices, err = expandInstanceConfigDeviceMap(devices, diskIDLabelMap)
+// labelHostname derives a legal hostname (RFC 1123) from an instance label. Labels may contain characters, like
+// underscores and periods, which are not allowed in a hostname; these are replaced with hyphens.
+func labelHostname(label string) string {
+	hostname := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(strings.TrimSpace(label)))
 
-				if err != nil {
-					return rebootInstance, updatedConfigMap, updatedConfigs, err
-				}
-				if configUpdateOpts.Devices != nil && emptyConfigDeviceMap(*configUpdateOpts.Devices) {
-					configUpdateOpts.Devices = nil
-				}
-			} else {
-				configUpdateOpts.Devices = nil
-			}
+	if len(hostname) > 63 {
+		hostname = hostname[:63]
+	}
+	hostname = strings.Trim(hostname, "-")
+	if hostname == "" {
+		return "localhost"
+	}
+	return hostname
+}
 
-			if configUpdateOpts.Devices != nil {
-				detacher := makeVolumeDetacher(client, d)
+// rootPasswordState hashes a string passed in as an interface
+func rootPasswordState(val interface{}) string {
+	return hashString(val.(string))
+}
 
-				if detachErr := detachConfigVolumes(*configUpdateOpts.Devices, detacher); detachErr != nil {
-					return rebootInstance, updatedConfigMap, updatedConfigs, detachErr
-				}
-			}
+// hashString hashes a string
+func hashString(key string) string {
+	hash := sha3.Sum512([]byte(key))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
 
-			updatedConfig, err := client.UpdateInstanceConfig(context.Background(), instance.ID, existingConfig.ID, configUpdateOpts)
-			if err != nil {
-				return rebootInstance, updatedConfigMap, updatedConfigs, fmt.Errorf("Error updating Instance %d Config %d: %s", instance.ID, existingConfig.ID, err)
-			}
+func createRandomRootPassword() (string, error) {
+	rawRootPass := make([]byte, 50)
+	_, err := rand.Read(rawRootPass)
+	if err != nil {
+		return "", fmt.Errorf("Failed to generate random password")
+	}
+	rootPass := base64.StdEncoding.EncodeToString(rawRootPass)
+	return rootPass, nil
+}
 
-			updatedConfigMap[updatedConfig.Label] = updatedConfig.ID
-		} else {
-			detacher := makeVolumeDetacher(client, d)
+// changeInstanceType resizes the Linode Instance
+func changeInstanceType(client *linodego.Client, instance *linodego.Instance, targetType string, d *schema.ResourceData) error {
+	// Instance must be either offline or running (with no extra activity) to resize.
+	if instance.Status == linodego.InstanceOffline || instance.Status == linodego.InstanceShuttingDown {
+		if _, err := client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceOffline, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+			return fmt.Errorf("Error waiting for instance %d to go offline: %s", instance.ID, err)
+		}
+	} else {
+		if _, err := client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceRunning, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+			return fmt.Errorf("Error waiting for instance %d readiness: %s", instance.ID, err)
+		}
+	}
 
-			configIDMap, err := createInstanceConfigsFromSet(client, instance.ID, []interface{}{tfc}, diskIDLabelMap, detacher)
-			if err != nil {
-				return rebootInstance, updatedConfigMap, updatedConfigs, err
-			}
-			for _, config := range configIDMap {
-				updatedConfigMap[config.Label] = config.ID
-				updatedConfigs = append(updatedConfigs, &config)
-			}
+	// The API only grows the disk of instances with a single disk, so it is told not to when another disk is targeted
+	expansionTarget := d.Get("disk_expansion_target").(string)
+	if expansionTarget == "" {
+		if err := client.ResizeInstance(context.Background(), instance.ID, targetType); err != nil {
+			return fmt.Errorf("Error resizing instance %d: %s", instance.ID, err)
 		}
+	} else if err := resizeInstanceWithoutDiskExpansion(client, instance.ID, targetType); err != nil {
+		return fmt.Errorf("Error resizing instance %d: %s", instance.ID, err)
 	}
 
-	updatedConfigMap, err = deleteInstanceConfigs(client, instance.ID, oldConfigLabels, updatedConfigMap, configMap)
+	_, err := client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionLinodeResize, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds()))
 	if err != nil {
-		return rebootInstance, updatedConfigMap, updatedConfigs, err
+		return fmt.Errorf("Error waiting for instance %d to finish resizing: %s", instance.ID, err)
 	}
 
-	return rebootInstance, updatedConfigMap, updatedConfigs, nil
+	switch expansionTarget {
+	case "":
+		if diagnostic, err := instanceDiskExpansionDiagnostic(client, instance.ID, targetType); err != nil {
+			log.Printf("[WARN] Could not check the disk space of Linode instance %d after resizing: %s", instance.ID, err)
+		} else if diagnostic != "" {
+			log.Printf("[WARN] Linode instance %d %s", instance.ID, diagnostic)
+		}
+	case "none":
+	default:
+		if err := expandInstanceDisk(client, instance.ID, expansionTarget, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func deleteInstanceConfigs(client linodego.Client, instanceID int, oldConfigLabels []string, newConfigLabels map[string]int, configMap map[string]linodego.InstanceConfig) (map[string]int, error) {
-	for _, oldLabel := range oldConfigLabels {
-		if _, found := newConfigLabels[oldLabel]; !found {
-			if listedConfig, found := configMap[oldLabel]; found {
-				if err := client.DeleteInstanceConfig(context.Background(), instanceID, listedConfig.ID); err != nil {
-					return newConfigLabels, err
-				}
-				delete(newConfigLabels, oldLabel)
-			}
-		}
+// resizeInstanceWithoutDiskExpansion resizes the Linode Instance, leaving its disks unchanged. linodego does not yet
+// support allow_auto_disk_resize.
+func resizeInstanceWithoutDiskExpansion(client *linodego.Client, instanceID int, targetType string) error {
+	body := map[string]interface{}{"type": targetType, "allow_auto_disk_resize": false}
+	r, err := client.R(context.Background()).SetBody(body).Post(fmt.Sprintf("linode/instances/%d/resize", instanceID))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
 	}
-	return newConfigLabels, nil
+	return err
 }
 
-func flattenInstanceConfigDevice(dev *linodego.InstanceConfigDevice, diskLabelIDMap map[int]string) []map[string]interface{} {
-	if dev == nil || emptyInstanceConfigDevice(*dev) {
-		return nil
+// expandInstanceDisk grows a disk of the instance into its unallocated space. The disk is given by label, or is the
+// biggest disk when target is "biggest".
+func expandInstanceDisk(client *linodego.Client, instanceID int, target string, d *schema.ResourceData) error {
+	instance, err := client.GetInstance(context.Background(), instanceID)
+	if err != nil {
+		return fmt.Errorf("Error fetching data about the current linode: %s", err)
 	}
 
-	if dev.DiskID > 0 {
-		ret := map[string]interface{}{
-			"disk_id": dev.DiskID,
+	disks, err := client.ListInstanceDisks(context.Background(), instanceID, nil)
+	if err != nil {
+		return fmt.Errorf("Error fetching the disks for Instance %d: %s", instanceID, err)
+	}
+
+	biggestDiskID := 0
+	if target == "biggest" {
+		if biggestDiskID, _, err = getBiggestDisk(client, instanceID); err != nil {
+			return fmt.Errorf("Error finding the biggest disk of Instance %d: %s", instanceID, err)
 		}
-		if label, found := diskLabelIDMap[dev.DiskID]; found {
-			ret["disk_label"] = label
+	}
+
+	var targetDisk *linodego.InstanceDisk
+	usedSize := 0
+	for i, disk := range disks {
+		usedSize += disk.Size
+		if (biggestDiskID != 0 && disk.ID == biggestDiskID) || (biggestDiskID == 0 && disk.Label == target) {
+			targetDisk = &disks[i]
 		}
-		return []map[string]interface{}{ret}
 	}
-	return []map[string]interface{}{{
-		"volume_id": dev.VolumeID,
-	}}
+	if targetDisk == nil {
+		return fmt.Errorf("Error expanding disk %q of Instance %d: the instance has no such disk", target, instanceID)
+	}
 
+	unallocated := instance.Specs.Disk - usedSize
+	if unallocated <= 0 {
+		return nil
+	}
+	log.Printf("[INFO] Expanding disk %q of Linode instance %d by %d MB", targetDisk.Label, instanceID, unallocated)
+	return changeInstanceDiskSize(client, *instance, *targetDisk, targetDisk.Size+unallocated, d)
 }
 
-// expandInstanceConfigDeviceMap converts a terraform linode_instance config.*.devices map to a InstanceConfigDeviceMap for the Linode API
-func expandInstanceConfigDeviceMap(m map[string]interface{}, diskIDLabelMap map[string]int) (deviceMap *linodego.InstanceConfigDeviceMap, err error) {
-	if len(m) == 0 {
-		return nil, nil
+// instanceDiskExpansionDiagnostic fetches the disks of a resized instance and explains why any unallocated space was not
+// used by its biggest disk
+func instanceDiskExpansionDiagnostic(client *linodego.Client, instanceID int, targetType string) (string, error) {
+	linodeType, err := client.GetType(context.Background(), targetType)
+	if err != nil {
+		return "", err
 	}
-	deviceMap = &linodego.InstanceConfigDeviceMap{}
-	for k, rdev := range m {
-		devSlots := rdev.([]interface{})
-		for _, rrdev := range devSlots {
-			dev := rrdev.(map[string]interface{})
-			tDevice := new(linodego.InstanceConfigDevice)
-			if err := assignConfigDevice(tDevice, dev, diskIDLabelMap); err != nil {
-				return nil, err
-			}
 
-			*deviceMap = changeInstanceConfigDevice(*deviceMap, k, tDevice)
-		}
+	disks, err := client.ListInstanceDisks(context.Background(), instanceID, nil)
+	if err != nil {
+		return "", err
 	}
-	return deviceMap, nil
+
+	biggestDiskID, _, err := getBiggestDisk(client, instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	return diskExpansionDiagnostic(disks, biggestDiskID, linodeType.Disk), nil
 }
 
-// changeInstanceConfigDevice returns a copy of a config device map with the specified disk slot changed to the provided device
-func changeInstanceConfigDevice(deviceMap linodego.InstanceConfigDeviceMap, namedSlot string, device *linodego.InstanceConfigDevice) linodego.InstanceConfigDeviceMap {
-	tDevice := device
-	if tDevice != nil && emptyInstanceConfigDevice(*tDevice) {
-		tDevice = nil
+// diskExpansionDiagnostic describes the disk space left unallocated on an instance with typeDiskSize MB of storage, and
+// why the biggest disk cannot simply be grown into it. An empty string is returned when all space is allocated.
+func diskExpansionDiagnostic(disks []linodego.InstanceDisk, biggestDiskID int, typeDiskSize int) string {
+	usedSize := 0
+	var biggestDisk *linodego.InstanceDisk
+	for i, disk := range disks {
+		usedSize += disk.Size
+		if disk.ID == biggestDiskID {
+			biggestDisk = &disks[i]
+		}
 	}
-	switch namedSlot {
-	case "sda":
-		deviceMap.SDA = tDevice
-	case "sdb":
-		deviceMap.SDB = tDevice
-	case "sdc":
-		deviceMap.SDC = tDevice
-	case "sdd":
-		deviceMap.SDD = tDevice
-	case "sde":
-		deviceMap.SDE = tDevice
-	case "sdf":
-		deviceMap.SDF = tDevice
-	case "sdg":
-		deviceMap.SDG = tDevice
-	case "sdh":
-		deviceMap.SDH = tDevice
+
+	unallocated := typeDiskSize - usedSize
+	if unallocated <= 0 {
+		return ""
 	}
 
-	return deviceMap
+	diagnostic := fmt.Sprintf("has %d MB of unallocated disk space which was not used", unallocated)
+	switch {
+	case biggestDisk == nil:
+		return diagnostic + ": the instance has no disks to expand"
+	case biggestDisk.Filesystem == linodego.FilesystemSwap:
+		return diagnostic + fmt.Sprintf(": the biggest disk %q is swap", biggestDisk.Label)
+	case len(disks) > 1:
+		return diagnostic + fmt.Sprintf(": the instance has %d disks, increase the size of disk %q or another disk to use the space", len(disks), biggestDisk.Label)
+	}
+	return diagnostic + fmt.Sprintf(": increase the size of disk %q to use the space", biggestDisk.Label)
 }
 
-// emptyInstanceConfigDevice returns true only when neither the disk or volume have been assigned to a config device
-func emptyInstanceConfigDevice(dev linodego.InstanceConfigDevice) bool {
-	return (dev.DiskID == 0 && dev.VolumeID == 0)
+// instanceBootedState reports whether the instance is powered on. ok is false while the instance is
+// in a transitional state that is neither booted nor powered off.
+func instanceBootedState(instance linodego.Instance) (booted bool, ok bool) {
+	switch instance.Status {
+	case linodego.InstanceRunning, linodego.InstanceBooting, linodego.InstanceRebooting:
+		return true, true
+	case linodego.InstanceOffline, linodego.InstanceShuttingDown:
+		return false, true
+	}
+	return false, false
 }
 
-// emptyConfigDeviceMap returns true only when none of the disks in a config device map have been assigned
-func emptyConfigDeviceMap(dmap linodego.InstanceConfigDeviceMap) bool {
-	drives := []*linodego.InstanceConfigDevice{
-		dmap.SDA, dmap.SDB, dmap.SDC, dmap.SDD, dmap.SDE, dmap.SDF, dmap.SDG, dmap.SDH,
-	}
-	empty := true
-	for _, drive := range drives {
-		if drive != nil && !emptyInstanceConfigDevice(*drive) {
-			empty = false
-			break
-		}
+// rotateInstanceRootPass resets the root password on the instance's biggest disk, which holds the deployed image.
+// The password can only be reset while the instance is powered off, so a running instance is shut down first and
+// booted again afterwards.
+func rotateInstanceRootPass(client linodego.Client, instanceID int, rootPass string, timeoutSeconds int) error {
+	instance, err := client.GetInstance(context.Background(), instanceID)
+	if err != nil {
+		return fmt.Errorf("Error fetching Linode instance %d: %s", instanceID, err)
 	}
-	return empty
-}
 
-type volumeDetacher func(context.Context, int, string) error
+	diskID, _, err := getBiggestDisk(&client, instanceID)
+	if err != nil {
+		return fmt.Errorf("Error fetching the disks of Linode instance %d: %s", instanceID, err)
+	}
+	if diskID == 0 {
+		return fmt.Errorf("Error resetting root_pass of Linode instance %d: the instance has no disks", instanceID)
+	}
 
-func makeVolumeDetacher(client linodego.Client, d *schema.ResourceData) volumeDetacher {
-	return func(ctx context.Context, volumeID int, reason string) error {
-		log.Printf("[INFO] Detaching Linode Volume %d %s", volumeID, reason)
-		if err := client.DetachVolume(ctx, volumeID); err != nil {
+	wasRunning := instance.Status != linodego.InstanceOffline
+	if wasRunning {
+		if err = applyInstanceBootedState(client, instanceID, false, 0, timeoutSeconds); err != nil {
 			return err
 		}
+	}
 
-		log.Printf("[INFO] Waiting for Linode Volume %d to detach ...", volumeID)
-		if _, err := client.WaitForVolumeLinodeID(ctx, volumeID, nil, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
-			return err
-		}
-		return nil
+	if err = client.PasswordResetInstanceDisk(context.Background(), instanceID, diskID, rootPass); err != nil {
+		return fmt.Errorf("Error resetting root_pass of Linode instance %d disk %d: %s", instanceID, diskID, err)
+	}
+
+	if wasRunning {
+		return applyInstanceBootedState(client, instanceID, true, 0, timeoutSeconds)
 	}
+	return nil
 }
 
-func expandInstanceConfigDevice(m map[string]interface{}) *linodego.InstanceConfigDevice {
-	var dev *linodego.InstanceConfigDevice
-	// be careful of `disk_label string` in m
-	if diskID, ok := m["disk_id"]; ok && diskID.(int) > 0 {
-		dev = &linodego.InstanceConfigDevice{
-			DiskID: diskID.(int),
-		}
-	} else if volumeID, ok := m["volume_id"]; ok && volumeID.(int) > 0 {
-		dev = &linodego.InstanceConfigDevice{
-			VolumeID: m["volume_id"].(int),
-		}
+// validateCloneTarget verifies that a clone_target overwrite was confirmed and does not clone an instance onto itself
+func validateCloneTarget(sourceID int, instanceID int, confirmOverwrite bool) error {
+	if !confirmOverwrite {
+		return fmt.Errorf("Error validating clone_target: cloning Linode %d deletes all disks and configs of this instance; set confirm_overwrite = true to allow it", sourceID)
 	}
-	return dev
+	if sourceID != 0 && sourceID == instanceID {
+		return fmt.Errorf("Error validating clone_target: Linode %d can not be cloned onto itself", sourceID)
+	}
+	return nil
 }
 
-func createInstanceDisk(client linodego.Client, instance linodego.Instance, v interface{}, d *schema.ResourceData) (*linodego.InstanceDisk, error) {
-	disk, ok := v.(map[string]interface{})
+// deleteAllInstanceConfigs deletes every config of an instance
+func deleteAllInstanceConfigs(client linodego.Client, instanceID int) error {
+	configs, err := client.ListInstanceConfigs(context.Background(), instanceID, nil)
+	if err != nil {
+		return fmt.Errorf("Error fetching the configs of Linode instance %d: %s", instanceID, err)
+	}
+	for _, config := range configs {
+		if err = client.DeleteInstanceConfig(context.Background(), instanceID, config.ID); err != nil {
+			return fmt.Errorf("Error deleting config %d of Linode instance %d: %s", config.ID, instanceID, err)
+		}
+	}
+	return nil
+}
 
-	if !ok {
-		return nil, fmt.Errorf("Error converting disk from Terraform Set to golang map")
+// validateInstanceCreateConfig checks that a new instance created without a config is not to be booted or to have
+// the helpers of its config set, since it has no config to boot or to set the helpers of
+func validateInstanceCreateConfig(d *schema.ResourceDiff) error {
+	if d.Id() != "" || d.Get("create_config").(bool) {
+		return nil
+	}
+	if booted, ok := d.GetOkExists("booted"); ok && booted.(bool) {
+		return fmt.Errorf("Error validating create_config: a Linode Instance created without a config can not be booted")
 	}
+	if _, helpersOk := d.GetOk("helpers"); helpersOk {
+		return fmt.Errorf("Error validating create_config: helpers can not be set on a Linode Instance created without a config")
+	}
+	return nil
+}
 
-	diskOpts := linodego.InstanceDiskCreateOptions{
-		Label:      disk["label"].(string),
-		Filesystem: disk["filesystem"].(string),
-		Size:       disk["size"].(int),
+// cloneOntoInstance overwrites an instance with a clone of the source Linode. The instance is shut down and all of its
+// configs and disks are deleted, so the clone only has to fit the instance's disk rather than its free space. The
+// instance is booted afterwards when boot is set.
+func cloneOntoInstance(client linodego.Client, sourceID int, instanceID int, boot bool, timeoutSeconds int) error {
+	if err := applyInstanceBootedState(client, instanceID, false, 0, timeoutSeconds); err != nil {
+		return err
 	}
 
-	if image, ok := disk["image"]; ok {
-		diskOpts.Image = image.(string)
+	if err := deleteAllInstanceConfigs(client, instanceID); err != nil {
+		return err
+	}
 
-		if rootPass, ok := disk["root_pass"]; ok && rootPass != "" {
-			diskOpts.RootPass = rootPass.(string)
-		} else {
-			var err error
-			diskOpts.RootPass, err = createRandomRootPassword()
-			if err != nil {
-				return nil, err
-			}
+	disks, err := client.ListInstanceDisks(context.Background(), instanceID, nil)
+	if err != nil {
+		return fmt.Errorf("Error fetching the disks of Linode instance %d: %s", instanceID, err)
+	}
+	for _, disk := range disks {
+		minDelete := time.Now().Add(-time.Minute)
+		if err = client.DeleteInstanceDisk(context.Background(), instanceID, disk.ID); err != nil {
+			return fmt.Errorf("Error deleting disk %d of Linode instance %d: %s", disk.ID, instanceID, err)
 		}
-
-		if authorizedKeys, ok := disk["authorized_keys"]; ok {
-			for _, sshKey := range authorizedKeys.([]interface{}) {
-				diskOpts.AuthorizedKeys = append(diskOpts.AuthorizedKeys, sshKey.(string))
-			}
+		if _, err = client.WaitForEventFinished(context.Background(), instanceID, linodego.EntityLinode, linodego.ActionDiskDelete, minDelete, timeoutSeconds); err != nil {
+			return fmt.Errorf("Error waiting for Instance %d Disk %d to finish deleting: %s", instanceID, disk.ID, err)
 		}
+	}
 
-		if authorizedUsers, ok := disk["authorized_users"]; ok {
-			for _, user := range authorizedUsers.([]interface{}) {
-				diskOpts.AuthorizedUsers = append(diskOpts.AuthorizedUsers, user.(string))
-			}
-		}
+	minClone := time.Now().Add(-time.Minute)
+	if _, err = client.CloneInstance(context.Background(), sourceID, linodego.InstanceCloneOptions{LinodeID: instanceID}); err != nil {
+		return fmt.Errorf("Error cloning Linode %d onto Linode instance %d: %s", sourceID, instanceID, err)
+	}
+	if _, err = client.WaitForEventFinished(context.Background(), sourceID, linodego.EntityLinode, linodego.ActionLinodeClone, minClone, timeoutSeconds); err != nil {
+		return fmt.Errorf("Error waiting for Linode %d to finish cloning onto Linode instance %d: %s", sourceID, instanceID, err)
+	}
 
-		if stackscriptID, ok := disk["stackscript_id"]; ok {
-			diskOpts.StackscriptID = stackscriptID.(int)
-		}
+	if boot {
+		return applyInstanceBootedState(client, instanceID, true, 0, timeoutSeconds)
+	}
+	return nil
+}
 
-		if stackscriptData, ok := disk["stackscript_data"]; ok {
-			for name, value := range stackscriptData.(map[string]interface{}) {
-				diskOpts.StackscriptData[name] = value.(string)
-			}
-		}
+// shutdownInstanceBeforeDelete gracefully shuts down a running instance, waiting up to timeoutSeconds. Failures are
+// only logged, since the instance is deleted (forcefully powering it off) either way.
+func shutdownInstanceBeforeDelete(client linodego.Client, instanceID int, timeoutSeconds int) {
+	instance, err := client.GetInstance(context.Background(), instanceID)
+	if err != nil || instance.Status != linodego.InstanceRunning {
+		return
 	}
 
-	instanceDisk, err := client.CreateInstanceDisk(context.Background(), instance.ID, diskOpts)
+	if err = client.ShutdownInstance(context.Background(), instanceID); err != nil {
+		log.Printf("[WARN] Error shutting down Linode instance %d before deleting it, deleting it anyway: %s", instanceID, err)
+		return
+	}
+	if _, err = client.WaitForInstanceStatus(context.Background(), instanceID, linodego.InstanceOffline, timeoutSeconds); err != nil {
+		log.Printf("[WARN] Linode instance %d did not shut down within %d seconds, deleting it anyway: %s", instanceID, timeoutSeconds, err)
+	}
+}
 
+// rollbackInstanceCreate deletes an instance which failed to be provisioned after it was created, and clears the ID so
+// the next apply creates it again instead of keeping a partially configured instance. When the instance can't be
+// deleted, the ID is kept so the instance is destroyed as a tainted resource.
+func rollbackInstanceCreate(client linodego.Client, d *schema.ResourceData, createErr error) error {
+	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return nil, fmt.Errorf("Error creating Linode instance %d disk: %s", instance.ID, err)
+		return createErr
 	}
 
-	_, err = client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionDiskCreate, instanceDisk.Created, int(d.Timeout(schema.TimeoutCreate).Seconds()))
-	if err != nil {
-		return nil, fmt.Errorf("Error waiting for Linode instance %d disk: %s", instanceDisk.ID, err)
+	log.Printf("[WARN] deleting Linode instance %d, which failed to be provisioned: %s", id, createErr)
+	if err = client.DeleteInstance(context.Background(), id); err != nil {
+		if lerr, ok := err.(*linodego.Error); !ok || lerr.Code != 404 {
+			return fmt.Errorf("%s; deleting the partially created Linode instance %d also failed: %s", createErr, id, err)
+		}
 	}
 
-	return instanceDisk, err
+	d.SetId("")
+	return createErr
 }
 
-func updateInstanceDisks(client linodego.Client, d *schema.ResourceData, instance linodego.Instance, tfDisksOld interface{}, tfDisksNew interface{}) (bool, map[string]int, error) {
-	var diskIDLabelMap map[string]int
-	var rebootInstance bool
+// instancePowerEventActions are the event actions which boot, shut down or reboot an instance, including reboots
+// by Lassie (the shutdown watchdog) and host maintenance, which linodego does not define
+var instancePowerEventActions = []linodego.EventAction{
+	linodego.ActionLinodeBoot,
+	linodego.ActionLinodeShutdown,
+	linodego.ActionLinodeReboot,
+	linodego.EventAction("lassie_reboot"),
+	linodego.EventAction("host_reboot"),
+}
 
-	disks, err := client.ListInstanceDisks(context.Background(), int(instance.ID), nil)
+// instanceBootEventActions are the power event actions which boot an instance with its boot config
+var instanceBootEventActions = []linodego.EventAction{
+	linodego.ActionLinodeBoot,
+	linodego.ActionLinodeReboot,
+	linodego.EventAction("lassie_reboot"),
+	linodego.EventAction("host_reboot"),
+}
+
+// instancePowerEventsLimit is the number of recent power events kept in the power_events attribute
+const instancePowerEventsLimit = 10
+
+// listInstanceEvents lists the latest page of the events of the instance, newest first. The events are filtered on the
+// instance by the API, so that the events of other entities on a busy account don't push them off the page. The
+// entity is still matched, in case the filter is not applied.
+func listInstanceEvents(client linodego.Client, instanceID int) ([]linodego.Event, error) {
+	filter, _ := json.Marshal(map[string]interface{}{
+		"entity.id":   instanceID,
+		"entity.type": linodego.EntityLinode,
+		"+order_by":   "created",
+		"+order":      "desc",
+	})
+	events, err := client.ListEvents(context.Background(), linodego.NewListOptions(1, string(filter)))
 	if err != nil {
-		return rebootInstance, diskIDLabelMap, fmt.Errorf("Error fetching the disks for Instance %d: %s", instance.ID, err)
+		return nil, err
 	}
 
-	diskMap := make(map[string]linodego.InstanceDisk, len(disks))
-	for _, disk := range disks {
-		if _, duplicate := diskMap[disk.Label]; duplicate {
-			return rebootInstance, diskIDLabelMap, fmt.Errorf("Error indexing Instance %d Disks: Label '%s' is assigned to multiple disks", instance.ID, disk.Label)
+	var instanceEvents []linodego.Event
+	for _, event := range events {
+		if event.Entity == nil || event.Entity.Type != linodego.EntityLinode {
+			continue
 		}
-		diskMap[disk.Label] = disk
+		// Entity IDs are decoded from JSON as float64
+		if entityID, ok := event.Entity.ID.(float64); !ok || int(entityID) != instanceID {
+			continue
+		}
+		instanceEvents = append(instanceEvents, event)
 	}
+	return instanceEvents, nil
+}
 
-	oldDiskLabels := make([]string, len(tfDisksOld.([]interface{})))
-
-	for _, tfDiskOld := range tfDisksOld.([]interface{}) {
-		if oldDisk, ok := tfDiskOld.(map[string]interface{}); ok {
-			oldDiskLabels = append(oldDiskLabels, oldDisk["label"].(string))
+// countPendingInstanceJobs counts the scheduled and started events of events
+func countPendingInstanceJobs(events []linodego.Event) int {
+	pending := 0
+	for _, event := range events {
+		if event.Status == linodego.EventScheduled || event.Status == linodego.EventStarted {
+			pending++
 		}
 	}
-	tfDisks := tfDisksNew.([]interface{})
+	return pending
+}
 
-	//updatedDisks := make([]*linodego.InstanceDisk, tfDisks.Len())
-	diskIDLabelMap = make(map[string]int, len(tfDisks))
+// lastInstanceBoot returns when the most recent boot of events, which are newest first, was created, or nil when
+// events include no boot
+func lastInstanceBoot(events []linodego.Event) *time.Time {
+	for _, event := range events {
+		for _, action := range instanceBootEventActions {
+			if event.Action == action && event.Created != nil {
+				return event.Created
+			}
+		}
+	}
+	return nil
+}
 
-	for _, tfDisk := range tfDisks {
-		tfd := tfDisk.(map[string]interface{})
+// instanceRebootRequired tells whether the boot config of a running instance was updated after the instance last
+// booted, so the changes are not in effect yet. This is not known, and assumed false, when the last boot is not in the
+// latest events. Timestamps are only precise to the second, so an update in the second of the boot is missed.
+func instanceRebootRequired(status linodego.InstanceStatus, bootConfig *linodego.InstanceConfig, lastBoot *time.Time) bool {
+	if status != linodego.InstanceRunning || bootConfig == nil || bootConfig.Updated == nil || lastBoot == nil {
+		return false
+	}
+	return bootConfig.Updated.After(*lastBoot)
+}
 
-		labelStr, found := tfd["label"]
-		if !found {
-			return rebootInstance, diskIDLabelMap, fmt.Errorf("Error parsing disk label")
+// flattenInstancePowerEvents returns the most recent boot, shutdown and reboot events of events, which are newest first
+func flattenInstancePowerEvents(events []linodego.Event) []map[string]interface{} {
+	powerEvents := []map[string]interface{}{}
+	for _, event := range events {
+		if len(powerEvents) == instancePowerEventsLimit {
+			break
 		}
-
-		label, ok := labelStr.(string)
-		if !ok {
-			return rebootInstance, diskIDLabelMap, fmt.Errorf("Error parsing disk label")
+		isPowerEvent := false
+		for _, action := range instancePowerEventActions {
+			isPowerEvent = isPowerEvent || event.Action == action
 		}
-
-		existingDisk, existing := diskMap[label]
-
-		if existing {
-			// The only non-destructive change supported is resize, which requires a reboot
-			// Label renames are not supported because this TF provider relies on the label as an identifier
-			if tfd["size"].(int) != existingDisk.Size {
-				if err := changeInstanceDiskSize(&client, instance, existingDisk, tfd["size"].(int), d); err != nil {
-					return rebootInstance, diskIDLabelMap, err
-				}
-				rebootInstance = true
-			}
-			if strings.Compare(tfd["filesystem"].(string), string(existingDisk.Filesystem)) != 0 {
-				return rebootInstance, diskIDLabelMap, fmt.Errorf("Error updating Instance %d Disk %d: Filesystem changes are not supported ('%s' != '%s')", instance.ID, existingDisk.ID, tfd["filesystem"], existingDisk.Filesystem)
-			}
-			diskIDLabelMap[existingDisk.Label] = existingDisk.ID
-
-		} else {
-			instanceDisk, err := createInstanceDisk(client, instance, tfd, d)
-			if err != nil {
-				return rebootInstance, diskIDLabelMap, err
-			}
-			rebootInstance = true
-			diskIDLabelMap[instanceDisk.Label] = instanceDisk.ID
+		if !isPowerEvent {
+			continue
 		}
-	}
 
-	for _, oldLabel := range oldDiskLabels {
-		if _, found := diskIDLabelMap[oldLabel]; !found {
-			if listedDisk, found := diskMap[oldLabel]; found {
-				if err := client.DeleteInstanceDisk(context.Background(), instance.ID, listedDisk.ID); err != nil {
-					return rebootInstance, diskIDLabelMap, err
-				}
-				_, err = client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionDiskDelete, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds()))
-				if err != nil {
-					return rebootInstance, diskIDLabelMap, fmt.Errorf("Error waiting for Instance %d Disk %d to finish deleting: %s", instance.ID, listedDisk.ID, err)
-				}
-				delete(diskIDLabelMap, oldLabel)
-			}
+		created := ""
+		if event.Created != nil {
+			created = event.Created.Format(time.RFC3339)
 		}
+		powerEvents = append(powerEvents, map[string]interface{}{
+			"action":   string(event.Action),
+			"status":   string(event.Status),
+			"username": event.Username,
+			"created":  created,
+		})
 	}
+	return powerEvents
+}
 
-	return rebootInstance, diskIDLabelMap, nil
+// imageLabelMaxLength is the maximum length of an Image label
+const imageLabelMaxLength = 50
+
+// imageBeforeDeleteLabel returns the label of the Image captured before deleting an instance, the instance label
+// followed by the instance ID, so that it is known before the instance is deleted. The instance label is truncated so
+// that the Image label fits, rather than the API rejecting it, and whether it was truncated is returned as well.
+func imageBeforeDeleteLabel(label string, instanceID int) (string, bool) {
+	suffix := strconv.Itoa(instanceID)
+	truncated := false
+	if maxLength := imageLabelMaxLength - len(suffix) - 1; len(label) > maxLength {
+		label, truncated = label[:maxLength], true
+	}
+	return fmt.Sprintf("%s-%s", label, suffix), truncated
 }
 
-// getTotalDiskSize returns the number of disks and their total size.
-func getTotalDiskSize(client *linodego.Client, linodeID int) (totalDiskSize int, err error) {
-	disks, err := client.ListInstanceDisks(context.Background(), linodeID, nil)
+// instanceRootDiskID returns the ID of the disk the instance boots from, the disk in the device slot of the root_device
+// of its boot config. The largest disk is used when there is no boot config, or its root device is not a disk.
+func instanceRootDiskID(client linodego.Client, instanceID int, bootConfigLabel string) (int, error) {
+	configs, err := client.ListInstanceConfigs(context.Background(), instanceID, nil)
 	if err != nil {
 		return 0, err
 	}
-
-	for _, disk := range disks {
-		totalDiskSize += disk.Size
+	if bootConfig := findBootConfig(configs, bootConfigLabel); bootConfig != nil && bootConfig.Devices != nil {
+		dmap := bootConfig.Devices
+		devices := []*linodego.InstanceConfigDevice{
+			dmap.SDA, dmap.SDB, dmap.SDC, dmap.SDD, dmap.SDE, dmap.SDF, dmap.SDG, dmap.SDH,
+		}
+		if device := devices[instanceConfigRootDeviceNum(bootConfig.RootDevice)-1]; device != nil && device.DiskID != 0 {
+			return device.DiskID, nil
+		}
 	}
 
-	return totalDiskSize, nil
+	diskID, _, err := getBiggestDisk(&client, instanceID)
+	return diskID, err
 }
 
-// getBiggestDisk returns the ID and Size of the largest disk attached to the Linode
-func getBiggestDisk(client *linodego.Client, linodeID int) (biggestDiskID int, biggestDiskSize int, err error) {
-	diskFilter := "{\"+order_by\": \"size\", \"+order\": \"desc\"}"
-	disks, err := client.ListInstanceDisks(context.Background(), linodeID, linodego.NewListOptions(1, diskFilter))
-	if err != nil {
-		return 0, 0, err
-	}
+// imageAvailablePollInterval is the delay between checks of the status of an Image being created
+var imageAvailablePollInterval = 3 * time.Second
 
-	for _, disk := range disks {
-		// Find Biggest Disk ID & Size
-		if disk.Size > biggestDiskSize {
-			biggestDiskID = disk.ID
-			biggestDiskSize = disk.Size
+// waitForImageAvailable waits until the Image has been taken from its disk and is available
+func waitForImageAvailable(client linodego.Client, imageID string, timeoutSeconds int) error {
+	deadline := time.Now().Add(time.Duration(timeoutSeconds) * time.Second)
+	for {
+		var image struct {
+			Status string `json:"status"`
+		}
+		r, err := client.R(context.Background()).SetResult(&image).Get(fmt.Sprintf("images/%s", imageID))
+		if err == nil && r.IsError() {
+			err = linodego.NewError(r)
+		}
+		if err != nil {
+			return fmt.Errorf("Error getting Image %s: %s", imageID, err)
+		}
+		if image.Status == "available" {
+			return nil
 		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Error waiting for Image %s to be available: timed out after %d seconds with status %q", imageID, timeoutSeconds, image.Status)
+		}
+		time.Sleep(imageAvailablePollInterval)
 	}
-	return biggestDiskID, biggestDiskSize, nil
-}
-
-// sshKeyState hashes a string passed in as an interface
-func sshKeyState(val interface{}) string {
-	return hashString(strings.Join(val.([]string), "\n"))
 }
 
-// rootPasswordState hashes a string passed in as an interface
-func rootPasswordState(val interface{}) string {
-	return hashString(val.(string))
-}
+// imageInstanceBeforeDelete captures the root disk of the instance into a private Image labeled by
+// imageBeforeDeleteLabel, waiting for the Image to be available so the disk is not deleted under it. No Image is
+// returned when the instance no longer exists.
+func imageInstanceBeforeDelete(client linodego.Client, instanceID int, label string, bootConfigLabel string, timeoutSeconds int) (*linodego.Image, error) {
+	diskID, err := instanceRootDiskID(client, instanceID, bootConfigLabel)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error fetching the root disk of Linode instance %d: %s", instanceID, err)
+	}
+	if diskID == 0 {
+		return nil, fmt.Errorf("Linode instance %d has no disks to image", instanceID)
+	}
 
-// hashString hashes a string
-func hashString(key string) string {
-	hash := sha3.Sum512([]byte(key))
-	return base64.StdEncoding.EncodeToString(hash[:])
+	imageLabel, truncated := imageBeforeDeleteLabel(label, instanceID)
+	if truncated {
+		log.Printf("[WARN] Truncating the label %q to fit the Image label %q captured before deleting the instance", label, imageLabel)
+	}
+	image, err := client.CreateImage(context.Background(), linodego.ImageCreateOptions{
+		DiskID:      diskID,
+		Label:       imageLabel,
+		Description: fmt.Sprintf("Created by Terraform before deleting Linode instance %d", instanceID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = waitForImageAvailable(client, image.ID, timeoutSeconds); err != nil {
+		return nil, err
+	}
+	return image, nil
 }
 
-func createRandomRootPassword() (string, error) {
-	rawRootPass := make([]byte, 50)
-	_, err := rand.Read(rawRootPass)
+// applyInstanceBootedState boots or shuts down the instance until it reaches the desired power state
+func applyInstanceBootedState(client linodego.Client, instanceID int, booted bool, bootConfig int, timeoutSeconds int) error {
+	instance, err := client.GetInstance(context.Background(), instanceID)
 	if err != nil {
-		return "", fmt.Errorf("Failed to generate random password")
+		return fmt.Errorf("Error fetching Linode instance %d: %s", instanceID, err)
 	}
-	rootPass := base64.StdEncoding.EncodeToString(rawRootPass)
-	return rootPass, nil
-}
 
-// changeInstanceType resizes the Linode Instance
-func changeInstanceType(client *linodego.Client, instance *linodego.Instance, targetType string, d *schema.ResourceData) error {
-	// Instance must be either offline or running (with no extra activity) to resize.
-	if instance.Status == linodego.InstanceOffline || instance.Status == linodego.InstanceShuttingDown {
-		if _, err := client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceOffline, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
-			return fmt.Errorf("Error waiting for instance %d to go offline: %s", instance.ID, err)
+	if booted {
+		if instance.Status == linodego.InstanceRunning {
+			return nil
 		}
-	} else {
-		if _, err := client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceRunning, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
-			return fmt.Errorf("Error waiting for instance %d readiness: %s", instance.ID, err)
+
+		if instance.Status == linodego.InstanceOffline {
+			if err = client.BootInstance(context.Background(), instance.ID, bootConfig); err != nil {
+				return fmt.Errorf("Error booting Linode instance %d: %s", instance.ID, err)
+			}
+		}
+
+		if _, err = client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceRunning, timeoutSeconds); err != nil {
+			return fmt.Errorf("Timed-out waiting for Linode instance %d to boot: %s", instance.ID, err)
 		}
+		return nil
 	}
 
-	if err := client.ResizeInstance(context.Background(), instance.ID, targetType); err != nil {
-		return fmt.Errorf("Error resizing instance %d: %s", instance.ID, err)
+	if instance.Status == linodego.InstanceOffline {
+		return nil
 	}
 
-	_, err := client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionLinodeResize, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds()))
-	if err != nil {
-		return fmt.Errorf("Error waiting for instance %d to finish resizing: %s", instance.ID, err)
+	if instance.Status != linodego.InstanceShuttingDown {
+		// The instance can only be shutdown once it has finished booting
+		if _, err = client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceRunning, timeoutSeconds); err != nil {
+			return fmt.Errorf("Error waiting for Linode instance %d readiness: %s", instance.ID, err)
+		}
+
+		if err = client.ShutdownInstance(context.Background(), instance.ID); err != nil {
+			return fmt.Errorf("Error shutting down Linode instance %d: %s", instance.ID, err)
+		}
 	}
 
+	if _, err = client.WaitForInstanceStatus(context.Background(), instance.ID, linodego.InstanceOffline, timeoutSeconds); err != nil {
+		return fmt.Errorf("Timed-out waiting for Linode instance %d to shutdown: %s", instance.ID, err)
+	}
 	return nil
 }
 
 func changeInstanceDiskSize(client *linodego.Client, instance linodego.Instance, disk linodego.InstanceDisk, targetSize int, d *schema.ResourceData) error {
-	if instance.Specs.Disk > targetSize {
-		client.ResizeInstanceDisk(context.Background(), instance.ID, disk.ID, targetSize)
+	if instance.Specs.Disk >= targetSize {
+		if err := client.ResizeInstanceDisk(context.Background(), instance.ID, disk.ID, targetSize); err != nil {
+			return fmt.Errorf("Error resizing Instance %d Disk %d: %s", instance.ID, disk.ID, err)
+		}
 
 		// Wait for the Disk Resize Operation to Complete
 		// waitForEventComplete(client, instance.ID, "linode_resize", waitMinutes)
@@ -0,0 +1,189 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/linode/linodego"
+)
+
+// taggedEntityPaths are the API paths of the entities which can be tagged, by entity type
+var taggedEntityPaths = map[string]string{
+	"linode":       "linode/instances",
+	"volume":       "volumes",
+	"nodebalancer": "nodebalancers",
+	"domain":       "domains",
+}
+
+// tagsSchema is the schema of the tags attribute shared by all resources which can be tagged
+func tagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateTag},
+		Optional:    true,
+		Description: "An array of tags applied to this object. Tags are for organizational purposes only.",
+	}
+}
+
+// tagsAllSchema is the schema of the tags_all attribute shared by all resources which can be tagged
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Computed:    true,
+		Description: "All tags applied to this object, including the provider's default_tags.",
+	}
+}
+
+// validateTag checks that a tag is between 3 and 50 characters, as the Linode API requires, and that it does not begin
+// or end with whitespace, which the API would not preserve
+func validateTag(v interface{}, k string) (ws []string, es []error) {
+	tag := v.(string)
+	if len(tag) < 3 || len(tag) > 50 {
+		es = append(es, fmt.Errorf("%q must be between 3 and 50 characters, got %q", k, tag))
+	}
+	if strings.TrimSpace(tag) != tag {
+		es = append(es, fmt.Errorf("%q must not begin or end with whitespace, got %q", k, tag))
+	}
+	return
+}
+
+// expandTags returns the tags of a tags set. An empty set is returned as an empty list rather than nil, so that the
+// API removes every tag instead of keeping the current ones.
+func expandTags(tagsSet *schema.Set) []string {
+	tags := []string{}
+	for _, tag := range tagsSet.List() {
+		tags = append(tags, tag.(string))
+	}
+	return tags
+}
+
+// expandResourceTags returns the configured tags of a resource merged with the provider's default_tags
+func expandResourceTags(d *schema.ResourceData, meta interface{}) []string {
+	return mergeDefaultTags(meta, expandTags(d.Get("tags").(*schema.Set)))
+}
+
+// mergeDefaultTags returns tags with the provider's default_tags which it does not already list appended
+func mergeDefaultTags(meta interface{}, tags []string) []string {
+	for _, tag := range meta.(*ProviderMeta).Config.DefaultTags {
+		if !sliceContains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// customizeResourceTagsAllDiff plans tags_all as the configured tags of a resource merged with the provider's
+// default_tags
+func customizeResourceTagsAllDiff(d *schema.ResourceDiff, meta interface{}) error {
+	return customizeTagsAllDiff(d, meta, expandTags(d.Get("tags").(*schema.Set)))
+}
+
+// customizeTagsAllDiff plans tags_all, the tags the entity is to have, as tags merged with the provider's default_tags.
+// Since tags leaves the default tags out, a change to default_tags, or a default tag removed outside of Terraform, is
+// only a change to tags_all, which has the tags of the entity updated.
+func customizeTagsAllDiff(d *schema.ResourceDiff, meta interface{}, tags []string) error {
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+
+	planned := mergeDefaultTags(meta, tags)
+	if d.Id() != "" {
+		added, removed := tagsDiff(expandTags(d.Get("tags_all").(*schema.Set)), planned)
+		if len(added) == 0 && len(removed) == 0 {
+			return nil
+		}
+	}
+	return d.SetNew("tags_all", planned)
+}
+
+// tagsDiff returns the tags which newTags adds to and removes from oldTags, sorted
+func tagsDiff(oldTags, newTags []string) (added, removed []string) {
+	for _, tag := range newTags {
+		if !sliceContains(oldTags, tag) && !sliceContains(added, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range oldTags {
+		if !sliceContains(newTags, tag) && !sliceContains(removed, tag) {
+			removed = append(removed, tag)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// applyTags updates the tags of an entity from oldTags, its current tags, to newTags, and returns the tags the API
+// applied. An update replaces every tag of the entity rather than adding or removing single tags, so the complete
+// newTags list is sent even when only one tag is removed. No update is made when the tags would not change.
+func applyTags(client linodego.Client, entityType string, entityID int, oldTags, newTags []string) ([]string, error) {
+	added, removed := tagsDiff(oldTags, newTags)
+	if len(added) == 0 && len(removed) == 0 {
+		return oldTags, nil
+	}
+
+	path, ok := taggedEntityPaths[entityType]
+	if !ok {
+		return nil, fmt.Errorf("Error updating tags: %s entities cannot be tagged", entityType)
+	}
+
+	log.Printf("[DEBUG] Tagging %s %d: adding %v, removing %v", entityType, entityID, added, removed)
+	body := map[string][]string{"tags": newTags}
+	result := &struct {
+		Tags []string `json:"tags"`
+	}{}
+	r, err := client.R(context.Background()).SetBody(body).SetResult(result).Put(fmt.Sprintf("%s/%d", path, entityID))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error updating tags of %s %d: %s", entityType, entityID, err)
+	}
+	return result.Tags, nil
+}
+
+// getEntityTags returns the current tags of an entity
+func getEntityTags(client linodego.Client, entityType string, entityID int) ([]string, error) {
+	path, ok := taggedEntityPaths[entityType]
+	if !ok {
+		return nil, fmt.Errorf("Error getting tags: %s entities cannot be tagged", entityType)
+	}
+
+	result := &struct {
+		Tags []string `json:"tags"`
+	}{}
+	r, err := client.R(context.Background()).SetResult(result).Get(fmt.Sprintf("%s/%d", path, entityID))
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return result.Tags, nil
+}
+
+// readTags sets the tags of an entity, as returned by the API, in the state. The provider's default_tags are left
+// out of tags so they are not drift, unless they are also configured on the resource, while tags_all lists every tag.
+func readTags(d *schema.ResourceData, meta interface{}, entityType string, entityID int, tags []string) error {
+	defaultTags := meta.(*ProviderMeta).Config.DefaultTags
+	configuredTags := d.Get("tags").(*schema.Set)
+
+	stateTags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !sliceContains(defaultTags, tag) || configuredTags.Contains(tag) {
+			stateTags = append(stateTags, tag)
+		}
+	}
+	if err := d.Set("tags", stateTags); err != nil {
+		return fmt.Errorf("Error setting tags of %s %d: %s", entityType, entityID, err)
+	}
+	if err := d.Set("tags_all", tags); err != nil {
+		return fmt.Errorf("Error setting tags_all of %s %d: %s", entityType, entityID, err)
+	}
+	return nil
+}
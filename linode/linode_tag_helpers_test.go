@@ -0,0 +1,95 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/linode/linodego"
+)
+
+func TestLinodeTags_diff(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		oldTags         []string
+		newTags         []string
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{"add", []string{"tf_test"}, []string{"tf_test", "tf_test_2"}, []string{"tf_test_2"}, nil},
+		{"remove", []string{"tf_test", "tf_test_2"}, []string{"tf_test_2"}, nil, []string{"tf_test"}},
+		{"replace", []string{"tf_test"}, []string{"tf_test_2"}, []string{"tf_test_2"}, []string{"tf_test"}},
+		{"no change", []string{"tf_test", "tf_test_2"}, []string{"tf_test_2", "tf_test"}, nil, nil},
+		{"remove all", []string{"tf_test"}, []string{}, nil, []string{"tf_test"}},
+		{"sorted", nil, []string{"tf_test_b", "tf_test_a"}, []string{"tf_test_a", "tf_test_b"}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			added, removed := tagsDiff(tc.oldTags, tc.newTags)
+			if !reflect.DeepEqual(added, tc.expectedAdded) {
+				t.Errorf("Expected added tags %v, got %v", tc.expectedAdded, added)
+			}
+			if !reflect.DeepEqual(removed, tc.expectedRemoved) {
+				t.Errorf("Expected removed tags %v, got %v", tc.expectedRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestLinodeTags_mockApply(t *testing.T) {
+	api := newMockLinodeAPI(t)
+	client := api.client()
+
+	instance, err := client.CreateInstance(context.Background(), linodego.InstanceCreateOptions{
+		Region: "us-east",
+		Type:   "g6-nanode-1",
+		Label:  "tf_test_tags",
+		Tags:   []string{"tf_test"},
+	})
+	if err != nil {
+		t.Fatalf("Error creating the instance: %s", err)
+	}
+	putCall := fmt.Sprintf("PUT linode/instances/%d", instance.ID)
+
+	for _, tc := range []struct {
+		name     string
+		newTags  []string
+		expected []string
+		puts     int
+	}{
+		{"no change", []string{"tf_test"}, []string{"tf_test"}, 0},
+		{"add", []string{"tf_test", "tf_test_2"}, []string{"tf_test", "tf_test_2"}, 1},
+		{"remove", []string{"tf_test_2"}, []string{"tf_test_2"}, 2},
+		{"remove all", []string{}, []string{}, 3},
+	} {
+		current, err := client.GetInstance(context.Background(), instance.ID)
+		if err != nil {
+			t.Fatalf("Error getting the instance: %s", err)
+		}
+
+		tags, err := applyTags(client, "linode", instance.ID, current.Tags, tc.newTags)
+		if err != nil {
+			t.Fatalf("%s: Error applying tags: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(tags, tc.expected) {
+			t.Errorf("%s: Expected the applied tags to be %v, got %v", tc.name, tc.expected, tags)
+		}
+		if calls := api.callCount(putCall); calls != tc.puts {
+			t.Errorf("%s: Expected %d updates, got %d", tc.name, tc.puts, calls)
+		}
+		if tc.puts > 0 {
+			// The API replaces every tag, so the full list is sent
+			var body map[string][]string
+			if err := api.lastBody(putCall, &body); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(body["tags"], tc.newTags) {
+				t.Errorf("%s: Expected the update to send tags %v, got %v", tc.name, tc.newTags, body["tags"])
+			}
+		}
+	}
+
+	if _, err := applyTags(client, "sshkey", 1, nil, []string{"tf_test"}); err == nil {
+		t.Errorf("Expected tagging an entity which cannot be tagged to fail")
+	}
+}
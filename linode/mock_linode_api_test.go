@@ -0,0 +1,1678 @@
+package linode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/linode/linodego"
+)
+
+// mockLinodeAPIPollDelay is the number of milliseconds the mock-configured client waits between polls.
+const mockLinodeAPIPollDelay = 5
+
+const mockLinodeAPIDateLayout = "2006-01-02T15:04:05"
+
+// mockLinodeAPI is an in-memory implementation of the Linode API endpoints used by the provider.
+// It allows resource CRUD flows to be exercised by `go test` without a Linode account.
+//
+// Jobs complete synchronously: every action is recorded as a finished event and instances move
+// straight to their resulting status.
+type mockLinodeAPI struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	nextID    int
+	instances map[int]*linodego.Instance
+	disks     map[int][]*linodego.InstanceDisk
+	configs   map[int][]*linodego.InstanceConfig
+	ips       map[int][]*linodego.InstanceIP
+	events    []*linodego.Event
+	types     []linodego.LinodeType
+	kernels   []linodego.LinodeKernel
+	images    []linodego.Image
+	// creatingImages are the IDs of the Images which are still being taken, until their status is next fetched
+	creatingImages map[string]bool
+	regions        []linodego.Region
+	settings       accountSettings
+
+	// regionCapabilities are the capabilities reported for each region by ID
+	regionCapabilities map[string][]string
+
+	placementGroups   []placementGroup
+	instancePlacement map[int]int
+	reservedIPs       []*reservedIP
+	ipv6RDNS          map[int]string
+	// ipv6Ranges are the IPv6 ranges routed to each instance
+	ipv6Ranges map[int][]*linodego.IPv6Range
+	// hostUUIDs are the hosts of instances, which are not reported for instances missing from the map
+	hostUUIDs map[int]string
+
+	longviewClients map[int]*longviewClient
+
+	// stackscripts are the StackScripts by ID. Each revision is updated a minute after the previous one.
+	stackscripts map[int]*linodego.Stackscript
+	// configInterfaces are the network interfaces of configs by config ID
+	configInterfaces map[int][]instanceConfigInterface
+
+	// publicIPv4 is the address given to public IPv4 addresses, instead of a generated one, when it is set
+	publicIPv4 string
+
+	// backups are the available backups of instances, which have none unless they are added by a test
+	backups map[int]*linodego.InstanceBackupsResponse
+
+	// diskBusy is the number of disk creations rejected because the instance is busy, before disks are created
+	diskBusy int
+
+	// diskBusyJob is the started job keeping the instance busy after a rejected disk creation, buried under the jobs
+	// of another instance. It finishes the second time it is listed, and disk creations are rejected until then.
+	diskBusyJob       *linodego.Event
+	diskBusyJobListed bool
+
+	// configCreateError is the reason config creations are rejected with, when it is set
+	configCreateError string
+
+	// diskPasswords records the root password last reset on each disk by ID
+	diskPasswords map[int]string
+
+	// tags are the labels of the tags created through the tags endpoint. Tags applied to instances exist too.
+	tags map[string]bool
+
+	// sharedIPs are the addresses of other instances shared with each instance by ID
+	sharedIPs map[int][]string
+
+	// maintenancePolicies are the maintenance policy slugs of instances, which follow the default policy until set
+	maintenancePolicies map[int]string
+
+	// domainRecords are the records of each domain by domain ID. Domains are only added by tests.
+	domainRecords map[int][]*linodego.DomainRecord
+
+	// withoutIPv6 creates instances without an IPv6 SLAAC address
+	withoutIPv6 bool
+	calls       []string
+	bodies      map[string][]byte
+
+	// filter and page are the X-Filter header and page query parameter of the request being routed
+	filter string
+	page   int
+
+	// overrides replace the mock's handling of a "METHOD path" request, e.g.
+	// "POST linode/instances/1/boot".  Overrides are called without holding the mock's lock.
+	overrides map[string]http.HandlerFunc
+}
+
+func newMockLinodeAPI(t *testing.T) *mockLinodeAPI {
+	m := &mockLinodeAPI{
+		nextID:    1000,
+		instances: make(map[int]*linodego.Instance),
+		disks:     make(map[int][]*linodego.InstanceDisk),
+		configs:   make(map[int][]*linodego.InstanceConfig),
+		ips:       make(map[int][]*linodego.InstanceIP),
+		overrides: make(map[string]http.HandlerFunc),
+		bodies:    make(map[string][]byte),
+
+		instancePlacement:   make(map[int]int),
+		ipv6RDNS:            make(map[int]string),
+		ipv6Ranges:          make(map[int][]*linodego.IPv6Range),
+		hostUUIDs:           make(map[int]string),
+		diskPasswords:       make(map[int]string),
+		tags:                make(map[string]bool),
+		domainRecords:       make(map[int][]*linodego.DomainRecord),
+		sharedIPs:           make(map[int][]string),
+		maintenancePolicies: make(map[int]string),
+		longviewClients:     make(map[int]*longviewClient),
+		stackscripts:        make(map[int]*linodego.Stackscript),
+		creatingImages:      make(map[string]bool),
+		configInterfaces:    make(map[int][]instanceConfigInterface),
+		settings:            accountSettings{NetworkHelper: true},
+		types: []linodego.LinodeType{
+			{ID: "g6-nanode-1", Label: "Nanode 1GB", Class: linodego.ClassNanode, Disk: 25600, Memory: 1024, VCPUs: 1, Transfer: 1000, NetworkOut: 1000},
+			{ID: "g6-standard-1", Label: "Linode 2GB", Class: linodego.ClassStandard, Disk: 51200, Memory: 2048, VCPUs: 1, Transfer: 2000, NetworkOut: 2000},
+			{ID: "g6-standard-2", Label: "Linode 4GB", Class: linodego.ClassStandard, Disk: 81920, Memory: 4096, VCPUs: 2, Transfer: 4000, NetworkOut: 4000},
+			{ID: "g1-gpu-rtx6000-1", Label: "Dedicated 32GB + RTX6000 GPU x1", Class: instanceTypeClassGPU, Disk: 655360, Memory: 32768, VCPUs: 8, Transfer: 16000, NetworkOut: 10000},
+		},
+		kernels: []linodego.LinodeKernel{
+			{ID: "linode/latest-64bit", Label: "Latest 64 bit", Architecture: "x86_64", KVM: true, PVOPS: true},
+			{ID: "linode/latest-32bit", Label: "Latest 32 bit", Architecture: "i386", KVM: true, PVOPS: true},
+			{ID: "linode/grub2", Label: "GRUB 2", Architecture: "x86_64", KVM: true},
+			{ID: "linode/direct-disk", Label: "Direct Disk", Architecture: "x86_64", KVM: true},
+		},
+		images: []linodego.Image{
+			{ID: "linode/ubuntu18.04", Label: "Ubuntu 18.04 LTS", Type: "manual", Vendor: "Ubuntu", Size: 2500, IsPublic: true},
+			{ID: "linode/containerlinux", Label: "Container Linux", Type: "manual", Vendor: "CoreOS", Size: 3500, IsPublic: true},
+		},
+		regions: []linodego.Region{
+			{ID: "us-east", Country: "us"},
+			{ID: "us-central", Country: "us"},
+			{ID: "us-west", Country: "us"},
+			{ID: "eu-west", Country: "uk"},
+		},
+		regionCapabilities: map[string][]string{
+			"us-east": {"Linodes", "NodeBalancers", "Block Storage", "GPU Linodes", "Metadata"},
+			"eu-west": {"Linodes", "NodeBalancers"},
+		},
+		placementGroups: []placementGroup{
+			{ID: 1, Label: "pg-us-east", Region: "us-east"},
+			{ID: 2, Label: "pg-us-west", Region: "us-west"},
+		},
+		reservedIPs: []*reservedIP{
+			{Address: "203.0.113.10", Region: "us-east", Reserved: true},
+			{Address: "203.0.113.20", Region: "us-east", Reserved: true},
+			{Address: "203.0.113.30", Region: "us-west", Reserved: true},
+		},
+	}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+// URL is the base URL of the mock API, suitable for the provider's `url` setting
+func (m *mockLinodeAPI) URL() string {
+	return m.server.URL + "/v4"
+}
+
+// providers returns a provider map whose `url` defaults to the mock API and whose client polls
+// without delay.  Test configs need no provider block.
+func (m *mockLinodeAPI) providers() map[string]terraform.ResourceProvider {
+	provider := Provider().(*schema.Provider)
+	provider.Schema["token"].DefaultFunc = schema.EnvDefaultFunc("LINODE_TOKEN", "mock-token")
+	provider.Schema["url"].DefaultFunc = func() (interface{}, error) {
+		return m.URL(), nil
+	}
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		meta, err := providerConfigure(d)
+		if err != nil {
+			return nil, err
+		}
+		providerMeta := meta.(*ProviderMeta)
+		providerMeta.Client.SetPollDelay(mockLinodeAPIPollDelay)
+		return providerMeta, nil
+	}
+	return map[string]terraform.ResourceProvider{
+		"linode": provider,
+	}
+}
+
+// client returns a linodego client for the mock API
+func (m *mockLinodeAPI) client() linodego.Client {
+	client := getLinodeClient("mock-token", m.URL(), "")
+	client.SetPollDelay(mockLinodeAPIPollDelay)
+	return client
+}
+
+// meta returns provider meta for the mock API, for calling resource functions directly
+func (m *mockLinodeAPI) meta() *ProviderMeta {
+	return &ProviderMeta{Client: m.client(), Config: &Config{}}
+}
+
+// callCount returns the number of requests received matching "METHOD path"
+func (m *mockLinodeAPI) callCount(call string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, c := range m.calls {
+		if c == call {
+			count++
+		}
+	}
+	return count
+}
+
+// callIndex returns the position of the first request received matching "METHOD path" after the position from,
+// or -1 if there is none
+func (m *mockLinodeAPI) callIndex(call string, from int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := from + 1; i < len(m.calls); i++ {
+		if m.calls[i] == call {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastBody decodes the body of the most recent "METHOD path" request into v
+func (m *mockLinodeAPI) lastBody(call string, v interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, found := m.bodies[call]
+	if !found {
+		return fmt.Errorf("No %s request was received", call)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// instanceID returns the ID of the only instance in the mock API
+func (m *mockLinodeAPI) instanceID() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id := range m.instances {
+		return id
+	}
+	return 0
+}
+
+// checkInstanceDestroy verifies that no instances remain in the mock API
+func (m *mockLinodeAPI) checkInstanceDestroy(s *terraform.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id := range m.instances {
+		return fmt.Errorf("Linode Instance with id %d still exists", id)
+	}
+	return nil
+}
+
+func (m *mockLinodeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v4"), "/")
+	call := r.Method + " " + path
+
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	override, overridden := m.overrides[call]
+	m.mu.Unlock()
+
+	if overridden {
+		override(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		status, resp := mockAPIError(http.StatusBadRequest, err.Error())
+		writeMockResponse(w, status, resp)
+		return
+	}
+
+	m.mu.Lock()
+	m.bodies[call] = body
+	m.filter = r.Header.Get("X-Filter")
+	m.page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	status, resp := m.route(r.Method, strings.Split(path, "/"), body)
+	m.mu.Unlock()
+
+	writeMockResponse(w, status, resp)
+}
+
+func writeMockResponse(w http.ResponseWriter, status int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if resp == nil {
+		resp = map[string]interface{}{}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func mockAPIError(status int, reason string) (int, interface{}) {
+	return status, map[string]interface{}{
+		"errors": []map[string]string{{"reason": reason}},
+	}
+}
+
+func mockNotFound() (int, interface{}) {
+	return mockAPIError(http.StatusNotFound, "Not found")
+}
+
+func mockPaged(items interface{}) (int, interface{}) {
+	results := reflect.ValueOf(items).Len()
+	return http.StatusOK, map[string]interface{}{
+		"data":    items,
+		"page":    1,
+		"pages":   1,
+		"results": results,
+	}
+}
+
+// mockEventsPageSize is the number of events on each page, the default page size of the API
+const mockEventsPageSize = 100
+
+// mockPagedEvents returns the page of the events, from 1, with mockEventsPageSize events on each page
+func mockPagedEvents(items []*linodego.Event, page int) (int, interface{}) {
+	pageSize := mockEventsPageSize
+	if page < 1 {
+		page = 1
+	}
+	pages := (len(items) + pageSize - 1) / pageSize
+	if pages == 0 {
+		pages = 1
+	}
+	start, end := (page-1)*pageSize, page*pageSize
+	if start > len(items) {
+		start = len(items)
+	}
+	if end > len(items) {
+		end = len(items)
+	}
+	return http.StatusOK, map[string]interface{}{
+		"data":    items[start:end],
+		"page":    page,
+		"pages":   pages,
+		"results": len(items),
+	}
+}
+
+func mockTimestamp() string {
+	return time.Now().UTC().Format(mockLinodeAPIDateLayout)
+}
+
+// matchPath reports whether the path segments match the pattern, where "*" matches any segment
+func matchPath(segs []string, pattern ...string) bool {
+	if len(segs) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockLinodeAPI) route(method string, segs []string, body []byte) (int, interface{}) {
+	if len(segs) >= 3 && segs[0] == "linode" && segs[1] == "instances" {
+		id, err := strconv.Atoi(segs[2])
+		if err != nil {
+			return mockNotFound()
+		}
+		instance, found := m.instances[id]
+		if !found {
+			return mockNotFound()
+		}
+		return m.routeInstance(method, instance, segs[3:], body)
+	}
+
+	switch {
+	case matchPath(segs, "linode", "types") && method == http.MethodGet:
+		return mockPaged(m.types)
+	case matchPath(segs, "linode", "types", "*") && method == http.MethodGet:
+		if linodeType := m.findType(segs[2]); linodeType != nil {
+			return http.StatusOK, linodeType
+		}
+		return mockNotFound()
+	case matchPath(segs, "images", "*", "*") && method == http.MethodGet:
+		imageID := strings.Join(segs[1:], "/")
+		for _, image := range m.images {
+			if image.ID == imageID {
+				var resp map[string]interface{}
+				raw, _ := json.Marshal(image)
+				json.Unmarshal(raw, &resp)
+				resp["status"] = "available"
+				if m.creatingImages[imageID] {
+					resp["status"] = "creating"
+					delete(m.creatingImages, imageID)
+				}
+				return http.StatusOK, resp
+			}
+		}
+		return mockNotFound()
+	case matchPath(segs, "images") && method == http.MethodPost:
+		var opts linodego.ImageCreateOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		for instanceID, disks := range m.disks {
+			for _, disk := range disks {
+				if disk.ID != opts.DiskID {
+					continue
+				}
+				m.nextID++
+				image := linodego.Image{
+					ID:          fmt.Sprintf("private/%d", m.nextID),
+					Label:       opts.Label,
+					Description: opts.Description,
+					Type:        "manual",
+					Size:        disk.Size,
+					CreatedBy:   "mockuser",
+					CreatedStr:  mockTimestamp(),
+				}
+				m.images = append(m.images, image)
+				m.creatingImages[image.ID] = true
+				m.addEvent(m.instances[instanceID], linodego.ActionDiskImagize)
+				return http.StatusOK, image
+			}
+		}
+		return mockAPIError(http.StatusBadRequest, "disk_id not found")
+	case matchPath(segs, "linode", "kernels") && method == http.MethodGet:
+		return mockPaged(m.kernels)
+	case len(segs) > 2 && segs[0] == "linode" && segs[1] == "kernels" && method == http.MethodGet:
+		kernelID := strings.Join(segs[2:], "/")
+		for _, kernel := range m.kernels {
+			if kernel.ID == kernelID {
+				return http.StatusOK, kernel
+			}
+		}
+		return mockNotFound()
+	case matchPath(segs, "regions") && method == http.MethodGet:
+		return mockPaged(m.regions)
+	case matchPath(segs, "regions", "*") && method == http.MethodGet:
+		for _, region := range m.regions {
+			if region.ID == segs[1] {
+				return http.StatusOK, map[string]interface{}{
+					"id":           region.ID,
+					"country":      region.Country,
+					"capabilities": m.regionCapabilities[region.ID],
+				}
+			}
+		}
+		return mockNotFound()
+	case matchPath(segs, "profile") && method == http.MethodGet:
+		return http.StatusOK, &linodego.Profile{UID: 1, Username: "mockuser", Email: "mock@example.com"}
+	case matchPath(segs, "account") && method == http.MethodGet:
+		return http.StatusOK, &linodego.Account{Email: "mock@example.com", FirstName: "Mock", LastName: "Account", Country: "US", Balance: 10}
+	case matchPath(segs, "account", "transfer") && method == http.MethodGet:
+		return http.StatusOK, &accountTransfer{Used: 120, Quota: 2000, Billable: 0}
+	case matchPath(segs, "account", "settings") && method == http.MethodGet:
+		return http.StatusOK, m.settings
+	case matchPath(segs, "maintenance", "policies") && method == http.MethodGet:
+		return mockPaged(mockMaintenancePolicies)
+	case matchPath(segs, "account", "events") && method == http.MethodGet:
+		// Events are filtered on their entity, and paged like the API does, newest first
+		var filter struct {
+			EntityID   *int    `json:"entity.id"`
+			EntityType *string `json:"entity.type"`
+		}
+		json.Unmarshal([]byte(m.filter), &filter)
+		var events []*linodego.Event
+		for i := len(m.events) - 1; i >= 0; i-- {
+			event := m.events[i]
+			if filter.EntityID != nil && (event.Entity == nil || event.Entity.ID != *filter.EntityID) {
+				continue
+			}
+			if filter.EntityType != nil && (event.Entity == nil || string(event.Entity.Type) != *filter.EntityType) {
+				continue
+			}
+			events = append(events, event)
+		}
+		status, resp := mockPagedEvents(events, m.page)
+		for _, event := range resp.(map[string]interface{})["data"].([]*linodego.Event) {
+			if event != m.diskBusyJob {
+				continue
+			}
+			if m.diskBusyJobListed {
+				m.diskBusyJob.Status = linodego.EventFinished
+				m.diskBusyJob, m.diskBusyJobListed = nil, false
+			} else {
+				m.diskBusyJobListed = true
+			}
+			break
+		}
+		return status, resp
+	case matchPath(segs, "linode", "instances") && method == http.MethodGet:
+		ids := make([]int, 0, len(m.instances))
+		for id := range m.instances {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		instances := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
+			instances[i] = m.instanceResponse(m.instances[id])
+		}
+		return mockPaged(instances)
+	case matchPath(segs, "longview", "clients") && method == http.MethodPost:
+		var opts struct {
+			Label string `json:"label"`
+		}
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		m.nextID++
+		client := &longviewClient{
+			ID:          m.nextID,
+			Label:       opts.Label,
+			APIKey:      fmt.Sprintf("MOCK-API-KEY-%d", m.nextID),
+			InstallCode: fmt.Sprintf("MOCK-INSTALL-CODE-%d", m.nextID),
+			Created:     mockTimestamp(),
+		}
+		if client.Label == "" {
+			client.Label = fmt.Sprintf("longview%d", client.ID)
+		}
+		m.longviewClients[client.ID] = client
+		return http.StatusOK, client
+	case matchPath(segs, "longview", "clients", "*"):
+		id, _ := strconv.Atoi(segs[2])
+		client, ok := m.longviewClients[id]
+		if !ok {
+			return mockNotFound()
+		}
+		switch method {
+		case http.MethodGet:
+			return http.StatusOK, client
+		case http.MethodPut:
+			var opts struct {
+				Label string `json:"label"`
+			}
+			if err := json.Unmarshal(body, &opts); err != nil {
+				return mockAPIError(http.StatusBadRequest, err.Error())
+			}
+			client.Label = opts.Label
+			return http.StatusOK, client
+		case http.MethodDelete:
+			delete(m.longviewClients, id)
+			return http.StatusOK, nil
+		}
+		return mockNotFound()
+	case matchPath(segs, "linode", "stackscripts") && method == http.MethodPost:
+		m.nextID++
+		stackscript := &linodego.Stackscript{ID: m.nextID, Username: "mockuser", CreatedStr: mockTimestamp()}
+		if err := m.reviseStackscript(stackscript, body); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		m.stackscripts[stackscript.ID] = stackscript
+		return http.StatusOK, stackscript
+	case matchPath(segs, "linode", "stackscripts", "*"):
+		id, _ := strconv.Atoi(segs[2])
+		stackscript, ok := m.stackscripts[id]
+		if !ok {
+			return mockNotFound()
+		}
+		switch method {
+		case http.MethodGet:
+			return http.StatusOK, stackscript
+		case http.MethodPut:
+			if err := m.reviseStackscript(stackscript, body); err != nil {
+				return mockAPIError(http.StatusBadRequest, err.Error())
+			}
+			return http.StatusOK, stackscript
+		case http.MethodDelete:
+			delete(m.stackscripts, id)
+			return http.StatusOK, nil
+		}
+		return mockNotFound()
+	case matchPath(segs, "placement", "groups", "*") && method == http.MethodGet:
+		if group := m.findPlacementGroup(segs[2]); group != nil {
+			return http.StatusOK, group
+		}
+		return mockNotFound()
+	case matchPath(segs, "placement", "groups", "*", "*") && method == http.MethodPost:
+		group := m.findPlacementGroup(segs[2])
+		if group == nil {
+			return mockNotFound()
+		}
+		var opts struct {
+			Linodes []int `json:"linodes"`
+		}
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		for _, id := range opts.Linodes {
+			instance, found := m.instances[id]
+			if !found {
+				return mockAPIError(http.StatusBadRequest, fmt.Sprintf("Linode %d not found", id))
+			}
+			switch segs[3] {
+			case "assign":
+				if instance.Region != group.Region {
+					return mockAPIError(http.StatusBadRequest, "Linode and Placement Group must be in the same region")
+				}
+				m.instancePlacement[id] = group.ID
+			case "unassign":
+				delete(m.instancePlacement, id)
+			default:
+				return mockNotFound()
+			}
+		}
+		return http.StatusOK, group
+	case matchPath(segs, "networking", "ips", "*"):
+		return m.routeIPAddress(method, segs[2], body)
+	case matchPath(segs, "networking", "reserved", "ips", "*") && method == http.MethodGet:
+		if ip := m.findReservedIP(segs[3]); ip != nil {
+			return http.StatusOK, ip
+		}
+		return mockNotFound()
+	case matchPath(segs, "linode", "instances") && method == http.MethodPost:
+		return m.createInstance(body)
+	case matchPath(segs, "tags") && method == http.MethodPost:
+		return m.createTag(body)
+	case matchPath(segs, "tags", "*"):
+		return m.routeTag(method, segs[1])
+	case len(segs) >= 3 && segs[0] == "domains" && segs[2] == "records":
+		return m.routeDomainRecords(method, segs[1], segs[3:], body)
+	}
+
+	return mockNotFound()
+}
+
+func (m *mockLinodeAPI) createTag(body []byte) (int, interface{}) {
+	var opts linodego.TagCreateOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		return mockAPIError(http.StatusBadRequest, err.Error())
+	}
+	if m.tagExists(opts.Label) {
+		return mockAPIError(http.StatusBadRequest, "A tag with this label already exists")
+	}
+	for _, id := range opts.Linodes {
+		if _, ok := m.instances[id]; !ok {
+			return mockAPIError(http.StatusBadRequest, fmt.Sprintf("Linode %d not found", id))
+		}
+	}
+
+	m.tags[opts.Label] = true
+	for _, id := range opts.Linodes {
+		instance := m.instances[id]
+		if !sliceContains(instance.Tags, opts.Label) {
+			instance.Tags = append(instance.Tags, opts.Label)
+		}
+	}
+	return http.StatusOK, linodego.Tag{Label: opts.Label}
+}
+
+func (m *mockLinodeAPI) routeTag(method string, label string) (int, interface{}) {
+	if !m.tagExists(label) {
+		return mockNotFound()
+	}
+
+	ids := make([]int, 0, len(m.instances))
+	for id, instance := range m.instances {
+		if sliceContains(instance.Tags, label) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	switch method {
+	case http.MethodGet:
+		objects := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
+			objects[i] = map[string]interface{}{"type": "linode", "data": m.instanceResponse(m.instances[id])}
+		}
+		return mockPaged(objects)
+	case http.MethodDelete:
+		for _, id := range ids {
+			instance := m.instances[id]
+			tags := []string{}
+			for _, tag := range instance.Tags {
+				if tag != label {
+					tags = append(tags, tag)
+				}
+			}
+			instance.Tags = tags
+		}
+		delete(m.tags, label)
+		return http.StatusOK, nil
+	}
+	return mockNotFound()
+}
+
+func (m *mockLinodeAPI) routeDomainRecords(method string, domain string, segs []string, body []byte) (int, interface{}) {
+	domainID, err := strconv.Atoi(domain)
+	if err != nil {
+		return mockNotFound()
+	}
+	records, found := m.domainRecords[domainID]
+	if !found {
+		return mockNotFound()
+	}
+
+	switch {
+	case len(segs) == 0 && method == http.MethodGet:
+		return mockPaged(records)
+	case len(segs) == 0 && method == http.MethodPost:
+		var opts linodego.DomainRecordCreateOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		record := &linodego.DomainRecord{
+			ID:       m.nextID,
+			Type:     opts.Type,
+			Name:     opts.Name,
+			Target:   opts.Target,
+			Service:  opts.Service,
+			Protocol: opts.Protocol,
+			TTLSec:   opts.TTLSec,
+			Tag:      opts.Tag,
+		}
+		m.nextID++
+		if opts.Priority != nil {
+			record.Priority = *opts.Priority
+		}
+		if opts.Weight != nil {
+			record.Weight = *opts.Weight
+		}
+		if opts.Port != nil {
+			record.Port = *opts.Port
+		}
+		m.domainRecords[domainID] = append(records, record)
+		return http.StatusOK, record
+	case len(segs) == 1:
+		recordID, err := strconv.Atoi(segs[0])
+		if err != nil {
+			return mockNotFound()
+		}
+		for i, record := range records {
+			if record.ID != recordID {
+				continue
+			}
+			switch method {
+			case http.MethodGet:
+				return http.StatusOK, record
+			case http.MethodPut:
+				var opts linodego.DomainRecordUpdateOptions
+				if err := json.Unmarshal(body, &opts); err != nil {
+					return mockAPIError(http.StatusBadRequest, err.Error())
+				}
+				record.Target = opts.Target
+				record.TTLSec = opts.TTLSec
+				record.Service, record.Protocol, record.Tag = opts.Service, opts.Protocol, opts.Tag
+				if opts.Priority != nil {
+					record.Priority = *opts.Priority
+				}
+				if opts.Weight != nil {
+					record.Weight = *opts.Weight
+				}
+				if opts.Port != nil {
+					record.Port = *opts.Port
+				}
+				return http.StatusOK, record
+			case http.MethodDelete:
+				m.domainRecords[domainID] = append(records[:i:i], records[i+1:]...)
+				return http.StatusOK, nil
+			}
+		}
+	}
+	return mockNotFound()
+}
+
+// tagExists reports whether the tag label was created or is applied to an instance
+func (m *mockLinodeAPI) tagExists(label string) bool {
+	if m.tags[label] {
+		return true
+	}
+	for _, instance := range m.instances {
+		if sliceContains(instance.Tags, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockLinodeAPI) routeInstance(method string, instance *linodego.Instance, segs []string, body []byte) (int, interface{}) {
+	switch {
+	case len(segs) == 0 && method == http.MethodGet:
+		return http.StatusOK, m.instanceResponse(instance)
+	case len(segs) == 0 && method == http.MethodPut:
+		return m.updateInstance(instance, body)
+	case len(segs) == 0 && method == http.MethodDelete:
+		delete(m.instances, instance.ID)
+		delete(m.disks, instance.ID)
+		delete(m.configs, instance.ID)
+		delete(m.ips, instance.ID)
+		delete(m.instancePlacement, instance.ID)
+		for _, ip := range m.reservedIPs {
+			if ip.LinodeID != nil && *ip.LinodeID == instance.ID {
+				ip.LinodeID = nil
+			}
+		}
+		m.addEvent(instance, linodego.ActionLinodeDelete)
+		return http.StatusOK, nil
+	case matchPath(segs, "boot") && method == http.MethodPost:
+		if len(m.configs[instance.ID]) == 0 {
+			return mockAPIError(http.StatusBadRequest, "Linode has no configs")
+		}
+		instance.Status = linodego.InstanceRunning
+		m.addEvent(instance, linodego.ActionLinodeBoot)
+		return http.StatusOK, nil
+	case matchPath(segs, "reboot") && method == http.MethodPost:
+		if len(m.configs[instance.ID]) == 0 {
+			return mockAPIError(http.StatusBadRequest, "Linode has no configs")
+		}
+		instance.Status = linodego.InstanceRunning
+		m.addEvent(instance, linodego.ActionLinodeReboot)
+		return http.StatusOK, nil
+	case matchPath(segs, "shutdown") && method == http.MethodPost:
+		instance.Status = linodego.InstanceOffline
+		m.addEvent(instance, linodego.ActionLinodeShutdown)
+		return http.StatusOK, nil
+	case matchPath(segs, "resize") && method == http.MethodPost:
+		return m.resizeInstance(instance, body)
+	case matchPath(segs, "clone") && method == http.MethodPost:
+		return m.cloneInstance(instance, body)
+	case matchPath(segs, "stats") && method == http.MethodGet:
+		return http.StatusOK, mockInstanceStats(instance)
+	case matchPath(segs, "backups") && method == http.MethodGet:
+		if backups, ok := m.backups[instance.ID]; ok {
+			return http.StatusOK, backups
+		}
+		return http.StatusOK, &linodego.InstanceBackupsResponse{Automatic: []*linodego.InstanceSnapshot{}, Snapshot: &linodego.InstanceBackupSnapshotResponse{}}
+	case matchPath(segs, "backups", "enable") && method == http.MethodPost:
+		instance.Backups.Enabled = true
+		m.addEvent(instance, linodego.ActionBackupsEnable)
+		return http.StatusOK, nil
+	case matchPath(segs, "backups", "cancel") && method == http.MethodPost:
+		instance.Backups.Enabled = false
+		m.addEvent(instance, linodego.ActionBackupsCancel)
+		return http.StatusOK, nil
+	case matchPath(segs, "ips") && method == http.MethodGet:
+		return http.StatusOK, m.instanceIPAddressesResponse(instance)
+	case matchPath(segs, "ips") && method == http.MethodPost:
+		var opts struct {
+			Type    string `json:"type"`
+			Public  bool   `json:"public"`
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		if opts.Address != "" {
+			return m.assignReservedIP(instance, opts.Address)
+		}
+		if !opts.Public {
+			for _, ip := range m.ips[instance.ID] {
+				if !ip.Public {
+					return mockAPIError(http.StatusBadRequest, "Linode already has a private IP")
+				}
+			}
+		}
+		ip := m.addIP(instance, opts.Public)
+		m.addEvent(instance, linodego.ActionLinodeAddIP)
+		return http.StatusOK, ip
+	case matchPath(segs, "ips", "*") && method == http.MethodDelete:
+		return m.removeIP(instance, segs[1])
+	case matchPath(segs, "disks") && method == http.MethodGet:
+		return mockPaged(m.disks[instance.ID])
+	case matchPath(segs, "disks") && method == http.MethodPost:
+		var opts linodego.InstanceDiskCreateOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		if opts.Image != "" && opts.RootPass == "" {
+			return mockAPIError(http.StatusBadRequest, "root_pass is required when deploying an image")
+		}
+		if m.diskBusyJob != nil {
+			return mockAPIError(http.StatusBadRequest, "Linode busy.")
+		}
+		if m.diskBusy > 0 {
+			m.diskBusy--
+			m.addEvent(instance, linodego.ActionDiskCreate)
+			m.diskBusyJob = m.events[len(m.events)-1]
+			m.diskBusyJob.Status = linodego.EventStarted
+			for i := 0; i < mockEventsPageSize+50; i++ {
+				m.addEvent(&linodego.Instance{ID: instance.ID + 1}, linodego.ActionDiskCreate)
+				m.events[len(m.events)-1].Status = linodego.EventStarted
+			}
+			return mockAPIError(http.StatusBadRequest, "Linode busy.")
+		}
+		if m.usedDiskSpace(instance.ID)+opts.Size > instance.Specs.Disk {
+			return mockAPIError(http.StatusBadRequest, "Insufficient space available for a disk of this size")
+		}
+		disk := m.addDisk(instance, opts.Label, opts.Size, opts.Filesystem)
+		m.addEvent(instance, linodego.ActionDiskCreate)
+		return http.StatusOK, disk
+	case matchPath(segs, "disks", "*"):
+		return m.routeInstanceDisk(method, instance, segs[1], segs[2:], body)
+	case matchPath(segs, "disks", "*", "*"):
+		return m.routeInstanceDisk(method, instance, segs[1], segs[2:], body)
+	case matchPath(segs, "configs") && method == http.MethodGet:
+		configs := make([]map[string]interface{}, len(m.configs[instance.ID]))
+		for i, config := range m.configs[instance.ID] {
+			configs[i] = m.configResponse(config)
+		}
+		return mockPaged(configs)
+	case matchPath(segs, "configs") && method == http.MethodPost:
+		if m.configCreateError != "" {
+			return mockAPIError(http.StatusBadRequest, m.configCreateError)
+		}
+		var opts linodego.InstanceConfigCreateOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		return http.StatusOK, m.addConfig(instance, opts)
+	case matchPath(segs, "configs", "*"):
+		return m.routeInstanceConfig(method, instance, segs[1], body)
+	}
+
+	return mockNotFound()
+}
+
+func (m *mockLinodeAPI) routeInstanceDisk(method string, instance *linodego.Instance, diskID string, segs []string, body []byte) (int, interface{}) {
+	id, err := strconv.Atoi(diskID)
+	if err != nil {
+		return mockNotFound()
+	}
+
+	index := -1
+	for i, disk := range m.disks[instance.ID] {
+		if disk.ID == id {
+			index = i
+		}
+	}
+	if index < 0 {
+		return mockNotFound()
+	}
+	disk := m.disks[instance.ID][index]
+
+	switch {
+	case len(segs) == 0 && method == http.MethodGet:
+		return http.StatusOK, disk
+	case len(segs) == 0 && method == http.MethodPut:
+		var opts linodego.InstanceDiskUpdateOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		if opts.Label != "" {
+			disk.Label = opts.Label
+		}
+		disk.UpdatedStr = mockTimestamp()
+		return http.StatusOK, disk
+	case len(segs) == 0 && method == http.MethodDelete:
+		m.disks[instance.ID] = append(m.disks[instance.ID][:index], m.disks[instance.ID][index+1:]...)
+		m.addEvent(instance, linodego.ActionDiskDelete)
+		return http.StatusOK, nil
+	case matchPath(segs, "password") && method == http.MethodPost:
+		var opts struct {
+			Password string `json:"password"`
+		}
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		if instance.Status != linodego.InstanceOffline {
+			return mockAPIError(http.StatusBadRequest, "Linode must be powered off to reset a disk password")
+		}
+		m.diskPasswords[disk.ID] = opts.Password
+		m.addEvent(instance, linodego.ActionPasswordReset)
+		return http.StatusOK, disk
+	case matchPath(segs, "resize") && method == http.MethodPost:
+		var opts struct {
+			Size int `json:"size"`
+		}
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		if m.usedDiskSpace(instance.ID)-disk.Size+opts.Size > instance.Specs.Disk {
+			return mockAPIError(http.StatusBadRequest, "Insufficient space available for a disk of this size")
+		}
+		disk.Size = opts.Size
+		disk.UpdatedStr = mockTimestamp()
+		m.addEvent(instance, linodego.ActionDiskResize)
+		return http.StatusOK, nil
+	}
+
+	return mockNotFound()
+}
+
+func (m *mockLinodeAPI) routeInstanceConfig(method string, instance *linodego.Instance, configID string, body []byte) (int, interface{}) {
+	id, err := strconv.Atoi(configID)
+	if err != nil {
+		return mockNotFound()
+	}
+
+	index := -1
+	for i, config := range m.configs[instance.ID] {
+		if config.ID == id {
+			index = i
+		}
+	}
+	if index < 0 {
+		return mockNotFound()
+	}
+	config := m.configs[instance.ID][index]
+
+	switch method {
+	case http.MethodGet:
+		return http.StatusOK, config
+	case http.MethodPut:
+		var opts linodego.InstanceConfigUpdateOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+		if opts.Label != "" {
+			config.Label = opts.Label
+		}
+		if opts.Devices != nil {
+			config.Devices = opts.Devices
+		}
+		if opts.Helpers != nil {
+			config.Helpers = opts.Helpers
+		}
+		if opts.Kernel != "" {
+			config.Kernel = opts.Kernel
+		}
+		if opts.RootDevice != "" {
+			config.RootDevice = opts.RootDevice
+		}
+		if opts.RunLevel != "" {
+			config.RunLevel = opts.RunLevel
+		}
+		if opts.VirtMode != "" {
+			config.VirtMode = opts.VirtMode
+		}
+		config.Comments = opts.Comments
+		config.MemoryLimit = opts.MemoryLimit
+		config.InitRD = opts.InitRD
+		config.UpdatedStr = mockTimestamp()
+		return http.StatusOK, config
+	case http.MethodDelete:
+		m.configs[instance.ID] = append(m.configs[instance.ID][:index], m.configs[instance.ID][index+1:]...)
+		return http.StatusOK, nil
+	}
+
+	return mockNotFound()
+}
+
+func (m *mockLinodeAPI) createInstance(body []byte) (int, interface{}) {
+	var opts linodego.InstanceCreateOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		return mockAPIError(http.StatusBadRequest, err.Error())
+	}
+
+	linodeType := m.findType(opts.Type)
+	if linodeType == nil {
+		return mockAPIError(http.StatusBadRequest, "type is not a valid Linode type")
+	}
+
+	regionFound := false
+	for _, region := range m.regions {
+		regionFound = regionFound || region.ID == opts.Region
+	}
+	if !regionFound {
+		return mockAPIError(http.StatusBadRequest, "region is not valid")
+	}
+
+	if opts.Image != "" && opts.RootPass == "" {
+		return mockAPIError(http.StatusBadRequest, "root_pass is required when deploying an image")
+	}
+
+	m.nextID++
+	now := mockTimestamp()
+	instance := &linodego.Instance{
+		CreatedStr: now,
+		UpdatedStr: now,
+		ID:         m.nextID,
+		Region:     opts.Region,
+		Alerts: &linodego.InstanceAlert{
+			CPU:           90 * linodeType.VCPUs,
+			IO:            10000,
+			NetworkIn:     10,
+			NetworkOut:    10,
+			TransferQuota: 80,
+		},
+		Backups:         &linodego.InstanceBackup{Enabled: opts.BackupsEnabled},
+		Image:           opts.Image,
+		Group:           opts.Group,
+		IPv6:            fmt.Sprintf("2600:3c03::f03c:91ff:fe24:%x/64", m.nextID),
+		Label:           strings.TrimSpace(opts.Label),
+		Type:            linodeType.ID,
+		Status:          linodego.InstanceOffline,
+		Hypervisor:      "kvm",
+		WatchdogEnabled: true,
+		Tags:            opts.Tags,
+	}
+	if m.withoutIPv6 {
+		instance.IPv6 = ""
+	}
+	m.setInstanceSpecs(instance, linodeType)
+
+	if instance.Label == "" {
+		instance.Label = fmt.Sprintf("linode%d", instance.ID)
+	}
+	if instance.Tags == nil {
+		instance.Tags = []string{}
+	}
+
+	m.instances[instance.ID] = instance
+	m.addIP(instance, true)
+	if opts.PrivateIP {
+		m.addIP(instance, false)
+	}
+	m.addEvent(instance, linodego.ActionLinodeCreate)
+
+	if opts.Image != "" {
+		swapSize := 512
+		if opts.SwapSize != nil {
+			swapSize = *opts.SwapSize
+		}
+
+		rootDisk := m.addDisk(instance, fmt.Sprintf("%s Disk", opts.Image), linodeType.Disk-swapSize, "ext4")
+		devices := linodego.InstanceConfigDeviceMap{
+			SDA: &linodego.InstanceConfigDevice{DiskID: rootDisk.ID},
+		}
+		if swapSize > 0 {
+			swapDisk := m.addDisk(instance, fmt.Sprintf("%d MB Swap Image", swapSize), swapSize, "swap")
+			devices.SDB = &linodego.InstanceConfigDevice{DiskID: swapDisk.ID}
+		}
+
+		m.addConfig(instance, linodego.InstanceConfigCreateOptions{
+			Label:   fmt.Sprintf("My %s Disk Profile", opts.Image),
+			Devices: devices,
+		})
+	}
+
+	booted := opts.Image != "" || opts.BackupID != 0
+	if opts.Booted != nil {
+		booted = *opts.Booted
+	}
+	if booted && len(m.configs[instance.ID]) > 0 {
+		instance.Status = linodego.InstanceRunning
+		m.addEvent(instance, linodego.ActionLinodeBoot)
+	}
+
+	return http.StatusOK, instance
+}
+
+func (m *mockLinodeAPI) updateInstance(instance *linodego.Instance, body []byte) (int, interface{}) {
+	var opts linodego.InstanceUpdateOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		return mockAPIError(http.StatusBadRequest, err.Error())
+	}
+
+	if opts.Label != "" {
+		instance.Label = strings.TrimSpace(opts.Label)
+	}
+	// An empty group is only sent to clear the group, which linodego omits
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return mockAPIError(http.StatusBadRequest, err.Error())
+	}
+	if _, ok := fields["group"]; ok {
+		instance.Group = opts.Group
+	}
+	if raw, ok := fields["maintenance_policy"]; ok {
+		var policy string
+		json.Unmarshal(raw, &policy)
+		valid := false
+		for _, p := range mockMaintenancePolicies {
+			valid = valid || p.Slug == policy
+		}
+		if !valid {
+			return mockAPIError(http.StatusBadRequest, "maintenance_policy is not a valid maintenance policy")
+		}
+		m.maintenancePolicies[instance.ID] = policy
+	}
+	if opts.Tags != nil {
+		instance.Tags = *opts.Tags
+	}
+	if opts.Alerts != nil {
+		instance.Alerts = opts.Alerts
+	}
+	if opts.Backups != nil {
+		instance.Backups.Schedule = opts.Backups.Schedule
+	}
+	if opts.WatchdogEnabled != nil {
+		instance.WatchdogEnabled = *opts.WatchdogEnabled
+	}
+	instance.UpdatedStr = mockTimestamp()
+
+	return http.StatusOK, instance
+}
+
+func (m *mockLinodeAPI) resizeInstance(instance *linodego.Instance, body []byte) (int, interface{}) {
+	var opts struct {
+		Type                string `json:"type"`
+		AllowAutoDiskResize *bool  `json:"allow_auto_disk_resize"`
+	}
+	if err := json.Unmarshal(body, &opts); err != nil {
+		return mockAPIError(http.StatusBadRequest, err.Error())
+	}
+
+	linodeType := m.findType(opts.Type)
+	if linodeType == nil {
+		return mockAPIError(http.StatusBadRequest, "type is not a valid Linode type")
+	}
+	if m.usedDiskSpace(instance.ID) > linodeType.Disk {
+		return mockAPIError(http.StatusBadRequest, "Linode has allocated more disk than the new service plan allows")
+	}
+
+	// Like the API, the disk of an instance with a single disk, not counting swap, is grown into the new space
+	// unless allow_auto_disk_resize is false
+	if opts.AllowAutoDiskResize == nil || *opts.AllowAutoDiskResize {
+		var expandable []*linodego.InstanceDisk
+		for _, disk := range m.disks[instance.ID] {
+			if disk.Filesystem != linodego.FilesystemSwap {
+				expandable = append(expandable, disk)
+			}
+		}
+		if len(expandable) == 1 && len(m.disks[instance.ID]) <= 2 {
+			expandable[0].Size += linodeType.Disk - m.usedDiskSpace(instance.ID)
+		}
+	}
+
+	instance.Type = linodeType.ID
+	m.setInstanceSpecs(instance, linodeType)
+	m.addEvent(instance, linodego.ActionLinodeResize)
+
+	return http.StatusOK, nil
+}
+
+func (m *mockLinodeAPI) instanceIPAddresses(instance *linodego.Instance) *linodego.InstanceIPAddressResponse {
+	resp := &linodego.InstanceIPAddressResponse{
+		IPv4: &linodego.InstanceIPv4Response{
+			Public:  []*linodego.InstanceIP{},
+			Private: []*linodego.InstanceIP{},
+			Shared:  []*linodego.InstanceIP{},
+		},
+		IPv6: &linodego.InstanceIPv6Response{
+			SLAAC: &linodego.InstanceIP{
+				Address:  strings.Split(instance.IPv6, "/")[0],
+				Prefix:   64,
+				Type:     "ipv6",
+				Public:   true,
+				RDNS:     m.ipv6RDNS[instance.ID],
+				LinodeID: instance.ID,
+				Region:   instance.Region,
+			},
+			LinkLocal: &linodego.InstanceIP{
+				Address:  fmt.Sprintf("fe80::f03c:91ff:fe24:%x", instance.ID),
+				Prefix:   64,
+				Type:     "ipv6",
+				LinodeID: instance.ID,
+				Region:   instance.Region,
+			},
+			Global: []*linodego.IPv6Range{},
+		},
+	}
+	resp.IPv6.Global = append(resp.IPv6.Global, m.ipv6Ranges[instance.ID]...)
+	for _, address := range m.sharedIPs[instance.ID] {
+		resp.IPv4.Shared = append(resp.IPv4.Shared, &linodego.InstanceIP{Address: address, Type: "ipv4", Public: true, Region: instance.Region})
+	}
+
+	for _, ip := range m.ips[instance.ID] {
+		if ip.Public {
+			resp.IPv4.Public = append(resp.IPv4.Public, ip)
+		} else {
+			resp.IPv4.Private = append(resp.IPv4.Private, ip)
+		}
+	}
+	return resp
+}
+
+func (m *mockLinodeAPI) routeIPAddress(method string, address string, body []byte) (int, interface{}) {
+	var opts linodego.IPAddressUpdateOptions
+	if method == http.MethodPut {
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return mockAPIError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	for id, instance := range m.instances {
+		for _, ip := range m.ips[id] {
+			if ip.Address != address {
+				continue
+			}
+			switch method {
+			case http.MethodGet:
+				return http.StatusOK, ip
+			case http.MethodPut:
+				ip.RDNS = ""
+				if opts.RDNS != nil {
+					ip.RDNS = *opts.RDNS
+				}
+				return http.StatusOK, ip
+			}
+			return mockNotFound()
+		}
+
+		slaac := m.instanceIPAddresses(instance).IPv6.SLAAC
+		if instance.IPv6 == "" || slaac.Address != address {
+			continue
+		}
+		switch method {
+		case http.MethodGet:
+			return http.StatusOK, slaac
+		case http.MethodPut:
+			delete(m.ipv6RDNS, id)
+			if opts.RDNS != nil {
+				m.ipv6RDNS[id] = *opts.RDNS
+			}
+			return http.StatusOK, m.instanceIPAddresses(instance).IPv6.SLAAC
+		}
+		return mockNotFound()
+	}
+	return mockNotFound()
+}
+
+// instanceIPAddressesResponse includes the reserved flag on IPv4 addresses, which linodego does not yet model
+func (m *mockLinodeAPI) instanceIPAddressesResponse(instance *linodego.Instance) map[string]interface{} {
+	var resp map[string]interface{}
+	raw, _ := json.Marshal(m.instanceIPAddresses(instance))
+	json.Unmarshal(raw, &resp)
+
+	ipv4 := resp["ipv4"].(map[string]interface{})
+	for _, kind := range []string{"public", "private"} {
+		ips, _ := ipv4[kind].([]interface{})
+		for _, ip := range ips {
+			ipMap := ip.(map[string]interface{})
+			ipMap["reserved"] = m.findReservedIP(ipMap["address"].(string)) != nil
+		}
+	}
+	return resp
+}
+
+func (m *mockLinodeAPI) findReservedIP(address string) *reservedIP {
+	for _, ip := range m.reservedIPs {
+		if ip.Address == address {
+			return ip
+		}
+	}
+	return nil
+}
+
+func (m *mockLinodeAPI) assignReservedIP(instance *linodego.Instance, address string) (int, interface{}) {
+	reserved := m.findReservedIP(address)
+	if reserved == nil {
+		return mockAPIError(http.StatusBadRequest, "address is not a reserved IP")
+	}
+	if reserved.Region != instance.Region {
+		return mockAPIError(http.StatusBadRequest, "reserved IP must be in the same region as the Linode")
+	}
+	if reserved.LinodeID != nil {
+		return mockAPIError(http.StatusBadRequest, "reserved IP is already assigned")
+	}
+
+	ip := m.addIP(instance, true)
+	ip.Address = address
+	addr := net.ParseIP(address)
+	instance.IPv4[len(instance.IPv4)-1] = &addr
+	linodeID := instance.ID
+	reserved.LinodeID = &linodeID
+	m.addEvent(instance, linodego.ActionLinodeAddIP)
+	return http.StatusOK, ip
+}
+
+func (m *mockLinodeAPI) removeIP(instance *linodego.Instance, address string) (int, interface{}) {
+	for i, ip := range m.ips[instance.ID] {
+		if ip.Address != address {
+			continue
+		}
+		m.ips[instance.ID] = append(m.ips[instance.ID][:i], m.ips[instance.ID][i+1:]...)
+		for j, addr := range instance.IPv4 {
+			if addr.String() == address {
+				instance.IPv4 = append(instance.IPv4[:j], instance.IPv4[j+1:]...)
+				break
+			}
+		}
+		if reserved := m.findReservedIP(address); reserved != nil {
+			reserved.LinodeID = nil
+		}
+		return http.StatusOK, nil
+	}
+	return mockNotFound()
+}
+
+// reviseStackscript applies the create or update options to the StackScript as a new revision
+func (m *mockLinodeAPI) reviseStackscript(stackscript *linodego.Stackscript, body []byte) error {
+	var opts linodego.StackscriptUpdateOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		return err
+	}
+	stackscript.Label, stackscript.Script, stackscript.Description = opts.Label, opts.Script, opts.Description
+	stackscript.Images, stackscript.IsPublic, stackscript.RevNote = opts.Images, opts.IsPublic, opts.RevNote
+
+	updated := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	if stackscript.UpdatedStr != "" {
+		updated, _ = time.Parse(mockLinodeAPIDateLayout, stackscript.UpdatedStr)
+		updated = updated.Add(time.Minute)
+	}
+	stackscript.UpdatedStr = updated.Format(mockLinodeAPIDateLayout)
+	return nil
+}
+
+// mockMaintenancePolicies are the maintenance policies the mock accepts, the first being the default
+var mockMaintenancePolicies = []maintenancePolicy{
+	{Slug: "linode/migrate", Label: "Migrate", IsDefault: true},
+	{Slug: "linode/power_off_on", Label: "Power Off/Power On"},
+}
+
+// mockInstanceStats are three samples of each series of the stats of an instance, rising by 1 with each sample from
+// a different base for each series
+func mockInstanceStats(instance *linodego.Instance) *instanceStats {
+	series := func(base float64) [][]float64 {
+		return [][]float64{{1500000000000, base}, {1500000300000, base + 1}, {1500000600000, base + 2}}
+	}
+	stats := &instanceStats{Title: fmt.Sprintf("%s - Stats", instance.Label)}
+	stats.Data.CPU = series(10)
+	stats.Data.IO.IO = series(20)
+	stats.Data.IO.Swap = series(30)
+	stats.Data.NetV4 = instanceNetStats{In: series(40), Out: series(50), PrivateIn: series(60), PrivateOut: series(70)}
+	stats.Data.NetV6 = instanceNetStats{In: series(80), Out: series(90), PrivateIn: series(100), PrivateOut: series(110)}
+	return stats
+}
+
+// instanceResponse includes the instance fields which linodego does not yet model
+func (m *mockLinodeAPI) instanceResponse(instance *linodego.Instance) map[string]interface{} {
+	var resp map[string]interface{}
+	raw, _ := json.Marshal(instance)
+	json.Unmarshal(raw, &resp)
+
+	resp["placement_group"] = nil
+	if groupID, found := m.instancePlacement[instance.ID]; found {
+		resp["placement_group"] = m.findPlacementGroup(strconv.Itoa(groupID))
+	}
+	if hostUUID, found := m.hostUUIDs[instance.ID]; found {
+		resp["host_uuid"] = hostUUID
+	}
+	resp["maintenance_policy"] = mockMaintenancePolicies[0].Slug
+	if policy, found := m.maintenancePolicies[instance.ID]; found {
+		resp["maintenance_policy"] = policy
+	}
+	return resp
+}
+
+func (m *mockLinodeAPI) configResponse(config *linodego.InstanceConfig) map[string]interface{} {
+	var resp map[string]interface{}
+	raw, _ := json.Marshal(config)
+	json.Unmarshal(raw, &resp)
+
+	resp["interfaces"] = []instanceConfigInterface{}
+	if interfaces, found := m.configInterfaces[config.ID]; found {
+		resp["interfaces"] = interfaces
+	}
+	return resp
+}
+
+func (m *mockLinodeAPI) findPlacementGroup(groupID string) *placementGroup {
+	for i := range m.placementGroups {
+		if strconv.Itoa(m.placementGroups[i].ID) == groupID {
+			return &m.placementGroups[i]
+		}
+	}
+	return nil
+}
+
+func (m *mockLinodeAPI) findType(typeID string) *linodego.LinodeType {
+	for i := range m.types {
+		if m.types[i].ID == typeID {
+			return &m.types[i]
+		}
+	}
+	return nil
+}
+
+func (m *mockLinodeAPI) setInstanceSpecs(instance *linodego.Instance, linodeType *linodego.LinodeType) {
+	instance.Specs = &linodego.InstanceSpec{
+		Disk:     linodeType.Disk,
+		Memory:   linodeType.Memory,
+		VCPUs:    linodeType.VCPUs,
+		Transfer: linodeType.Transfer,
+	}
+}
+
+func (m *mockLinodeAPI) usedDiskSpace(instanceID int) (used int) {
+	for _, disk := range m.disks[instanceID] {
+		used += disk.Size
+	}
+	return
+}
+
+func (m *mockLinodeAPI) addIP(instance *linodego.Instance, public bool) *linodego.InstanceIP {
+	m.nextID++
+	ip := &linodego.InstanceIP{
+		Address:    fmt.Sprintf("198.51.%d.%d", m.nextID/250%250, m.nextID%250+1),
+		Gateway:    fmt.Sprintf("198.51.%d.1", m.nextID/250%250),
+		SubnetMask: "255.255.255.0",
+		Prefix:     24,
+		Type:       "ipv4",
+		Public:     public,
+		RDNS:       fmt.Sprintf("li%d.members.linode.com", instance.ID),
+		LinodeID:   instance.ID,
+		Region:     instance.Region,
+	}
+	if public && m.publicIPv4 != "" {
+		ip.Address = m.publicIPv4
+	}
+	if !public {
+		ip.Address = fmt.Sprintf("192.168.%d.%d", 128+m.nextID/250%127, m.nextID%250+1)
+		ip.Gateway = ""
+		ip.SubnetMask = "255.255.128.0"
+		ip.Prefix = 17
+		ip.RDNS = ""
+	}
+
+	m.ips[instance.ID] = append(m.ips[instance.ID], ip)
+
+	addr := net.ParseIP(ip.Address)
+	instance.IPv4 = append(instance.IPv4, &addr)
+	return ip
+}
+
+// cloneInstance copies the disks and configs of the source instance onto an existing target instance
+func (m *mockLinodeAPI) cloneInstance(source *linodego.Instance, body []byte) (int, interface{}) {
+	var opts linodego.InstanceCloneOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		return mockAPIError(http.StatusBadRequest, err.Error())
+	}
+	target, ok := m.instances[opts.LinodeID]
+	if !ok {
+		return mockAPIError(http.StatusBadRequest, "The mock only supports cloning to an existing Linode")
+	}
+	if m.usedDiskSpace(target.ID)+m.usedDiskSpace(source.ID) > target.Specs.Disk {
+		return mockAPIError(http.StatusBadRequest, "Insufficient space available on the target Linode")
+	}
+
+	diskIDs := make(map[int]int, len(m.disks[source.ID]))
+	for _, disk := range m.disks[source.ID] {
+		diskIDs[disk.ID] = m.addDisk(target, disk.Label, disk.Size, string(disk.Filesystem)).ID
+	}
+	clonedDevice := func(device *linodego.InstanceConfigDevice) *linodego.InstanceConfigDevice {
+		if device == nil {
+			return nil
+		}
+		return &linodego.InstanceConfigDevice{DiskID: diskIDs[device.DiskID], VolumeID: device.VolumeID}
+	}
+	for _, config := range m.configs[source.ID] {
+		devices := linodego.InstanceConfigDeviceMap{
+			SDA: clonedDevice(config.Devices.SDA),
+			SDB: clonedDevice(config.Devices.SDB),
+			SDC: clonedDevice(config.Devices.SDC),
+			SDD: clonedDevice(config.Devices.SDD),
+			SDE: clonedDevice(config.Devices.SDE),
+			SDF: clonedDevice(config.Devices.SDF),
+			SDG: clonedDevice(config.Devices.SDG),
+			SDH: clonedDevice(config.Devices.SDH),
+		}
+		rootDevice := config.RootDevice
+		m.addConfig(target, linodego.InstanceConfigCreateOptions{
+			Label:      config.Label,
+			Comments:   config.Comments,
+			Devices:    devices,
+			Helpers:    config.Helpers,
+			Kernel:     config.Kernel,
+			RootDevice: &rootDevice,
+			RunLevel:   config.RunLevel,
+			VirtMode:   config.VirtMode,
+		})
+	}
+	m.addEvent(source, linodego.ActionLinodeClone)
+	return http.StatusOK, m.instanceResponse(target)
+}
+
+func (m *mockLinodeAPI) addDisk(instance *linodego.Instance, label string, size int, filesystem string) *linodego.InstanceDisk {
+	if filesystem == "" {
+		filesystem = "ext4"
+	}
+
+	m.nextID++
+	now := mockTimestamp()
+	disk := &linodego.InstanceDisk{
+		CreatedStr: now,
+		UpdatedStr: now,
+		ID:         m.nextID,
+		Label:      label,
+		Status:     linodego.DiskReady,
+		Size:       size,
+		Filesystem: linodego.DiskFilesystem(filesystem),
+	}
+	m.disks[instance.ID] = append(m.disks[instance.ID], disk)
+	return disk
+}
+
+func (m *mockLinodeAPI) addConfig(instance *linodego.Instance, opts linodego.InstanceConfigCreateOptions) *linodego.InstanceConfig {
+	m.nextID++
+	now := mockTimestamp()
+	devices := opts.Devices
+	config := &linodego.InstanceConfig{
+		CreatedStr:  now,
+		UpdatedStr:  now,
+		ID:          m.nextID,
+		Label:       opts.Label,
+		Comments:    opts.Comments,
+		Devices:     &devices,
+		Helpers:     opts.Helpers,
+		MemoryLimit: opts.MemoryLimit,
+		Kernel:      opts.Kernel,
+		RootDevice:  "/dev/sda",
+		RunLevel:    opts.RunLevel,
+		VirtMode:    opts.VirtMode,
+	}
+
+	if config.Helpers == nil {
+		config.Helpers = &linodego.InstanceConfigHelpers{
+			UpdateDBDisabled:  true,
+			Distro:            true,
+			ModulesDep:        true,
+			Network:           m.settings.NetworkHelper,
+			DevTmpFsAutomount: true,
+		}
+	}
+	if config.Kernel == "" {
+		config.Kernel = "linode/latest-64bit"
+	}
+	if opts.RootDevice != nil && *opts.RootDevice != "" {
+		config.RootDevice = *opts.RootDevice
+	}
+	if config.RunLevel == "" {
+		config.RunLevel = "default"
+	}
+	if config.VirtMode == "" {
+		config.VirtMode = "paravirt"
+	}
+	if opts.InitRD != 0 {
+		initrd := opts.InitRD
+		config.InitRD = &initrd
+	}
+
+	m.configs[instance.ID] = append(m.configs[instance.ID], config)
+	return config
+}
+
+func (m *mockLinodeAPI) addEvent(instance *linodego.Instance, action linodego.EventAction) {
+	m.nextID++
+	m.events = append(m.events, &linodego.Event{
+		CreatedStr:      mockTimestamp(),
+		ID:              m.nextID,
+		Status:          linodego.EventFinished,
+		Action:          action,
+		PercentComplete: 100,
+		Username:        "mock",
+		Entity: &linodego.EventEntity{
+			ID:    instance.ID,
+			Label: instance.Label,
+			Type:  linodego.EntityLinode,
+			URL:   fmt.Sprintf("/v4/linode/instances/%d", instance.ID),
+		},
+	})
+}
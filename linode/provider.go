@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform/helper/logging"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/hashicorp/terraform/version"
 	"github.com/linode/linodego"
@@ -16,6 +17,26 @@ import (
 // DefaultLinodeURL is the Linode APIv4 URL to use
 const DefaultLinodeURL = "https://api.linode.com/v4"
 
+// DefaultLinodeAPIVersion is the Linode API version used when api_version is not configured
+const DefaultLinodeAPIVersion = "v4"
+
+// Config holds the provider settings which change the behavior of resources
+type Config struct {
+	APIVersion            string
+	DefaultTags           []string
+	SkipInstanceReadyPoll bool
+	// AutoEnableBackups enables backups on new instances which do not set backups_enabled
+	AutoEnableBackups bool
+	// AlertProfiles are the alert thresholds of each alert profile by name, including only the thresholds it sets
+	AlertProfiles map[string]map[string]int
+}
+
+// ProviderMeta is the configured provider, passed to resources and data sources as their meta
+type ProviderMeta struct {
+	Client linodego.Client
+	Config *Config
+}
+
 // Provider creates and manages the resources in a Linode configuration.
 func Provider() terraform.ResourceProvider {
 	return &schema.Provider{
@@ -32,24 +53,96 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("LINODE_URL", nil),
 				Description: "The HTTP(S) API address of the Linode API to use.",
 			},
+			"api_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("LINODE_API_VERSION", DefaultLinodeAPIVersion),
+				ValidateFunc: validation.StringInSlice([]string{"v4", "v4beta"}, false),
+				Description:  "The version of the Linode API to use, v4 or v4beta. Ignored when url is set, since url includes the version.",
+			},
 			"ua_prefix": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("LINODE_UA_PREFIX", nil),
 				Description: "An HTTP User-Agent Prefix to prepend in API requests.",
 			},
+			"default_tags": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateTag},
+				Optional:    true,
+				Description: "Tags applied to every resource which can be tagged, in addition to the tags of the resource.",
+			},
+			"alert_profile": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Named sets of alert thresholds which linode_instance resources can apply with alert_profile.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name linode_instance resources refer to the profile by.",
+						},
+						"cpu": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The CPU usage alert threshold, in percent.",
+						},
+						"io": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The disk IO alert threshold, in operations per second.",
+						},
+						"network_in": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The incoming traffic alert threshold, in Mbit/s.",
+						},
+						"network_out": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "The outbound traffic alert threshold, in Mbit/s.",
+						},
+						"transfer_quota": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(0, 100),
+							Description:  "The network transfer alert threshold, in percent of the transfer quota.",
+						},
+					},
+				},
+			},
+			"skip_instance_ready_poll": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("LINODE_SKIP_INSTANCE_READY_POLL", false),
+				Description: "Skip reading the disks, configs, events and backups of existing linode_instance resources when refreshing, to reduce the number of API requests for large deployments.",
+			},
+			"auto_enable_backups": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("LINODE_AUTO_ENABLE_BACKUPS", false),
+				Description: "Enable backups on every new linode_instance which does not set backups_enabled.",
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"linode_account":       dataSourceLinodeAccount(),
-			"linode_domain":        dataSourceLinodeDomain(),
-			"linode_image":         dataSourceLinodeImage(),
-			"linode_instance_type": dataSourceLinodeInstanceType(),
-			"linode_networking_ip": dataSourceLinodeNetworkingIP(),
-			"linode_profile":       dataSourceLinodeProfile(),
-			"linode_region":        dataSourceLinodeRegion(),
-			"linode_sshkey":        dataSourceLinodeSSHKey(),
-			"linode_user":          dataSourceLinodeUser(),
+			"linode_account":             dataSourceLinodeAccount(),
+			"linode_api_status":          dataSourceLinodeAPIStatus(),
+			"linode_domain":              dataSourceLinodeDomain(),
+			"linode_image":               dataSourceLinodeImage(),
+			"linode_instance_stats":      dataSourceLinodeInstanceStats(),
+			"linode_instance_type":       dataSourceLinodeInstanceType(),
+			"linode_networking_ip":       dataSourceLinodeNetworkingIP(),
+			"linode_profile":             dataSourceLinodeProfile(),
+			"linode_region":              dataSourceLinodeRegion(),
+			"linode_region_capabilities": dataSourceLinodeRegionCapabilities(),
+			"linode_sshkey":              dataSourceLinodeSSHKey(),
+			"linode_user":                dataSourceLinodeUser(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -57,12 +150,15 @@ func Provider() terraform.ResourceProvider {
 			"linode_instance":            resourceLinodeInstance(),
 			"linode_domain":              resourceLinodeDomain(),
 			"linode_domain_record":       resourceLinodeDomainRecord(),
+			"linode_domain_records":      resourceLinodeDomainRecords(),
+			"linode_longview_client":     resourceLinodeLongviewClient(),
 			"linode_nodebalancer":        resourceLinodeNodeBalancer(),
 			"linode_nodebalancer_config": resourceLinodeNodeBalancerConfig(),
 			"linode_nodebalancer_node":   resourceLinodeNodeBalancerNode(),
 			"linode_rdns":                resourceLinodeRDNS(),
 			"linode_sshkey":              resourceLinodeSSHKey(),
 			"linode_stackscript":         resourceLinodeStackscript(),
+			"linode_tag":                 resourceLinodeTag(),
 			"linode_token":               resourceLinodeToken(),
 			"linode_volume":              resourceLinodeVolume(),
 		},
@@ -87,14 +183,53 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, fmt.Errorf("The Linode UA Prefix was not valid")
 	}
 
-	client := getLinodeClient(token, url, uaPrefix)
+	apiVersion, ok := d.Get("api_version").(string)
+	if !ok {
+		return nil, fmt.Errorf("The Linode API Version was not valid")
+	}
+
+	client := getLinodeClient(token, linodeBaseURL(url, apiVersion), uaPrefix)
 	// Ping the API for an empty response to verify the configuration works
 	_, err := client.ListTypes(context.Background(), linodego.NewListOptions(100, ""))
 	if err != nil {
 		return nil, fmt.Errorf("Error connecting to the Linode API: %s", err)
 	}
 
-	return client, nil
+	alertProfiles, err := expandAlertProfiles(d)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		APIVersion:            apiVersion,
+		DefaultTags:           expandTags(d.Get("default_tags").(*schema.Set)),
+		SkipInstanceReadyPoll: d.Get("skip_instance_ready_poll").(bool),
+		AutoEnableBackups:     d.Get("auto_enable_backups").(bool),
+		AlertProfiles:         alertProfiles,
+	}
+
+	return &ProviderMeta{Client: client, Config: config}, nil
+}
+
+// expandAlertProfiles returns the thresholds of the alert_profile blocks by profile name. Thresholds a profile does not
+// set are left out, so they keep their current values on the instances using the profile.
+func expandAlertProfiles(d *schema.ResourceData) (map[string]map[string]int, error) {
+	profiles := make(map[string]map[string]int)
+	for i, profileRaw := range d.Get("alert_profile").([]interface{}) {
+		name := profileRaw.(map[string]interface{})["name"].(string)
+		if _, duplicate := profiles[name]; duplicate {
+			return nil, fmt.Errorf("Error configuring alert_profile: the name %s is used by multiple profiles", name)
+		}
+
+		thresholds := make(map[string]int)
+		for _, key := range instanceAlertThresholds {
+			if value, ok := d.GetOkExists(fmt.Sprintf("alert_profile.%d.%s", i, key)); ok {
+				thresholds[key] = value.(int)
+			}
+		}
+		profiles[name] = thresholds
+	}
+	return profiles, nil
 }
 
 func getLinodeClient(token, url, uaPrefix string) linodego.Client {
@@ -110,20 +245,35 @@ func getLinodeClient(token, url, uaPrefix string) linodego.Client {
 
 	client := linodego.NewClient(oauth2Client)
 
-	projectURL := "https://www.terraform.io"
-	userAgent := fmt.Sprintf("Terraform/%s (+%s) linodego/%s",
-		version.String(), projectURL, linodego.Version)
-
-	if len(uaPrefix) > 0 {
-		userAgent = uaPrefix + " " + userAgent
-	}
-
 	var baseURL = DefaultLinodeURL
 	if len(url) > 0 {
 		baseURL = url
 	}
 
 	client.SetBaseURL(baseURL)
-	client.SetUserAgent(userAgent)
+	client.SetUserAgent(linodeUserAgent(uaPrefix))
 	return client
 }
+
+// linodeBaseURL is the API address for url, or for apiVersion of the public Linode API when url is empty
+func linodeBaseURL(url, apiVersion string) string {
+	if len(url) > 0 {
+		return url
+	}
+	if len(apiVersion) == 0 {
+		return DefaultLinodeURL
+	}
+	return fmt.Sprintf("%s://%s/%s", linodego.APIProto, linodego.APIHost, apiVersion)
+}
+
+// linodeUserAgent identifies Terraform and this provider to the Linode API, after the optional uaPrefix
+func linodeUserAgent(uaPrefix string) string {
+	projectURL := "https://www.terraform.io"
+	userAgent := fmt.Sprintf("Terraform/%s (+%s) terraform-provider-linode linodego/%s",
+		version.String(), projectURL, linodego.Version)
+
+	if len(uaPrefix) > 0 {
+		userAgent = uaPrefix + " " + userAgent
+	}
+	return userAgent
+}
@@ -2,6 +2,7 @@ package linode
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -24,6 +25,44 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProvider_userAgent(t *testing.T) {
+	userAgent := linodeUserAgent("")
+	if !strings.HasPrefix(userAgent, "Terraform/") || !strings.Contains(userAgent, " terraform-provider-linode linodego/") {
+		t.Errorf("Expected the default User-Agent to identify Terraform and the provider, got %q", userAgent)
+	}
+
+	if prefixed := linodeUserAgent("my-automation/1.0"); prefixed != "my-automation/1.0 "+userAgent {
+		t.Errorf("Expected ua_prefix to be prepended to the default User-Agent, got %q", prefixed)
+	}
+}
+
+func TestProvider_baseURL(t *testing.T) {
+	for _, tc := range []struct {
+		url, apiVersion, expected string
+	}{
+		{"", "", DefaultLinodeURL},
+		{"", "v4", DefaultLinodeURL},
+		{"", "v4beta", "https://api.linode.com/v4beta"},
+		{"https://proxy.example.com/v4", "v4beta", "https://proxy.example.com/v4"},
+	} {
+		if baseURL := linodeBaseURL(tc.url, tc.apiVersion); baseURL != tc.expected {
+			t.Errorf("Expected url %q and api_version %q to use %q, got %q", tc.url, tc.apiVersion, tc.expected, baseURL)
+		}
+	}
+}
+
+func TestProvider_tags(t *testing.T) {
+	for name, resource := range Provider().(*schema.Provider).ResourcesMap {
+		tags, ok := resource.Schema["tags"]
+		if !ok {
+			continue
+		}
+		if elem, ok := tags.Elem.(*schema.Schema); tags.Type != schema.TypeSet || !ok || elem.ValidateFunc == nil {
+			t.Errorf("Expected %s tags to be a set of validated tags", name)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("LINODE_TOKEN"); v == "" {
 		t.Fatal("LINODE_TOKEN must be set for acceptance tests")
@@ -15,11 +15,12 @@ func resourceLinodeDomain() *schema.Resource {
 	validDomainSeconds := domainSecondsValidator()
 
 	return &schema.Resource{
-		Create: resourceLinodeDomainCreate,
-		Read:   resourceLinodeDomainRead,
-		Update: resourceLinodeDomainUpdate,
-		Delete: resourceLinodeDomainDelete,
-		Exists: resourceLinodeDomainExists,
+		Create:        resourceLinodeDomainCreate,
+		Read:          resourceLinodeDomainRead,
+		Update:        resourceLinodeDomainUpdate,
+		Delete:        resourceLinodeDomainDelete,
+		CustomizeDiff: customizeResourceTagsAllDiff,
+		Exists:        resourceLinodeDomainExists,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -101,12 +102,8 @@ func resourceLinodeDomain() *schema.Resource {
 				Description: "Start of Authority email address. This is required for master Domains.",
 				Optional:    true,
 			},
-			"tags": {
-				Type:        schema.TypeSet,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Optional:    true,
-				Description: "An array of tags applied to this object. Tags are for organizational purposes only.",
-			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -138,7 +135,7 @@ func domainSecondsValidator() schema.SchemaValidateFunc {
 }
 
 func resourceLinodeDomainExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode Domain ID %s as int: %s", d.Id(), err)
@@ -156,7 +153,7 @@ func resourceLinodeDomainExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceLinodeDomainRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Domain ID %s as int: %s", d.Id(), err)
@@ -191,16 +188,15 @@ func resourceLinodeDomainRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("expire_sec", domain.ExpireSec)
 	d.Set("refresh_sec", domain.RefreshSec)
 	d.Set("soa_email", domain.SOAEmail)
-	d.Set("tags", domain.Tags)
+	if err := readTags(d, meta, "domain", domain.ID, domain.Tags); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func resourceLinodeDomainCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Domain")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	createOpts := linodego.DomainCreateOptions{
 		Domain:      d.Get("domain").(string),
@@ -214,10 +210,8 @@ func resourceLinodeDomainCreate(d *schema.ResourceData, meta interface{}) error
 		TTLSec:      d.Get("ttl_sec").(int),
 	}
 
-	if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
-		for _, tag := range tagsRaw.(*schema.Set).List() {
-			createOpts.Tags = append(createOpts.Tags, tag.(string))
-		}
+	if tags := expandResourceTags(d, meta); len(tags) > 0 {
+		createOpts.Tags = tags
 	}
 
 	if v, ok := d.GetOk("master_ips"); ok {
@@ -248,7 +242,7 @@ func resourceLinodeDomainCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeDomainUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -285,14 +279,8 @@ func resourceLinodeDomainUpdate(d *schema.ResourceData, meta interface{}) error
 		updateOpts.AXfrIPs = AXfrIPs
 	}
 
-	if d.HasChange("tags") {
-		tags := []string{}
-		for _, tag := range d.Get("tags").(*schema.Set).List() {
-			tags = append(tags, tag.(string))
-		}
-
-		updateOpts.Tags = tags
-	}
+	// The update options always include tags, which replace the Domain's tags like applyTags does
+	updateOpts.Tags = expandResourceTags(d, meta)
 
 	_, err = client.UpdateDomain(context.Background(), int(id), updateOpts)
 	if err != nil {
@@ -302,7 +290,7 @@ func resourceLinodeDomainUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeDomainDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Domain id %s as int", d.Id())
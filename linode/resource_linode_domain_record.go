@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -12,6 +14,10 @@ import (
 	"github.com/linode/linodego"
 )
 
+// domainRecordHostnameRegex matches a DNS name made of RFC 1123 labels, optionally fully qualified. Labels may also
+// contain underscores, as the service labels of DKIM and certificate validation names, like _domainkey, do.
+var domainRecordHostnameRegex = regexp.MustCompile(`^([a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?)(\.[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?)*\.?$`)
+
 func resourceLinodeDomainRecord() *schema.Resource {
 	validDomainSeconds := domainSecondsValidator()
 
@@ -24,6 +30,7 @@ func resourceLinodeDomainRecord() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceLinodeDomainRecordImport,
 		},
+		CustomizeDiff: resourceLinodeDomainRecordCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"domain_id": {
 				Type:        schema.TypeInt,
@@ -90,8 +97,41 @@ func resourceLinodeDomainRecord() *schema.Resource {
 	}
 }
 
+func resourceLinodeDomainRecordCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// The target may come from another resource, such as an instance IP, which is not known until apply
+	if !d.NewValueKnown("target") || !d.NewValueKnown("record_type") {
+		return nil
+	}
+
+	return validateDomainRecordTarget(d.Get("record_type").(string), d.Get("target").(string))
+}
+
+// validateDomainRecordTarget checks that the target of A and AAAA records is an address of the right family
+// and that the target of CNAME records is a hostname. The targets of other record types are left to the API.
+func validateDomainRecordTarget(recordType, target string) error {
+	switch recordType {
+	case "A", "AAAA":
+		// The API resolves [remote_addr] to the address of the requesting client
+		if target == "[remote_addr]" {
+			return nil
+		}
+		ip := net.ParseIP(target)
+		if recordType == "A" && (ip == nil || ip.To4() == nil) {
+			return fmt.Errorf("Error validating Domain Record target %q: A records must target an IPv4 address", target)
+		}
+		if recordType == "AAAA" && (ip == nil || ip.To4() != nil) {
+			return fmt.Errorf("Error validating Domain Record target %q: AAAA records must target an IPv6 address", target)
+		}
+	case "CNAME":
+		if len(target) > 253 || !domainRecordHostnameRegex.MatchString(target) || net.ParseIP(target) != nil {
+			return fmt.Errorf("Error validating Domain Record target %q: CNAME records must target a hostname", target)
+		}
+	}
+	return nil
+}
+
 func resourceLinodeDomainRecordExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode DomainRecord ID %s as int: %s", d.Id(), err)
@@ -142,7 +182,7 @@ func resourceLinodeDomainRecordImport(d *schema.ResourceData, meta interface{})
 }
 
 func resourceLinodeDomainRecordRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode DomainRecord ID %s as int: %s", d.Id(), err)
@@ -193,10 +233,7 @@ func resourceDataIntOrNil(d *schema.ResourceData, name string) *int {
 }
 
 func resourceLinodeDomainRecordCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode DomainRecord")
-	}
+	client := meta.(*ProviderMeta).Client
 	domainID := d.Get("domain_id").(int)
 
 	createOpts := linodego.DomainRecordCreateOptions{
@@ -223,7 +260,7 @@ func resourceLinodeDomainRecordCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceLinodeDomainRecordUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	domainID := d.Get("domain_id").(int)
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
@@ -252,7 +289,7 @@ func resourceLinodeDomainRecordUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceLinodeDomainRecordDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	domainID := d.Get("domain_id").(int)
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 
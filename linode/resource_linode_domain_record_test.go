@@ -3,6 +3,7 @@ package linode
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -41,6 +42,66 @@ func TestAccLinodeDomainRecord_basic(t *testing.T) {
 	})
 }
 
+func TestLinodeDomainRecord_validateTarget(t *testing.T) {
+	for _, tc := range []struct {
+		recordType string
+		target     string
+		valid      bool
+	}{
+		{"A", "192.0.2.1", true},
+		{"A", "[remote_addr]", true},
+		{"A", "192.0.2", false},
+		{"A", "2001:db8::1", false},
+		{"A", "www.example.com", false},
+		{"AAAA", "2001:db8::1", true},
+		{"AAAA", "[remote_addr]", true},
+		{"AAAA", "2001:db8::g", false},
+		{"AAAA", "192.0.2.1", false},
+		{"CNAME", "www.example.com", true},
+		{"CNAME", "www.example.com.", true},
+		{"CNAME", "www", true},
+		{"CNAME", "s1._domainkey.u123.wl.sendgrid.net", true},
+		{"CNAME", "_x.acm-validations.aws.", true},
+		{"CNAME", "192.0.2.1", false},
+		{"CNAME", "www example.com", false},
+		{"CNAME", "www..example.com", false},
+		{"CNAME", "-www.example.com", false},
+		{"CNAME", "http://www.example.com", false},
+		{"CNAME", "", false},
+		{"TXT", "v=spf1 -all", true},
+		{"MX", "mail.example.com", true},
+	} {
+		err := validateDomainRecordTarget(tc.recordType, tc.target)
+		if tc.valid && err != nil {
+			t.Errorf("Expected %s target %q to be valid, got %s", tc.recordType, tc.target, err)
+		} else if !tc.valid && err == nil {
+			t.Errorf("Expected %s target %q to be invalid", tc.recordType, tc.target)
+		}
+	}
+}
+
+func TestLinodeDomainRecord_mockTargetValidation(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeDomainRecordConfigTarget("A", "2001:db8::1"),
+				ExpectError: regexp.MustCompile("A records must target an IPv4 address"),
+			},
+			{
+				Config:      testAccCheckLinodeDomainRecordConfigTarget("CNAME", "http://www.example.com"),
+				ExpectError: regexp.MustCompile("CNAME records must target a hostname"),
+			},
+		},
+	})
+
+	if calls := api.callCount("POST domains/1234/records"); calls != 0 {
+		t.Errorf("Expected no Domain Records to be created, got %d create calls", calls)
+	}
+}
+
 func testAccStateIDDomainRecord(s *terraform.State) (string, error) {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_domain_record" {
@@ -90,7 +151,7 @@ func TestAccLinodeDomainRecord_update(t *testing.T) {
 }
 
 func testAccCheckLinodeDomainRecordExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_domain_record" {
@@ -115,10 +176,7 @@ func testAccCheckLinodeDomainRecordExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeDomainRecordDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_domain_record" {
 			continue
@@ -170,3 +228,13 @@ resource "linode_domain_record" "foobar" {
 	target = "target.%s.example"
 }`, domainRecord, domainRecord)
 }
+
+func testAccCheckLinodeDomainRecordConfigTarget(recordType, target string) string {
+	return fmt.Sprintf(`
+resource "linode_domain_record" "foobar" {
+	domain_id = 1234
+	name = "www"
+	record_type = "%s"
+	target = "%s"
+}`, recordType, target)
+}
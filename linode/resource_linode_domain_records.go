@@ -0,0 +1,352 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/linode/linodego"
+)
+
+func resourceLinodeDomainRecords() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLinodeDomainRecordsCreate,
+		Read:   resourceLinodeDomainRecordsRead,
+		Update: resourceLinodeDomainRecordsUpdate,
+		Delete: resourceLinodeDomainRecordsDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: resourceLinodeDomainRecordsCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the Domain whose records are managed.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"record": {
+				Type:        schema.TypeSet,
+				Description: "The records of the Domain. Records of the Domain which are not listed are deleted.",
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Description:  "The name of this Record. For A and AAAA records, this is the subdomain being associated with an IP address.",
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(0, 100),
+						},
+						"record_type": {
+							Type:         schema.TypeString,
+							Description:  "The type of Record this is in the DNS system.",
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"A", "AAAA", "NS", "MX", "CNAME", "TXT", "SRV", "PTR", "CAA"}, false),
+						},
+						"target": {
+							Type:        schema.TypeString,
+							Description: "The target for this Record. For A and AAAA records, this is the address the named Domain should resolve to.",
+							Required:    true,
+						},
+						"ttl_sec": {
+							Type:         schema.TypeInt,
+							Description:  "'Time to Live' - the amount of time in seconds that this Record may be cached by resolvers or other domain servers.",
+							Optional:     true,
+							ValidateFunc: domainSecondsValidator(),
+						},
+						"priority": {
+							Type:         schema.TypeInt,
+							Description:  "The priority of the target host. Lower values are preferred.",
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(0, 255),
+						},
+						"protocol": {
+							Type:        schema.TypeString,
+							Description: "The protocol this Record's service communicates with. Only valid for SRV records.",
+							Optional:    true,
+						},
+						"service": {
+							Type:        schema.TypeString,
+							Description: "The service this Record identified. Only valid for SRV records.",
+							Optional:    true,
+						},
+						"tag": {
+							Type:        schema.TypeString,
+							Description: "The tag portion of a CAA record. It is invalid to set this on other record types.",
+							Optional:    true,
+						},
+						"port": {
+							Type:        schema.TypeInt,
+							Description: "The port this Record points to.",
+							Optional:    true,
+						},
+						"weight": {
+							Type:        schema.TypeInt,
+							Description: "The relative weight of this Record. Higher values are preferred.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceLinodeDomainRecordsCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// Targets may come from other resources, such as instance IPs, which are not known until apply
+	if !d.NewValueKnown("record") {
+		return nil
+	}
+
+	records := d.Get("record").(*schema.Set)
+	for _, record := range records.List() {
+		record := record.(map[string]interface{})
+		// The set stays known when only the target of a record comes from another resource, such as the IP of an
+		// instance created in the same apply. Those targets are validated by the API when they are applied.
+		if !domainRecordTargetKnown(d, records.F(record), record["target"].(string)) {
+			continue
+		}
+		if err := validateDomainRecordTarget(record["record_type"].(string), record["target"].(string)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// domainRecordTargetKnown reports whether the target of the record with the set hash is known when planning. A
+// target interpolated from a value which is not known yet is either marked computed in the diff, or read as the
+// unknown value or the raw interpolation.
+func domainRecordTargetKnown(d *schema.ResourceDiff, hash int, target string) bool {
+	if !d.NewValueKnown(fmt.Sprintf("record.%d.target", hash)) {
+		return false
+	}
+	return target != config.UnknownVariableValue && !strings.Contains(target, "${")
+}
+
+func resourceLinodeDomainRecordsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	domainID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing Linode Domain ID %s as int: %s", d.Id(), err)
+	}
+
+	records, err := client.ListDomainRecords(context.Background(), domainID, nil)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			log.Printf("[WARN] removing the records of Linode Domain %q from state because it no longer exists", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error listing the records of Linode Domain %d: %s", domainID, err)
+	}
+
+	flattened := make([]interface{}, len(records))
+	for i, record := range records {
+		flattened[i] = flattenDomainRecord(record)
+	}
+
+	d.Set("domain_id", domainID)
+	if err := d.Set("record", flattened); err != nil {
+		return fmt.Errorf("Error setting the records of Linode Domain %d: %s", domainID, err)
+	}
+
+	return nil
+}
+
+func resourceLinodeDomainRecordsCreate(d *schema.ResourceData, meta interface{}) error {
+	domainID := d.Get("domain_id").(int)
+	if err := reconcileDomainRecords(meta.(*ProviderMeta).Client, domainID, d.Get("record").(*schema.Set)); err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(domainID))
+
+	return resourceLinodeDomainRecordsRead(d, meta)
+}
+
+func resourceLinodeDomainRecordsUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("record") {
+		if err := reconcileDomainRecords(meta.(*ProviderMeta).Client, d.Get("domain_id").(int), d.Get("record").(*schema.Set)); err != nil {
+			return err
+		}
+	}
+
+	return resourceLinodeDomainRecordsRead(d, meta)
+}
+
+func resourceLinodeDomainRecordsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	domainID := d.Get("domain_id").(int)
+
+	records, err := client.ListDomainRecords(context.Background(), domainID, nil)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error listing the records of Linode Domain %d: %s", domainID, err)
+	}
+
+	// Only the records known to Terraform are deleted, in case records were added since the last refresh
+	declared := d.Get("record").(*schema.Set)
+	for _, record := range records {
+		if !declared.Contains(flattenDomainRecord(record)) {
+			continue
+		}
+		if err := client.DeleteDomainRecord(context.Background(), domainID, record.ID); err != nil {
+			return fmt.Errorf("Error deleting Linode Domain Record %d: %s", record.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileDomainRecords makes the live records of a domain match the declared records. Live records matching a
+// declared record are kept, declared records sharing the name and type of an unmatched live record update it, and
+// the remaining live records are deleted before the remaining declared records are created.
+func reconcileDomainRecords(client linodego.Client, domainID int, declared *schema.Set) error {
+	live, err := client.ListDomainRecords(context.Background(), domainID, nil)
+	if err != nil {
+		return fmt.Errorf("Error listing the records of Linode Domain %d: %s", domainID, err)
+	}
+
+	// Records are handled in a stable order, so that plans are applied the same way every time
+	wanted := declared.List()
+	sort.Slice(wanted, func(i, j int) bool {
+		return domainRecordSortKey(wanted[i].(map[string]interface{})) < domainRecordSortKey(wanted[j].(map[string]interface{}))
+	})
+	sort.Slice(live, func(i, j int) bool {
+		return domainRecordSortKey(flattenDomainRecord(live[i])) < domainRecordSortKey(flattenDomainRecord(live[j]))
+	})
+
+	unmatched := make([]linodego.DomainRecord, 0, len(live))
+	for _, record := range live {
+		if declared.Contains(flattenDomainRecord(record)) {
+			continue
+		}
+		unmatched = append(unmatched, record)
+	}
+
+	liveSet := schema.NewSet(declared.F, nil)
+	for _, record := range live {
+		liveSet.Add(flattenDomainRecord(record))
+	}
+
+	var missing []map[string]interface{}
+	for _, record := range wanted {
+		record := record.(map[string]interface{})
+		if liveSet.Contains(record) {
+			continue
+		}
+
+		updated := false
+		for i, current := range unmatched {
+			if string(current.Type) != record["record_type"].(string) || current.Name != record["name"].(string) {
+				continue
+			}
+			if _, err := client.UpdateDomainRecord(context.Background(), domainID, current.ID, expandDomainRecordUpdateOptions(record)); err != nil {
+				return fmt.Errorf("Error updating Linode Domain Record %d: %s", current.ID, err)
+			}
+			unmatched = append(unmatched[:i], unmatched[i+1:]...)
+			updated = true
+			break
+		}
+		if !updated {
+			missing = append(missing, record)
+		}
+	}
+
+	for _, record := range unmatched {
+		if err := client.DeleteDomainRecord(context.Background(), domainID, record.ID); err != nil {
+			return fmt.Errorf("Error deleting Linode Domain Record %d: %s", record.ID, err)
+		}
+	}
+
+	for _, record := range missing {
+		if _, err := client.CreateDomainRecord(context.Background(), domainID, expandDomainRecordCreateOptions(record)); err != nil {
+			return fmt.Errorf("Error creating a Linode Domain Record %s %q: %s", record["record_type"], record["name"], err)
+		}
+	}
+
+	return nil
+}
+
+// domainRecordSortKey orders records by name, type and target
+func domainRecordSortKey(record map[string]interface{}) string {
+	return fmt.Sprintf("%s\x00%s\x00%s", record["name"], record["record_type"], record["target"])
+}
+
+func flattenDomainRecord(record linodego.DomainRecord) map[string]interface{} {
+	stringOrEmpty := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	return map[string]interface{}{
+		"name":        record.Name,
+		"record_type": string(record.Type),
+		"target":      record.Target,
+		"ttl_sec":     record.TTLSec,
+		"priority":    record.Priority,
+		"protocol":    stringOrEmpty(record.Protocol),
+		"service":     stringOrEmpty(record.Service),
+		"tag":         stringOrEmpty(record.Tag),
+		"port":        record.Port,
+		"weight":      record.Weight,
+	}
+}
+
+// domainRecordStringOrNil returns nil for the empty values of optional record strings
+func domainRecordStringOrNil(record map[string]interface{}, name string) *string {
+	if s := record[name].(string); s != "" {
+		return &s
+	}
+	return nil
+}
+
+// domainRecordIntOrNil returns nil for the empty values of optional record ints
+func domainRecordIntOrNil(record map[string]interface{}, name string) *int {
+	if i := record[name].(int); i != 0 {
+		return &i
+	}
+	return nil
+}
+
+func expandDomainRecordCreateOptions(record map[string]interface{}) linodego.DomainRecordCreateOptions {
+	return linodego.DomainRecordCreateOptions{
+		Type:     linodego.DomainRecordType(record["record_type"].(string)),
+		Name:     record["name"].(string),
+		Target:   record["target"].(string),
+		Priority: domainRecordIntOrNil(record, "priority"),
+		Weight:   domainRecordIntOrNil(record, "weight"),
+		Port:     domainRecordIntOrNil(record, "port"),
+		Service:  domainRecordStringOrNil(record, "service"),
+		Protocol: domainRecordStringOrNil(record, "protocol"),
+		TTLSec:   record["ttl_sec"].(int),
+		Tag:      domainRecordStringOrNil(record, "tag"),
+	}
+}
+
+func expandDomainRecordUpdateOptions(record map[string]interface{}) linodego.DomainRecordUpdateOptions {
+	// Numbers are always sent, so that they can be reset to 0
+	priority, weight, port := record["priority"].(int), record["weight"].(int), record["port"].(int)
+
+	return linodego.DomainRecordUpdateOptions{
+		Type:     linodego.DomainRecordType(record["record_type"].(string)),
+		Name:     record["name"].(string),
+		Target:   record["target"].(string),
+		Priority: &priority,
+		Weight:   &weight,
+		Port:     &port,
+		Service:  domainRecordStringOrNil(record, "service"),
+		Protocol: domainRecordStringOrNil(record, "protocol"),
+		TTLSec:   record["ttl_sec"].(int),
+		Tag:      domainRecordStringOrNil(record, "tag"),
+	}
+}
@@ -0,0 +1,303 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/linode/linodego"
+)
+
+func TestAccLinodeDomainRecords_basic(t *testing.T) {
+	t.Parallel()
+
+	resName := "linode_domain_records.foobar"
+	var domainName = acctest.RandomWithPrefix("tf-test-") + ".example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeDomainDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeDomainConfigBasic(domainName) + testAccCheckLinodeDomainRecordsConfigBasic("${linode_domain.foobar.id}"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeDomainRecordsExist,
+					resource.TestCheckResourceAttr(resName, "record.#", "3"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeDomainConfigBasic(domainName) + testAccCheckLinodeDomainRecordsConfigUpdates("${linode_domain.foobar.id}"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "record.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestLinodeDomainRecords_mock(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_domain_records.foobar"
+	domainID := 5000
+
+	// The domain has a record created outside of Terraform, which is removed when the records are created
+	api.mu.Lock()
+	api.domainRecords[domainID] = []*linodego.DomainRecord{
+		{ID: 4000, Type: "A", Name: "stray", Target: "192.0.2.100"},
+	}
+	api.mu.Unlock()
+
+	checkLiveRecords := func(expected ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			var live []string
+			for _, record := range api.domainRecords[domainID] {
+				live = append(live, fmt.Sprintf("%s %s %s", record.Type, record.Name, record.Target))
+			}
+			sort.Strings(live)
+			sort.Strings(expected)
+			if strings.Join(live, ",") != strings.Join(expected, ",") {
+				return fmt.Errorf("Expected the domain records %v, got %v", expected, live)
+			}
+			return nil
+		}
+	}
+
+	var wwwID int
+	findWWW := func() int {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		for _, record := range api.domainRecords[domainID] {
+			if record.Type == "A" && record.Name == "www" {
+				return record.ID
+			}
+		}
+		return 0
+	}
+
+	domain := strconv.Itoa(domainID)
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		CheckDestroy: func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if records := api.domainRecords[domainID]; len(records) > 0 {
+				return fmt.Errorf("Expected the domain records to be deleted, %d remain", len(records))
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeDomainRecordsConfigInvalid(domain),
+				ExpectError: regexp.MustCompile("A records must target an IPv4 address"),
+			},
+			{
+				Config: testAccCheckLinodeDomainRecordsConfigBasic(domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "domain_id", domain),
+					resource.TestCheckResourceAttr(resName, "record.#", "3"),
+					checkLiveRecords("A www 192.0.2.1", "MX  mail.example.com", "TXT  v=spf1 -all"),
+					func(*terraform.State) error {
+						wwwID = findWWW()
+						return nil
+					},
+				),
+			},
+			{
+				// The changed www record is updated in place rather than replaced
+				Config: testAccCheckLinodeDomainRecordsConfigUpdates(domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "record.#", "2"),
+					checkLiveRecords("A www 192.0.2.2", "MX  mail.example.com"),
+					func(*terraform.State) error {
+						if id := findWWW(); id != wwwID {
+							return fmt.Errorf("Expected the www record %d to be updated, found record %d", wwwID, id)
+						}
+						if count := api.callCount(fmt.Sprintf("PUT domains/%d/records/%d", domainID, wwwID)); count != 1 {
+							return fmt.Errorf("Expected 1 update of the www record, got %d", count)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// A record added outside of Terraform is found when refreshed and removed
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.domainRecords[domainID] = append(api.domainRecords[domainID], &linodego.DomainRecord{
+						ID: 4001, Type: "CNAME", Name: "stray", Target: "www.example.com",
+					})
+				},
+				Config: testAccCheckLinodeDomainRecordsConfigUpdates(domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "record.#", "2"),
+					checkLiveRecords("A www 192.0.2.2", "MX  mail.example.com"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestLinodeDomainRecords_mockInstanceTarget verifies that records targeting the IP of an instance which is created in
+// the same apply pass validation, and point to the instance
+func TestLinodeDomainRecords_mockInstanceTarget(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	domainID := 5000
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	api.mu.Lock()
+	api.domainRecords[domainID] = []*linodego.DomainRecord{}
+	api.mu.Unlock()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeDomainRecordsConfigInstanceTarget(strconv.Itoa(domainID), instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_domain_records.foobar", "record.#", "2"),
+					func(s *terraform.State) error {
+						address := s.RootModule().Resources["linode_instance.foobar"].Primary.Attributes["ip_address"]
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						for _, record := range api.domainRecords[domainID] {
+							if record.Type == "A" && record.Name == "www" && record.Target == address {
+								return nil
+							}
+						}
+						return fmt.Errorf("Expected an A record for www targeting %s", address)
+					},
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckLinodeDomainRecordsExist verifies that the records of the domain can be listed
+func testAccCheckLinodeDomainRecordsExist(s *terraform.State) error {
+	client := testAccProvider.Meta().(*ProviderMeta).Client
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "linode_domain_records" {
+			continue
+		}
+		domainID, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error parsing %v to int", rs.Primary.ID)
+		}
+		if _, err := client.ListDomainRecords(context.Background(), domainID, nil); err != nil {
+			return fmt.Errorf("Error listing the records of Linode Domain %d: %s", domainID, err)
+		}
+	}
+	return nil
+}
+
+func testAccCheckLinodeDomainRecordsConfigBasic(domainID string) string {
+	return fmt.Sprintf(`
+resource "linode_domain_records" "foobar" {
+	domain_id = "%s"
+
+	record {
+		name = "www"
+		record_type = "A"
+		target = "192.0.2.1"
+	}
+
+	record {
+		name = ""
+		record_type = "MX"
+		target = "mail.example.com"
+		priority = 10
+	}
+
+	record {
+		name = ""
+		record_type = "TXT"
+		target = "v=spf1 -all"
+		ttl_sec = 300
+	}
+}`, domainID)
+}
+
+func testAccCheckLinodeDomainRecordsConfigUpdates(domainID string) string {
+	return fmt.Sprintf(`
+resource "linode_domain_records" "foobar" {
+	domain_id = "%s"
+
+	record {
+		name = "www"
+		record_type = "A"
+		target = "192.0.2.2"
+	}
+
+	record {
+		name = ""
+		record_type = "MX"
+		target = "mail.example.com"
+		priority = 10
+	}
+}`, domainID)
+}
+
+func testAccCheckLinodeDomainRecordsConfigInvalid(domainID string) string {
+	return fmt.Sprintf(`
+resource "linode_domain_records" "foobar" {
+	domain_id = "%s"
+
+	record {
+		name = "www"
+		record_type = "A"
+		target = "2001:db8::1"
+	}
+}`, domainID)
+}
+
+func testAccCheckLinodeDomainRecordsConfigInstanceTarget(domainID string, instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+}
+
+resource "linode_domain_records" "foobar" {
+	domain_id = "%s"
+
+	record {
+		name = "www"
+		record_type = "A"
+		target = "${linode_instance.foobar.ip_address}"
+	}
+
+	record {
+		name = ""
+		record_type = "MX"
+		target = "mail.example.com"
+		priority = 10
+	}
+}`, instance, domainID)
+}
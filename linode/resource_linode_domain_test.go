@@ -116,7 +116,7 @@ func TestAccLinodeDomain_update(t *testing.T) {
 }
 
 func testAccCheckLinodeDomainExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_domain" {
@@ -138,10 +138,7 @@ func testAccCheckLinodeDomainExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeDomainDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_domain" {
 			continue
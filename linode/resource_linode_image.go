@@ -95,7 +95,7 @@ func resourceLinodeImage() *schema.Resource {
 }
 
 func resourceLinodeImageExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	_, err := client.GetImage(context.Background(), d.Id())
 	if err != nil {
@@ -109,7 +109,7 @@ func resourceLinodeImageExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceLinodeImageRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	image, err := client.GetImage(context.Background(), d.Id())
 
@@ -142,10 +142,7 @@ func resourceLinodeImageRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeImageCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Image")
-	}
+	client := meta.(*ProviderMeta).Client
 	d.Partial(true)
 
 	linodeID := d.Get("linode_id").(int)
@@ -187,7 +184,7 @@ func resourceLinodeImageCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeImageUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	image, err := client.GetImage(context.Background(), d.Id())
 	if err != nil {
@@ -217,7 +214,7 @@ func resourceLinodeImageUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeImageDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	err := client.DeleteImage(context.Background(), d.Id())
 	if err != nil {
@@ -123,7 +123,7 @@ func TestAccLinodeImage_update(t *testing.T) {
 }
 
 func testAccCheckLinodeImageExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_Image" {
@@ -139,10 +139,7 @@ func testAccCheckLinodeImageExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeImageDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_Image" {
 			continue
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -20,13 +22,14 @@ const (
 
 func resourceLinodeInstance() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceLinodeInstanceCreate,
-		Read:   resourceLinodeInstanceRead,
-		Update: resourceLinodeInstanceUpdate,
-		Delete: resourceLinodeInstanceDelete,
-		Exists: resourceLinodeInstanceExists,
+		Create:        resourceLinodeInstanceCreate,
+		Read:          resourceLinodeInstanceRead,
+		Update:        resourceLinodeInstanceUpdate,
+		Delete:        resourceLinodeInstanceDelete,
+		Exists:        resourceLinodeInstanceExists,
+		CustomizeDiff: resourceLinodeInstanceCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceLinodeInstanceImport,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(LinodeInstanceCreateTimeout),
@@ -48,6 +51,32 @@ func resourceLinodeInstance() *schema.Resource {
 				ForceNew:      true,
 				ConflictsWith: []string{"image", "disk", "config"},
 			},
+			"clone_target": {
+				Type:          schema.TypeList,
+				Description:   "Overwrites this Linode with a clone of another Linode's disks and configs. All existing disks and configs of this Linode are deleted. The clone is repeated whenever source_linode_id or trigger changes.",
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"disk", "config"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source_linode_id": {
+							Type:        schema.TypeInt,
+							Description: "The ID of the Linode whose disks and configs are cloned onto this Linode.",
+							Required:    true,
+						},
+						"confirm_overwrite": {
+							Type:        schema.TypeBool,
+							Description: "Must be true, acknowledging that all existing disks and configs of this Linode are deleted by the clone.",
+							Required:    true,
+						},
+						"trigger": {
+							Type:        schema.TypeString,
+							Description: "An arbitrary value which repeats the clone from the same source whenever it changes.",
+							Optional:    true,
+						},
+					},
+				},
+			},
 			"stackscript_id": {
 				Type:          schema.TypeInt,
 				Description:   "The StackScript to deploy to the newly created Linode. If provided, 'image' must also be provided, and must be an Image that is compatible with this StackScript.",
@@ -55,6 +84,11 @@ func resourceLinodeInstance() *schema.Resource {
 				ForceNew:      true,
 				ConflictsWith: []string{"disk", "config"},
 			},
+			"stackscript_revision": {
+				Type:        schema.TypeString,
+				Description: "The revision of the StackScript which must be deployed, such as the pinned_revision of a linode_stackscript. Creating the Linode fails if the StackScript was revised since. Only applies when the Linode is created.",
+				Optional:    true,
+			},
 			"stackscript_data": {
 				Type: schema.TypeMap,
 				Elem: &schema.Schema{Type: schema.TypeString},
@@ -71,17 +105,38 @@ func resourceLinodeInstance() *schema.Resource {
 				Optional:     true,
 				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(3, 50),
+				StateFunc:    labelState,
 			},
 			"group": {
 				Type:        schema.TypeString,
 				Description: "The display group of the Linode instance.",
 				Optional:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// A group moved to a tag by group_migration is cleared, which is not a change to the group
+					if old == "" && d.Get("group_migration").(string) == "move" && normalizeGroup(new) == d.Get("migrated_group").(string) {
+						return true
+					}
+					return normalizeGroup(old) == normalizeGroup(new)
+				},
 			},
-			"tags": {
-				Type:        schema.TypeSet,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
+			"group_tag": {
+				Type:        schema.TypeBool,
+				Description: "If true, the group is also applied to the Linode as a tag, to ease the move from display groups to tags. The mirrored tag is not included in tags.",
 				Optional:    true,
-				Description: "An array of tags applied to this object. Tags are for organizational purposes only.",
+				Default:     false,
+			},
+			"group_migration": {
+				Type:         schema.TypeString,
+				Description:  "Migrates the group to a tag once: copy applies the group as a tag, and move also clears the group.",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"copy", "move"}, false),
+			},
+			"migrated_group": {
+				Type:        schema.TypeString,
+				Description: "The group which was migrated to a tag by group_migration.",
+				Computed:    true,
 			},
 			"boot_config_label": {
 				Type:        schema.TypeString,
@@ -89,6 +144,89 @@ func resourceLinodeInstance() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
+			"boot_config_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the Instance Config the Linode instance boots with, the config labeled boot_config_label. 0 when the Linode has no configs.",
+				Computed:    true,
+			},
+			"booted": {
+				Type:        schema.TypeBool,
+				Description: "If true, the instance is kept in (or returned to) a running state. If false, the instance is kept powered off, including after a resize. If unspecified, the instance's power state is preserved.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"disk_expansion_target": {
+				Type:        schema.TypeString,
+				Description: "The disk which is grown into the extra space when the type changes to a plan with more storage. If unset, the Linode API grows the disk of instances with a single disk besides swap. \"biggest\" grows the biggest disk, a disk label grows that disk, and \"none\" leaves the extra space unallocated.",
+				Optional:    true,
+			},
+			"create_config": {
+				Type:          schema.TypeBool,
+				Description:   "If false, the Linode is created with its disks but without a config, and is left powered off until a config is added. The config the Linode API creates when deploying from an Image is deleted. Only applies when the Linode is created.",
+				Optional:      true,
+				Default:       true,
+				ConflictsWith: []string{"config"},
+			},
+			"wait_for_cloud_init": {
+				Type:        schema.TypeBool,
+				Description: "If true, creating the Linode waits until cloud-init finished, by logging in over SSH as root with a key and checking the cloud-init status. Requires an Image with cloud-init, a key authorized for root, and access to port 22 of the Linode from where Terraform runs. If cloud-init fails or does not finish in time, the Linode is kept and tainted.",
+				Optional:    true,
+				Default:     false,
+			},
+			"cloud_init_status": {
+				Type:        schema.TypeString,
+				Description: "The cloud-init status wait_for_cloud_init found when the Linode was created, such as done or error, or unknown when it could not be checked. A Linode whose status is not done is replaced by the next apply, like a tainted resource.",
+				Computed:    true,
+			},
+			"wait_for_cloud_init_private_key": {
+				Type:        schema.TypeString,
+				Description: "The private key, in PEM format, the cloud-init wait logs in as root with. Its public key must be authorized for root, for example through authorized_keys. If not set, the keys of the SSH agent at SSH_AUTH_SOCK are used.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"wait_for_cloud_init_host_key": {
+				Type:         schema.TypeString,
+				Description:  "The public host key, in the authorized_keys format, the SSH server of the Linode must present to the cloud-init wait. If not set, the first host key presented is trusted.",
+				Optional:     true,
+				ValidateFunc: validateSSHHostKey,
+			},
+			"shutdown_before_resize": {
+				Type:        schema.TypeBool,
+				Description: "If true, a running instance is shut down to be resized when its type changes. If false, changing the type of a running instance which is to be kept booted fails instead, so that it can be shut down deliberately.",
+				Optional:    true,
+				Default:     true,
+			},
+			"shutdown_timeout": {
+				Type:         schema.TypeInt,
+				Description:  "If set, a running instance is gracefully shut down before it is deleted, waiting up to this many seconds. If the shutdown does not complete in time, the instance is deleted anyway.",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"create_image_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "If true, the root disk of the instance is captured into a private Image labeled destroy_image_label before the instance is deleted.",
+				Optional:    true,
+			},
+			"destroy_image_label": {
+				Type:        schema.TypeString,
+				Description: "The label of the Image captured before the instance is deleted, when create_image_on_destroy is true.",
+				Computed:    true,
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Description: "If true, the instance can't be deleted, including when a change requires it to be replaced. Set it to false and apply before deleting the instance.",
+				Optional:    true,
+			},
+			"ignore_extra_configs": {
+				Type:        schema.TypeBool,
+				Description: "If true, configs of the instance which are not managed by config blocks, such as a rescue config created outside of Terraform, are left untouched and are not read into state.",
+				Optional:    true,
+			},
+			"placement_group_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the Placement Group to assign this Linode to. The Placement Group must be in the same region as the Linode.",
+				Optional:    true,
+			},
 			"region": {
 				Type:         schema.TypeString,
 				Description:  "This is the location where the Linode was deployed. This cannot be changed without opening a support ticket.",
@@ -102,6 +240,66 @@ func resourceLinodeInstance() *schema.Resource {
 				Optional:    true,
 				Default:     "g6-standard-1",
 			},
+			"linode_id": {
+				Type:        schema.TypeInt,
+				Description: "The numeric ID of the Linode instance, as an integer.",
+				Computed:    true,
+			},
+			"hypervisor": {
+				Type:        schema.TypeString,
+				Description: "The virtualization software powering this Linode, such as kvm.",
+				Computed:    true,
+			},
+			"host_uuid": {
+				Type:        schema.TypeString,
+				Description: "The UUID of the host this Linode runs on, when provided by the API. Linodes on the same host share this value.",
+				Computed:    true,
+			},
+			"maintenance_policy": {
+				Type:        schema.TypeString,
+				Description: "The policy applied to this Linode when its host is under maintenance, such as linode/migrate or linode/power_off_on. The accepted policies are listed by the Linode API. Defaults to the account's default policy.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"pending_jobs": {
+				Type:        schema.TypeInt,
+				Description: "The number of scheduled or started jobs (events) of this Linode at the last refresh.",
+				Computed:    true,
+			},
+			"reboot_required": {
+				Type:        schema.TypeBool,
+				Description: "Whether the boot config of this running Linode was updated after it last booted, so the Linode must be rebooted for the changes to take effect.",
+				Computed:    true,
+			},
+			"power_events": {
+				Type:        schema.TypeList,
+				Description: "The most recent boot, shutdown and reboot events of this Linode at the last refresh, newest first.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"action": {
+							Type:        schema.TypeString,
+							Description: "The power action, such as linode_boot, linode_shutdown, linode_reboot or lassie_reboot.",
+							Computed:    true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The status of the event, such as started or finished.",
+							Computed:    true,
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Description: "The user which caused the event. Events caused by the Linode platform have no user.",
+							Computed:    true,
+						},
+						"created": {
+							Type:        schema.TypeString,
+							Description: "When the event was created.",
+							Computed:    true,
+						},
+					},
+				},
+			},
 			"status": {
 				Type:        schema.TypeString,
 				Description: "The status of the instance, indicating the current readiness state.",
@@ -112,11 +310,67 @@ func resourceLinodeInstance() *schema.Resource {
 				Description: "This Linode's Public IPv4 Address. If there are multiple public IPv4 addresses on this Instance, an arbitrary address will be used for this field.",
 				Computed:    true,
 			},
+			"ip_gateway": {
+				Type:        schema.TypeString,
+				Description: "The default gateway of this Linode's Public IPv4 Address, for configuring the network statically.",
+				Computed:    true,
+			},
+			"ip_subnet_mask": {
+				Type:        schema.TypeString,
+				Description: "The subnet mask of this Linode's Public IPv4 Address, for configuring the network statically.",
+				Computed:    true,
+			},
+			"ssh_connection": {
+				Type:        schema.TypeList,
+				Description: "The SSH connection details of this Linode's Public IPv4 Address, which provisioners use by default.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:        schema.TypeString,
+							Description: "The Public IPv4 Address to connect to.",
+							Computed:    true,
+						},
+						"user": {
+							Type:        schema.TypeString,
+							Description: "The user to connect as.",
+							Computed:    true,
+						},
+						"port": {
+							Type:        schema.TypeInt,
+							Description: "The SSH port to connect to.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"rdns_ipv6": {
+				Type:         schema.TypeString,
+				Description:  "The reverse DNS assigned to this Linode's IPv6 SLAAC address.",
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(3, 254),
+			},
 			"ipv6": {
 				Type:        schema.TypeString,
 				Description: "This Linode's IPv6 SLAAC addresses. This address is specific to a Linode, and may not be shared.",
 				Computed:    true,
 			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Description: "A legal hostname derived from the Linode's label, suitable for configuring the guest. The Linode API does not configure guest hostnames.",
+				Computed:    true,
+			},
+			"ipv6_link_local": {
+				Type:        schema.TypeString,
+				Description: "This Linode's IPv6 link-local address.",
+				Computed:    true,
+			},
+			"ipv6_ranges": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IPv6 ranges, such as a /64 pool, which are routed to this Linode in addition to its SLAAC address.",
+				Computed:    true,
+			},
 
 			"ipv4": {
 				Type:        schema.TypeSet,
@@ -125,6 +379,26 @@ func resourceLinodeInstance() *schema.Resource {
 				Computed:    true,
 			},
 
+			"ipv4_reserved": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The reserved IPv4 addresses attached to this Linode, whether or not they are managed by reserved_ipv4.",
+				Computed:    true,
+			},
+			"ipv4_shared": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IPv4 addresses of other Linodes which are shared with this Linode by IP sharing.",
+				Computed:    true,
+			},
+
+			"reserved_ipv4": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Reserved IPv4 addresses to attach to this Linode as additional public addresses. Reserved addresses must be in the same region as the Linode and are kept by the account when detached.",
+				Optional:    true,
+			},
+
 			"private_ip": {
 				Type:        schema.TypeBool,
 				Description: "If true, the created Linode will have private networking enabled, allowing use of the 192.168.128.0/17 network within the Linode's region.",
@@ -138,7 +412,7 @@ func resourceLinodeInstance() *schema.Resource {
 			"authorized_keys": {
 				Type:          schema.TypeList,
 				Elem:          &schema.Schema{Type: schema.TypeString},
-				Description:   "A list of SSH public keys to deploy for the root user on the newly created Linode. Only accepted if 'image' is provided.",
+				Description:   "A list of SSH public keys to deploy for the root user on the newly created Linode. An element may hold several keys, one per line, e.g. the contents of an authorized_keys file. Only accepted if 'image' is provided.",
 				Optional:      true,
 				ForceNew:      true,
 				StateFunc:     sshKeyState,
@@ -155,7 +429,15 @@ func resourceLinodeInstance() *schema.Resource {
 			},
 			"root_pass": {
 				Type:          schema.TypeString,
-				Description:   "The password that will be initialially assigned to the 'root' user account.",
+				Description:   "The password that will be initialially assigned to the 'root' user account. Changing it resets the password on the root disk in place. Only a hash of the password is stored in state.",
+				Sensitive:     true,
+				Optional:      true,
+				StateFunc:     rootPasswordState,
+				ConflictsWith: []string{"disk", "config"},
+			},
+			"disk_root_pass": {
+				Type:          schema.TypeString,
+				Description:   "The password the Image is deployed with, if it should differ from root_pass. Defaults to root_pass. Only a hash of the password is stored in state.",
 				Sensitive:     true,
 				Optional:      true,
 				ForceNew:      true,
@@ -163,12 +445,23 @@ func resourceLinodeInstance() *schema.Resource {
 				ConflictsWith: []string{"disk", "config"},
 			},
 			"swap_size": {
-				Type:          schema.TypeInt,
-				Description:   "When deploying from an Image, this field is optional with a Linode API default of 512mb, otherwise it is ignored. This is used to set the swap disk size for the newly-created Linode.",
+				Type:          schema.TypeString,
+				Description:   "When deploying from an Image, this field is optional with a Linode API default of 512mb, otherwise it is ignored. This is used to set the swap disk size for the newly-created Linode. The size is in MB, unless it has an MB or GB suffix.",
 				Optional:      true,
 				Computed:      true,
 				Default:       nil,
 				ConflictsWith: []string{"disk", "config"},
+				ValidateFunc:  validateSwapSize,
+				StateFunc:     swapSizeState,
+			},
+			"helpers": {
+				Type:          schema.TypeList,
+				Description:   "The boot helpers of the config the Linode API creates when deploying from an Image. Use the helpers of each config when configs are managed with config blocks.",
+				MaxItems:      1,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"disk", "config"},
+				Elem:          instanceConfigHelpersResource(),
 			},
 			"backups_enabled": {
 				Type:        schema.TypeBool,
@@ -181,7 +474,7 @@ func resourceLinodeInstance() *schema.Resource {
 				Type:        schema.TypeBool,
 				Description: "The watchdog, named Lassie, is a Shutdown Watchdog that monitors your Linode and will reboot it if it powers off unexpectedly. It works by issuing a boot job when your Linode powers off without a shutdown job being responsible. To prevent a loop, Lassie will give up if there have been more than 5 boot jobs issued within 15 minutes.",
 				Optional:    true,
-				Default:     true,
+				Computed:    true,
 			},
 			"specs": {
 				Computed: true,
@@ -213,6 +506,12 @@ func resourceLinodeInstance() *schema.Resource {
 				},
 			},
 
+			"transfer_reset_date": {
+				Type:        schema.TypeString,
+				Description: "An estimate of when the monthly network transfer of this Linode, counted against specs.transfer and the account's transfer pool, is next reset. The API does not report the reset date, so this is the start of the next calendar month in UTC.",
+				Computed:    true,
+			},
+
 			"alerts": {
 				Computed: true,
 				Type:     schema.TypeList,
@@ -239,10 +538,11 @@ func resourceLinodeInstance() *schema.Resource {
 							Description: "The amount of outbound traffic, in Mbit/s, required to trigger an alert. If the average outbound traffic over two hours exceeds this value, we'll send you an alert. If this is set to 0 (zero), the alert is disabled.",
 						},
 						"transfer_quota": {
-							Type:        schema.TypeInt,
-							Computed:    true,
-							Optional:    true,
-							Description: "The percentage of network transfer that may be used before an alert is triggered. When this value is exceeded, we'll alert you. If this is set to 0 (zero), the alert is disabled.",
+							Type:         schema.TypeInt,
+							Computed:     true,
+							Optional:     true,
+							Description:  "The percentage of network transfer that may be used before an alert is triggered, from 0 to 100. When this value is exceeded, we'll alert you. If this is set to 0 (zero), the alert is disabled.",
+							ValidateFunc: validation.IntBetween(0, 100),
 						},
 						"io": {
 							Type:        schema.TypeInt,
@@ -253,10 +553,97 @@ func resourceLinodeInstance() *schema.Resource {
 					},
 				},
 			},
+			"alert_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of an alert_profile of the provider whose thresholds are applied to this Linode. Thresholds set in alerts override the profile.",
+			},
+			"alert_profile_thresholds": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Computed:    true,
+				Description: "The thresholds of the alert_profile when they were last applied to this Linode.",
+			},
+			"alerts_enabled": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The alerts which are enabled on this Linode, those with a threshold other than 0: cpu, io, network_in, network_out and transfer_quota.",
+			},
+			"available_backups": {
+				Type:        schema.TypeList,
+				Description: "The backups of this Linode which can be restored, when the Backup service is enabled. Automatic backups are listed first, followed by the current and in progress snapshots.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Description: "The ID of the backup, to restore from.",
+							Computed:    true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "The type of the backup, auto or snapshot.",
+							Computed:    true,
+						},
+						"label": {
+							Type:        schema.TypeString,
+							Description: "The label of a snapshot. Automatic backups have no label.",
+							Computed:    true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The status of the backup, such as successful, pending or running.",
+							Computed:    true,
+						},
+						"created": {
+							Type:        schema.TypeString,
+							Description: "When the backup was started.",
+							Computed:    true,
+						},
+						"finished": {
+							Type:        schema.TypeString,
+							Description: "When the backup finished, if it has.",
+							Computed:    true,
+						},
+						"configs": {
+							Type:        schema.TypeList,
+							Description: "The labels of the configs included in the backup.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"disks": {
+							Type:        schema.TypeList,
+							Description: "The disks included in the backup.",
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"label": {
+										Type:        schema.TypeString,
+										Description: "The label of the disk.",
+										Computed:    true,
+									},
+									"size": {
+										Type:        schema.TypeInt,
+										Description: "The size of the disk in MB.",
+										Computed:    true,
+									},
+									"filesystem": {
+										Type:        schema.TypeString,
+										Description: "The filesystem of the disk.",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			"backups": {
 				Type:        schema.TypeList,
 				MaxItems:    1,
 				Description: "Information about this Linode's backups status.",
+				Optional:    true,
 				Computed:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -268,19 +655,25 @@ func resourceLinodeInstance() *schema.Resource {
 						"schedule": {
 							Type:     schema.TypeList,
 							MaxItems: 1,
+							Optional: true,
 							Computed: true,
 							Elem: &schema.Resource{
-								// TODO(displague) these fields are updatable via PUT to instance
 								Schema: map[string]*schema.Schema{
 									"day": {
-										Type:        schema.TypeString,
-										Description: "The day ('Sunday'-'Saturday') of the week that your Linode's weekly Backup is taken. If not set manually, a day will be chosen for you. Backups are taken every day, but backups taken on this day are preferred when selecting backups to retain for a longer period.  If not set manually, then when backups are initially enabled, this may come back as 'Scheduling' until the day is automatically selected.",
-										Computed:    true,
+										Type:             schema.TypeString,
+										Description:      "The day ('Sunday'-'Saturday') of the week that your Linode's weekly Backup is taken. If not set manually, a day will be chosen for you. Backups are taken every day, but backups taken on this day are preferred when selecting backups to retain for a longer period.  If not set manually, then when backups are initially enabled, this may come back as 'Scheduling' until the day is automatically selected.",
+										Optional:         true,
+										Computed:         true,
+										ValidateFunc:     validateBackupScheduleDay,
+										DiffSuppressFunc: backupScheduleDiffSuppressFunc(normalizeBackupScheduleDay),
 									},
 									"window": {
-										Type:        schema.TypeString,
-										Description: "The window ('W0'-'W22') in which your backups will be taken, in UTC. A backups window is a two-hour span of time in which the backup may occur. For example, 'W10' indicates that your backups should be taken between 10:00 and 12:00. If you do not choose a backup window, one will be selected for you automatically.  If not set manually, when backups are initially enabled this may come back as Scheduling until the window is automatically selected.",
-										Computed:    true,
+										Type:             schema.TypeString,
+										Description:      "The window ('W0'-'W22') in which your backups will be taken, in UTC. A backups window is a two-hour span of time in which the backup may occur. For example, 'W10' indicates that your backups should be taken between 10:00 and 12:00. If you do not choose a backup window, one will be selected for you automatically.  If not set manually, when backups are initially enabled this may come back as Scheduling until the window is automatically selected.",
+										Optional:         true,
+										Computed:         true,
+										ValidateFunc:     validateBackupScheduleWindow,
+										DiffSuppressFunc: backupScheduleDiffSuppressFunc(normalizeBackupScheduleWindow),
 									},
 								},
 							},
@@ -311,37 +704,28 @@ func resourceLinodeInstance() *schema.Resource {
 							MaxItems:    1,
 							Optional:    true,
 							Computed:    true,
+							Elem:        instanceConfigHelpersResource(),
+						},
+						"interfaces": {
+							Type:        schema.TypeList,
+							Description: "The network interfaces of this config, such as VLAN attachments, as reported by the Linode API.",
+							Computed:    true,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
-									"updatedb_disabled": {
-										Type:        schema.TypeBool,
-										Description: "Disables updatedb cron job to avoid disk thrashing.",
-										Optional:    true,
-										Default:     true,
-									},
-									"distro": {
-										Type:        schema.TypeBool,
-										Description: "Controls the behavior of the Linode Config's Distribution Helper setting.",
-										Optional:    true,
-										Default:     true,
-									},
-									"modules_dep": {
-										Type:        schema.TypeBool,
-										Description: "Creates a modules dependency file for the Kernel you run.",
-										Optional:    true,
-										Default:     true,
+									"purpose": {
+										Type:        schema.TypeString,
+										Description: "The type of the interface, such as public or vlan.",
+										Computed:    true,
 									},
-									"network": {
-										Type:        schema.TypeBool,
-										Optional:    true,
-										Description: "Controls the behavior of the Linode Config's Network Helper setting, used to automatically configure additional IP addresses assigned to this instance.",
-										Default:     true,
+									"label": {
+										Type:        schema.TypeString,
+										Description: "The label of the VLAN the interface is attached to. Empty for public interfaces.",
+										Computed:    true,
 									},
-									"devtmpfs_automount": {
-										Type:        schema.TypeBool,
-										Description: "Populates the /dev directory early during boot without udev. Defaults to false.",
-										Optional:    true,
-										Default:     false,
+									"ipam_address": {
+										Type:        schema.TypeString,
+										Description: "The IPAM address of the interface in CIDR notation, if any.",
+										Computed:    true,
 									},
 								},
 							},
@@ -364,9 +748,10 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:        schema.TypeString,
-													Optional:    true,
-													Description: "The `label` of the `disk` to map to this `device` slot.",
+													Type:         schema.TypeString,
+													Optional:     true,
+													Description:  "The `label` of the `disk` to map to this `device` slot.",
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:        schema.TypeInt,
@@ -391,8 +776,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -415,8 +801,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -439,8 +826,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -463,8 +851,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -487,8 +876,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -511,8 +901,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -536,8 +927,9 @@ func resourceLinodeInstance() *schema.Resource {
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"disk_label": {
-													Type:     schema.TypeString,
-													Optional: true,
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringLenBetween(0, instanceDiskLabelMaxLength),
 												},
 												"disk_id": {
 													Type:     schema.TypeInt,
@@ -579,16 +971,29 @@ func resourceLinodeInstance() *schema.Resource {
 							Computed:    true,
 							Description: "The root device to boot. The corresponding disk must be attached.",
 						},
+						"root_device_num": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							Description:  "The device slot of the root disk, from 1 (sda) to 8 (sdh). The corresponding disk must be attached. Defaults to 1. A changed root_device_num takes precedence over root_device.",
+							ValidateFunc: validation.IntBetween(1, len(instanceConfigDeviceSlots)),
+						},
 						"comments": {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Description: "Optional field for arbitrary User comments on this Config.",
 						},
+						"initrd": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The label of a disk in this Linode's disk list which contains the initrd (init ramdisk) to boot this Config with.",
+						},
 
 						"memory_limit": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Defaults to the total RAM of the Linode",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Defaults to the total RAM of the Linode",
+							ValidateFunc: validation.IntAtLeast(0),
 						},
 					},
 				},
@@ -607,7 +1012,7 @@ func resourceLinodeInstance() *schema.Resource {
 							Type:         schema.TypeString,
 							Description:  "The disks label, which acts as an identifier in Terraform.",
 							Required:     true,
-							ValidateFunc: validation.StringLenBetween(1, 48),
+							ValidateFunc: validation.StringLenBetween(1, instanceDiskLabelMaxLength),
 						},
 						"size": {
 							Type:        schema.TypeInt,
@@ -629,7 +1034,7 @@ func resourceLinodeInstance() *schema.Resource {
 						},
 						"read_only": {
 							Type:        schema.TypeBool,
-							Description: "If true, this Disk is read-only.",
+							Description: "If true, this Disk is read-only. This can be used to mount the root device read-only.",
 							Optional:    true,
 							Computed:    true,
 							ForceNew:    true,
@@ -648,7 +1053,7 @@ func resourceLinodeInstance() *schema.Resource {
 						"authorized_keys": {
 							Type:        schema.TypeList,
 							Elem:        &schema.Schema{Type: schema.TypeString},
-							Description: "A list of SSH public keys to deploy for the root user on the newly created Linode. Only accepted if 'image' is provided.",
+							Description: "A list of SSH public keys to deploy for the root user on the newly created Linode. An element may hold several keys, one per line, e.g. the contents of an authorized_keys file. Only accepted if 'image' is provided.",
 							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 								// the API does not return this field for existing disks, so must be ignored for diffs/updates
 								return !d.HasChange("label")
@@ -727,8 +1132,47 @@ func validateAll(validators ...schema.SchemaValidateFunc) schema.SchemaValidateF
 	}
 }
 
+// instanceConfigHelpersResource is the schema of the boot helpers of a config, shared by the helpers of each config
+// and the top-level helpers of an instance deployed from an image
+func instanceConfigHelpersResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"updatedb_disabled": {
+				Type:        schema.TypeBool,
+				Description: "Disables updatedb cron job to avoid disk thrashing.",
+				Optional:    true,
+				Default:     true,
+			},
+			"distro": {
+				Type:        schema.TypeBool,
+				Description: "Controls the behavior of the Linode Config's Distribution Helper setting.",
+				Optional:    true,
+				Default:     true,
+			},
+			"modules_dep": {
+				Type:        schema.TypeBool,
+				Description: "Creates a modules dependency file for the Kernel you run.",
+				Optional:    true,
+				Default:     true,
+			},
+			"network": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Controls the behavior of the Linode Config's Network Helper setting, used to automatically configure additional IP addresses assigned to this instance. Defaults to the account's Network Helper setting.",
+			},
+			"devtmpfs_automount": {
+				Type:        schema.TypeBool,
+				Description: "Populates the /dev directory early during boot without udev. Defaults to false.",
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
 func resourceLinodeInstanceExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 
 	if err != nil {
@@ -746,14 +1190,37 @@ func resourceLinodeInstanceExists(d *schema.ResourceData, meta interface{}) (boo
 	return true, nil
 }
 
+// resourceLinodeInstanceImport sets private_ip from the private IPs of the instance, since refreshes only clear the
+// private_ip of instances which Terraform configured with a private IP
+func resourceLinodeInstanceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*ProviderMeta).Client
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing Linode instance ID %s as int: %s", d.Id(), err)
+	}
+
+	instanceNetwork, err := client.GetInstanceIPAddresses(context.Background(), int(id))
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			// Read reports the missing instance
+			return []*schema.ResourceData{d}, nil
+		}
+		return nil, fmt.Errorf("Error getting the IPs for Linode instance %s: %s", d.Id(), err)
+	}
+	d.Set("private_ip", len(instanceNetwork.IPv4.Private) > 0)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceLinodeInstanceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode instance ID %s as int: %s", d.Id(), err)
 	}
 
-	instance, err := client.GetInstance(context.Background(), int(id))
+	instance, extras, err := getInstanceWithExtras(client, int(id))
 
 	if err != nil {
 		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
@@ -765,7 +1232,7 @@ func resourceLinodeInstanceRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error finding the specified Linode instance: %s", err)
 	}
 
-	instanceNetwork, err := client.GetInstanceIPAddresses(context.Background(), int(id))
+	instanceNetwork, reservedIPs, sharedIPs, err := getInstanceIPAddressesWithAdditional(client, int(id))
 
 	if err != nil {
 		return fmt.Errorf("Error getting the IPs for Linode instance %s: %s", d.Id(), err)
@@ -777,10 +1244,28 @@ func resourceLinodeInstanceRead(d *schema.ResourceData, meta interface{}) error
 	}
 	d.Set("ipv4", ips)
 	d.Set("ipv6", instance.IPv6)
+	if instanceNetwork.IPv6 != nil && instanceNetwork.IPv6.SLAAC != nil {
+		d.Set("rdns_ipv6", instanceNetwork.IPv6.SLAAC.RDNS)
+	}
+	ipv6LinkLocal := ""
+	ipv6Ranges := []string{}
+	if instanceNetwork.IPv6 != nil {
+		if instanceNetwork.IPv6.LinkLocal != nil {
+			ipv6LinkLocal = instanceNetwork.IPv6.LinkLocal.Address
+		}
+		for _, ipv6Range := range instanceNetwork.IPv6.Global {
+			ipv6Ranges = append(ipv6Ranges, ipv6Range.Range)
+		}
+	}
+	d.Set("ipv6_link_local", ipv6LinkLocal)
+	d.Set("ipv6_ranges", ipv6Ranges)
 	public, private := instanceNetwork.IPv4.Public, instanceNetwork.IPv4.Private
 
+	sshConnection := []map[string]interface{}{}
 	if len(public) > 0 {
 		d.Set("ip_address", public[0].Address)
+		d.Set("ip_gateway", public[0].Gateway)
+		d.Set("ip_subnet_mask", public[0].SubnetMask)
 
 		d.SetConnInfo(map[string]string{
 			"type": "ssh",
@@ -789,22 +1274,79 @@ func resourceLinodeInstanceRead(d *schema.ResourceData, meta interface{}) error
 		// TODO(displague) to determine 'user', need to check disk.image
 		// "linode/containerlinux" is "core", else "root"
 		// might be better to make this a resource field and avoid lookups
+		sshConnection = append(sshConnection, map[string]interface{}{
+			"host": public[0].Address,
+			"user": "root",
+			"port": 22,
+		})
+	}
+	if err := d.Set("ssh_connection", sshConnection); err != nil {
+		return fmt.Errorf("Error setting Linode Instance ssh_connection: %s", err)
 	}
 
+	// private_ip is only cleared here. A private IP added outside of Terraform is reported in private_ip_address,
+	// but does not set private_ip, so that Terraform does not take over, or try to remove, an address it did not add.
 	if len(private) > 0 {
-		d.Set("private_ip", true)
+		if !d.Get("private_ip").(bool) {
+			log.Printf("[DEBUG] Linode instance %d has private IP %s, which was not added by Terraform", instance.ID, private[0].Address)
+		}
 		d.Set("private_ip_address", private[0].Address)
 	} else {
 		d.Set("private_ip", false)
+		d.Set("private_ip_address", "")
 	}
 
 	d.Set("label", instance.Label)
+	d.Set("hostname", labelHostname(instance.Label))
+	d.Set("linode_id", instance.ID)
 	d.Set("status", instance.Status)
+	if booted, ok := instanceBootedState(*instance); ok {
+		d.Set("booted", booted)
+	}
 	d.Set("type", instance.Type)
 	d.Set("region", instance.Region)
+	d.Set("hypervisor", instance.Hypervisor)
 	d.Set("watchdog_enabled", instance.WatchdogEnabled)
 	d.Set("group", instance.Group)
-	d.Set("tags", instance.Tags)
+	if err := readTags(d, meta, "linode", instance.ID, flattenInstanceTags(d, instance.Tags)); err != nil {
+		return err
+	}
+	// tags_all also lists the tags applied from the group, which are hidden from tags
+	if err := d.Set("tags_all", instance.Tags); err != nil {
+		return fmt.Errorf("Error setting tags_all of linode %d: %s", instance.ID, err)
+	}
+	// group_tag is not known to the API, keep the configured value (or the default, on import)
+	d.Set("group_tag", d.Get("group_tag").(bool))
+	d.Set("migrated_group", d.Get("migrated_group").(string))
+	destroyImageLabel := ""
+	if d.Get("create_image_on_destroy").(bool) {
+		destroyImageLabel, _ = imageBeforeDeleteLabel(instance.Label, instance.ID)
+	}
+	d.Set("destroy_image_label", destroyImageLabel)
+	// shutdown_before_resize is not known to the API either, but defaults to true
+	if _, ok := d.GetOkExists("shutdown_before_resize"); !ok {
+		d.Set("shutdown_before_resize", true)
+	}
+	// create_config only applies when the instance is created either
+	if _, ok := d.GetOkExists("create_config"); !ok {
+		d.Set("create_config", true)
+	}
+	// wait_for_cloud_init only applies when the instance is created, imported instances never waited
+	if _, ok := d.GetOkExists("wait_for_cloud_init"); !ok {
+		d.Set("wait_for_cloud_init", false)
+	}
+
+	placementGroupID := 0
+	if extras.PlacementGroup != nil {
+		placementGroupID = extras.PlacementGroup.ID
+	}
+	d.Set("placement_group_id", placementGroupID)
+	d.Set("host_uuid", extras.HostUUID)
+	d.Set("maintenance_policy", extras.MaintenancePolicy)
+
+	d.Set("reserved_ipv4", reservedIPs)
+	d.Set("ipv4_reserved", reservedIPs)
+	d.Set("ipv4_shared", sharedIPs)
 
 	flatSpecs := flattenInstanceSpecs(*instance)
 	flatAlerts := flattenInstanceAlerts(*instance)
@@ -813,14 +1355,56 @@ func resourceLinodeInstanceRead(d *schema.ResourceData, meta interface{}) error
 	if err := d.Set("backups", flatBackups); err != nil {
 		return fmt.Errorf("Error setting Linode Instance backups: %s", err)
 	}
+	d.Set("backups_enabled", instance.Backups.Enabled)
+
+	if !instance.Backups.Enabled {
+		if err := d.Set("available_backups", []map[string]interface{}{}); err != nil {
+			return fmt.Errorf("Error setting Linode Instance available_backups: %s", err)
+		}
+	}
 
 	if err := d.Set("specs", flatSpecs); err != nil {
 		return fmt.Errorf("Error setting Linode Instance specs: %s", err)
 	}
+	// The API reports the transfer used, but not when it is reset, so the reset date is estimated from the clock
+	d.Set("transfer_reset_date", instanceTransferResetDate(time.Now()).Format(time.RFC3339))
 
 	if err := d.Set("alerts", flatAlerts); err != nil {
 		return fmt.Errorf("Error setting Linode Instance alerts: %s", err)
 	}
+	d.Set("alerts_enabled", flattenInstanceAlertsEnabled(*instance))
+	// alert_profile is not known to the API, keep the configured profile and the thresholds it last applied
+	d.Set("alert_profile_thresholds", d.Get("alert_profile_thresholds").(map[string]interface{}))
+
+	// Disks, configs, events and backups keep their values from state when the provider is configured to skip reading them.
+	// They are always read for new and imported instances, which have none in state.
+	if meta.(*ProviderMeta).Config.SkipInstanceReadyPoll && !d.IsNewResource() &&
+		(len(d.Get("disk").([]interface{})) > 0 || len(d.Get("config").([]interface{})) > 0) {
+		log.Printf("[DEBUG] skipping the disk and config refresh of Linode instance %d", instance.ID)
+		return nil
+	}
+
+	// The events are only informational, so pending_jobs, power_events and reboot_required keep their values from state
+	// when they can't be listed
+	events, eventsErr := listInstanceEvents(client, instance.ID)
+	if eventsErr != nil {
+		log.Printf("[WARN] Error getting the events for Linode instance %d, keeping pending_jobs, power_events and reboot_required: %s", instance.ID, eventsErr)
+	} else {
+		d.Set("pending_jobs", countPendingInstanceJobs(events))
+		if err := d.Set("power_events", flattenInstancePowerEvents(events)); err != nil {
+			return fmt.Errorf("Error setting Linode Instance power_events: %s", err)
+		}
+	}
+
+	// Like the events, available_backups keeps its value from state when the backups can't be listed
+	if instance.Backups.Enabled {
+		backups, err := client.GetInstanceBackups(context.Background(), instance.ID)
+		if err != nil {
+			log.Printf("[WARN] Error getting the backups for Linode instance %d, keeping available_backups: %s", instance.ID, err)
+		} else if err := d.Set("available_backups", flattenInstanceAvailableBackups(backups)); err != nil {
+			return fmt.Errorf("Error setting Linode Instance available_backups: %s", err)
+		}
+	}
 
 	instanceDisks, err := client.ListInstanceDisks(context.Background(), int(id), nil)
 
@@ -828,53 +1412,210 @@ func resourceLinodeInstanceRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error getting the disks for the Linode instance %d: %s", id, err)
 	}
 
-	disks, swapSize := flattenInstanceDisks(instanceDisks)
-
-	if err := d.Set("disk", disks); err != nil {
-		return fmt.Errorf("Erroring setting Linode Instance disk: %s", err)
+	instanceConfigs, configInterfaces, err := listInstanceConfigsWithInterfaces(client, int(id))
+
+	if err != nil {
+		return fmt.Errorf("Error getting the config for Linode instance %d (%s): %s", instance.ID, instance.Label, err)
+	}
+	if d.Get("ignore_extra_configs").(bool) {
+		instanceConfigs = managedInstanceConfigs(instanceConfigs, d.Get("config").([]interface{}), d.Get("boot_config_label").(string))
+	}
+	bootConfigLabel := d.Get("boot_config_label").(string)
+	bootConfig := findBootConfig(instanceConfigs, bootConfigLabel)
+	if eventsErr == nil {
+		d.Set("reboot_required", instanceRebootRequired(instance.Status, bootConfig, lastInstanceBoot(events)))
+	}
+
+	disks, swapSize := flattenInstanceDisks(instanceDisks)
+	if stateDisks := d.Get("disk").([]interface{}); len(stateDisks) > 0 {
+		disks = sortInstanceDisksByState(disks, stateDisks)
+	} else {
+		// Without disks in state, as on import, the disks follow the device order of the boot config rather than
+		// the creation order the API returns them in
+		disks = sortInstanceDisksByDevices(disks, bootConfig)
+	}
+
+	// The API does not report whether a disk is read-only, retain the value from state
+	retainInstanceDiskFields(disks, d.Get("disk").([]interface{}), "read_only")
+
+	if err := d.Set("disk", disks); err != nil {
+		return fmt.Errorf("Erroring setting Linode Instance disk: %s", err)
+	}
+
+	d.Set("swap_size", strconv.Itoa(swapSize))
+
+	diskLabelIDMap := make(map[int]string, len(instanceDisks))
+	for _, disk := range instanceDisks {
+		diskLabelIDMap[disk.ID] = disk.Label
+
+	}
+
+	configs := flattenInstanceConfigs(instanceConfigs, diskLabelIDMap)
+
+	for i, config := range instanceConfigs {
+		configs[i]["interfaces"] = flattenInstanceConfigInterfaces(configInterfaces[config.ID])
+	}
+
+	if err := d.Set("config", configs); err != nil {
+		return fmt.Errorf("Erroring setting Linode Instance config: %s", err)
+	}
+
+	if bootConfig != nil {
+		// The top-level helpers follow the boot config, which is the config the API created for an Image deployment
+		if bootConfig.Helpers != nil {
+			if err := d.Set("helpers", flattenInstanceConfigHelpers(bootConfig.Helpers)); err != nil {
+				return fmt.Errorf("Error setting Linode Instance helpers: %s", err)
+			}
+		}
+		if len(instanceConfigs) > 1 && bootConfig.Label != bootConfigLabel {
+			log.Printf("[WARN] Linode instance %d has %d configs and none match boot_config_label %q, using config %q", instance.ID, len(instanceConfigs), bootConfigLabel, bootConfig.Label)
+		}
+		d.Set("boot_config_label", bootConfig.Label)
+		d.Set("boot_config_id", bootConfig.ID)
+	} else {
+		// An instance may have no configs while it is provisioned, when it was created with create_config = false, or
+		// after they were deleted outside of Terraform
+		if bootConfigLabel != "" {
+			log.Printf("[WARN] Linode instance %d has no configs, clearing boot_config_label %q", instance.ID, bootConfigLabel)
+		}
+		d.Set("boot_config_label", "")
+		d.Set("boot_config_id", 0)
+		d.Set("helpers", []interface{}{})
+	}
+
+	return nil
+}
+
+// sliceContains tells whether a contains x.
+func sliceContains(a []string, x string) bool {
+	for _, n := range a {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceLinodeInstanceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange("image") || d.HasChange("type") || d.HasChange("swap_size") {
+		if err := validateInstanceImageFitsType(meta.(*ProviderMeta).Client, d); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("type") || d.HasChange("region") || d.HasChange("config") {
+		if err := validateInstanceGPUType(meta.(*ProviderMeta).Client, d); err != nil {
+			return err
+		}
+	}
+
+	if err := validateInstanceCloudInitWait(d); err != nil {
+		return err
+	}
+
+	if err := customizeInstanceCloudInitStatusDiff(d); err != nil {
+		return err
+	}
+
+	if err := validateInstanceCreateConfig(d); err != nil {
+		return err
+	}
+
+	if d.Id() == "" && d.NewValueKnown("stackscript_id") && d.NewValueKnown("stackscript_revision") {
+		if revision := d.Get("stackscript_revision").(string); revision != "" {
+			if err := validateStackscriptRevision(meta.(*ProviderMeta).Client, d.Get("stackscript_id").(int), revision); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("maintenance_policy") {
+		if err := validateInstanceMaintenancePolicy(meta.(*ProviderMeta).Client, d); err != nil {
+			return err
+		}
+	}
+
+	if err := customizeInstanceAlertProfileDiff(d, meta.(*ProviderMeta).Config.AlertProfiles); err != nil {
+		return err
 	}
 
-	d.Set("swap_size", swapSize)
+	if err := customizeInstanceGroupMigrationDiff(d); err != nil {
+		return err
+	}
 
-	instanceConfigs, err := client.ListInstanceConfigs(context.Background(), int(id), nil)
+	if !d.NewValueKnown("group") {
+		if err := d.SetNewComputed("tags_all"); err != nil {
+			return err
+		}
+	} else if err := customizeTagsAllDiff(d, meta, expandInstanceTags(d)); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return fmt.Errorf("Error getting the config for Linode instance %d (%s): %s", instance.ID, instance.Label, err)
+	if d.HasChange("clone_target") {
+		if _, cloneOk := d.GetOk("clone_target.0"); cloneOk {
+			instanceID, _ := strconv.Atoi(d.Id())
+			if err := validateCloneTarget(d.Get("clone_target.0.source_linode_id").(int), instanceID, d.Get("clone_target.0.confirm_overwrite").(bool)); err != nil {
+				return err
+			}
+		}
 	}
-	diskLabelIDMap := make(map[int]string, len(instanceDisks))
-	for _, disk := range instanceDisks {
-		diskLabelIDMap[disk.ID] = disk.Label
 
+	if d.Id() == "" {
+		return nil
 	}
 
-	configs := flattenInstanceConfigs(instanceConfigs, diskLabelIDMap)
+	if forceNewKeys := instanceForceNewKeys(resourceLinodeInstance().Schema, d.GetChangedKeysPrefix("")); len(forceNewKeys) > 0 {
+		log.Printf("[WARN] Linode instance %s will be replaced because %s changed. The replacement will not keep the public IPv4 address %s or the IPv6 address %s; use reserved_ipv4 to keep an address across replacements", d.Id(), strings.Join(forceNewKeys, ", "), d.Get("ip_address"), d.Get("ipv6"))
+	}
+	return nil
+}
 
-	if err := d.Set("config", configs); err != nil {
-		return fmt.Errorf("Erroring setting Linode Instance config: %s", err)
+func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := provisionLinodeInstance(d, meta); err != nil {
+		if d.Id() != "" {
+			return rollbackInstanceCreate(meta.(*ProviderMeta).Client, d, err)
+		}
+		return err
 	}
 
-	if len(instanceConfigs) == 1 {
-		d.Set("boot_config_label", instanceConfigs[0].Label)
+	if err := resourceLinodeInstanceRead(d, meta); err != nil {
+		return err
 	}
 
+	// The instance is built by now, so it is not rolled back when cloud-init fails or does not finish in time. It is
+	// kept in state so that it can be inspected, and cloud_init_status has the next apply replace it.
+	if d.Get("wait_for_cloud_init").(bool) {
+		status, err := waitForCreatedInstanceCloudInit(d)
+		if status == "" {
+			status = "unknown"
+		}
+		d.Set("cloud_init_status", status)
+		return err
+	}
 	return nil
 }
 
-// sliceContains tells whether a contains x.
-func sliceContains(a []string, x string) bool {
-	for _, n := range a {
-		if x == n {
-			return true
-		}
+// waitForCreatedInstanceCloudInit waits for cloud-init on the public IPv4 address of a new instance, returning the
+// last cloud-init status found
+func waitForCreatedInstanceCloudInit(d *schema.ResourceData) (string, error) {
+	address := d.Get("ip_address").(string)
+	if address == "" {
+		return "", fmt.Errorf("Error waiting for cloud-init on Linode instance %s: the instance has no public IPv4 address", d.Id())
 	}
-	return false
-}
 
-func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Instance")
+	config, closeAgent, err := instanceCloudInitSSHConfig(d.Get("wait_for_cloud_init_private_key").(string), d.Get("wait_for_cloud_init_host_key").(string))
+	if err != nil {
+		return "", fmt.Errorf("Error waiting for cloud-init on Linode instance %s: %s", d.Id(), err)
 	}
+	defer closeAgent()
+
+	return waitForInstanceCloudInit(net.JoinHostPort(address, cloudInitSSHPort), config, d.Timeout(schema.TimeoutCreate))
+}
+
+// provisionLinodeInstance creates the instance and its disks and configs, and boots it. The ID is set as soon as the
+// instance is created, so a failure after that leaves a partially configured instance behind.
+func provisionLinodeInstance(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
 	d.Partial(true)
 
 	bootConfig := 0
@@ -882,29 +1623,70 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 		Region:         d.Get("region").(string),
 		Type:           d.Get("type").(string),
 		Label:          d.Get("label").(string),
-		Group:          d.Get("group").(string),
-		BackupsEnabled: d.Get("backups_enabled").(bool),
+		BackupsEnabled: instanceBackupsEnabled(d, meta.(*ProviderMeta).Config),
 		PrivateIP:      d.Get("private_ip").(bool),
 	}
 
-	if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
-		for _, tag := range tagsRaw.(*schema.Set).List() {
-			createOpts.Tags = append(createOpts.Tags, tag.(string))
-		}
+	// A group moved to a tag by group_migration is only applied as a tag
+	if d.Get("migrated_group").(string) == "" || d.Get("group_migration").(string) != "move" {
+		createOpts.Group = normalizeGroup(d.Get("group").(string))
+	}
+
+	if tags := mergeDefaultTags(meta, expandInstanceTags(d)); len(tags) > 0 {
+		createOpts.Tags = tags
 	}
 
 	_, disksOk := d.GetOk("disk")
 	_, configsOk := d.GetOk("config")
+	_, cloneOk := d.GetOk("clone_target.0")
+
+	placementGroupID, placementGroupOk := d.GetOk("placement_group_id")
+	if placementGroupOk {
+		if err := validatePlacementGroupRegion(client, placementGroupID.(int), createOpts.Region); err != nil {
+			return err
+		}
+	}
+
+	reservedIPs := d.Get("reserved_ipv4").(*schema.Set).List()
+	for _, address := range reservedIPs {
+		if err := validateReservedIPRegion(client, address.(string), createOpts.Region); err != nil {
+			return err
+		}
+	}
+
+	// The instance is booted at the end of create unless booted is explicitly false, or there is no config to boot
+	bootedRaw, bootedOk := d.GetOkExists("booted")
+	createConfig := d.Get("create_config").(bool)
+	booted := (!bootedOk || bootedRaw.(bool)) && createConfig
+	_, helpersOk := d.GetOk("helpers")
+
+	if configsOk {
+		if err := validateInstanceConfigMemoryLimits(client, createOpts.Type, d.Get("config").([]interface{})); err != nil {
+			return err
+		}
+		if err := validateInstanceConfigRootDeviceNums(d.Get("config").([]interface{}), d.Get("disk").([]interface{})); err != nil {
+			return err
+		}
+		if err := validateInstanceConfigInitRDs(d.Get("disk").([]interface{}), d.Get("config").([]interface{})); err != nil {
+			return err
+		}
+		if booted {
+			if err := validateInstanceBootDisk(d.Get("disk").([]interface{}), d.Get("config").([]interface{}), d.Get("boot_config_label").(string)); err != nil {
+				return err
+			}
+		}
+	}
 
 	// If we don't have disks and we don't have configs, use the single API call approach
 	if !disksOk && !configsOk {
-		for _, key := range d.Get("authorized_keys").([]interface{}) {
-			createOpts.AuthorizedKeys = append(createOpts.AuthorizedKeys, key.(string))
-		}
+		createOpts.AuthorizedKeys = expandAuthorizedKeys(d.Get("authorized_keys").([]interface{}))
 		for _, key := range d.Get("authorized_users").([]interface{}) {
 			createOpts.AuthorizedUsers = append(createOpts.AuthorizedUsers, key.(string))
 		}
 		createOpts.RootPass = d.Get("root_pass").(string)
+		if diskRootPass := d.Get("disk_root_pass").(string); diskRootPass != "" {
+			createOpts.RootPass = diskRootPass
+		}
 		if createOpts.RootPass == "" {
 			var err error
 			createOpts.RootPass, err = createRandomRootPassword()
@@ -913,13 +1695,32 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 			}
 		}
 		createOpts.Image = d.Get("image").(string)
-		createOpts.Booted = &boolTrue
+		createOpts.Booted = &booted
+		if helpersOk {
+			// The instance is booted once the helpers of its config are applied
+			createOpts.Booted = &boolFalse
+		}
+		if cloneOk {
+			// The instance is booted once the clone has replaced its disks
+			createOpts.Booted = &boolFalse
+		}
 		createOpts.BackupID = d.Get("backup_id").(int)
-		if swapSize := d.Get("swap_size").(int); swapSize > 0 {
+		// swap_size = 0 must be sent explicitly, otherwise the API creates the default swap disk
+		if swapSizeRaw, swapSizeOk := d.GetOkExists("swap_size"); swapSizeOk {
+			swapSize, err := parseSwapSize(swapSizeRaw.(string))
+			if err != nil {
+				return err
+			}
 			createOpts.SwapSize = &swapSize
 		}
 
 		createOpts.StackScriptID = d.Get("stackscript_id").(int)
+		// The revision is checked again, as the StackScript may have been revised since the plan
+		if revision := d.Get("stackscript_revision").(string); revision != "" {
+			if err := validateStackscriptRevision(client, createOpts.StackScriptID, revision); err != nil {
+				return err
+			}
+		}
 
 		if stackscriptDataRaw, ok := d.GetOk("stackscript_data"); ok {
 			stackscriptData, ok := stackscriptDataRaw.(map[string]interface{})
@@ -942,12 +1743,46 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 
 	d.SetId(fmt.Sprintf("%d", instance.ID))
 
-	// d.Set("backups_enabled", instance.BackupsEnabled)
+	if placementGroupOk {
+		if err = assignPlacementGroup(client, placementGroupID.(int), instance.ID); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("placement_group_id")
+
+	for _, address := range reservedIPs {
+		if err = assignReservedIP(client, instance.ID, address.(string)); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("reserved_ipv4")
+
+	if rdns, rdnsOk := d.GetOk("rdns_ipv6"); rdnsOk {
+		if err = updateInstanceIPv6RDNS(client, instance.ID, rdns.(string)); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("rdns_ipv6")
+
+	if _, scheduleOk := d.GetOk("backups.0.schedule"); scheduleOk {
+		if err = updateInstanceBackupSchedule(client, instance.ID, d.Get("backups.0.schedule.0.day").(string), d.Get("backups.0.schedule.0.window").(string)); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("backups")
+
+	if policy, policyOk := d.GetOk("maintenance_policy"); policyOk {
+		if err = updateInstanceMaintenancePolicy(client, instance.ID, policy.(string)); err != nil {
+			return err
+		}
+	}
+	d.SetPartial("maintenance_policy")
 
 	d.SetPartial("private_ip")
 	d.SetPartial("authorized_keys")
 	d.SetPartial("authorized_users")
 	d.SetPartial("root_pass")
+	d.SetPartial("disk_root_pass")
 	d.SetPartial("kernel")
 	d.SetPartial("image")
 	d.SetPartial("backup_id")
@@ -974,22 +1809,17 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 	updateOpts := linodego.InstanceUpdateOptions{}
 	doUpdate := false
 
-	if _, watchdogEnabledOk := d.GetOk("watchdog_enabled"); watchdogEnabledOk {
+	// Unset settings keep the values the API assigned to the new instance, so they are read back without drift
+	if watchdogEnabled, watchdogEnabledOk := d.GetOkExists("watchdog_enabled"); watchdogEnabledOk {
 		doUpdate = true
-		watchdogEnabled := d.Get("watchdog_enabled").(bool)
+		watchdogEnabled := watchdogEnabled.(bool)
 		updateOpts.WatchdogEnabled = &watchdogEnabled
 	}
 
-	if _, alertsOk := d.GetOk("alerts.0"); alertsOk {
+	alertProfile := meta.(*ProviderMeta).Config.AlertProfiles[d.Get("alert_profile").(string)]
+	if _, alertsOk := d.GetOk("alerts.0"); alertsOk || len(alertProfile) > 0 {
 		doUpdate = true
-		updateOpts.Alerts = &linodego.InstanceAlert{}
-
-		// TODO(displague) only set specified alerts
-		updateOpts.Alerts.CPU = d.Get("alerts.0.cpu").(int)
-		updateOpts.Alerts.IO = d.Get("alerts.0.io").(int)
-		updateOpts.Alerts.NetworkIn = d.Get("alerts.0.network_in").(int)
-		updateOpts.Alerts.NetworkOut = d.Get("alerts.0.network_out").(int)
-		updateOpts.Alerts.TransferQuota = d.Get("alerts.0.transfer_quota").(int)
+		updateOpts.Alerts = expandInstanceAlerts(d, instance.Alerts, alertProfile)
 	}
 
 	if doUpdate {
@@ -999,6 +1829,16 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
+	// The API creates a config for Image deployments, which is deleted once the Image is deployed
+	if !createConfig && !disksOk {
+		if _, err = client.WaitForEventFinished(context.Background(), instance.ID, linodego.EntityLinode, linodego.ActionLinodeCreate, *instance.Created, int(d.Timeout(schema.TimeoutCreate).Seconds())); err != nil {
+			return fmt.Errorf("Error waiting for Instance %d to finish creating: %s", instance.ID, err)
+		}
+		if err = deleteAllInstanceConfigs(client, instance.ID); err != nil {
+			return err
+		}
+	}
+
 	// Look up tables for any disks and configs we create
 	// - so configs and initrd can reference disks by label
 	// - so configs can be referenced as a boot_config_label param
@@ -1033,7 +1873,11 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 		cset := d.Get("config").([]interface{})
 		detacher := makeVolumeDetacher(client, d)
 
-		configIDMap, err := createInstanceConfigsFromSet(client, instance.ID, cset, diskIDLabelMap, detacher)
+		if err = applyAccountNetworkHelperDefault(client, d, cset); err != nil {
+			return err
+		}
+
+		configIDMap, err := createInstanceConfigsFromSet(client, instance.ID, cset, diskIDLabelMap, diskIDOrdered, detacher)
 		if err != nil {
 			return err
 		}
@@ -1050,7 +1894,7 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 	d.Partial(false)
 
 	if createOpts.Booted == nil || !*createOpts.Booted {
-		if disksOk && configsOk {
+		if disksOk && configsOk && booted {
 			if err = client.BootInstance(context.Background(), instance.ID, bootConfig); err != nil {
 				return fmt.Errorf("Error booting Linode instance %d: %s", instance.ID, err)
 			}
@@ -1073,11 +1917,28 @@ func resourceLinodeInstanceCreate(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
-	return resourceLinodeInstanceRead(d, meta)
+	if helpersOk && !disksOk && !configsOk {
+		if _, err = updateInstanceDeployedConfigHelpers(client, d, instance.ID); err != nil {
+			return err
+		}
+		if booted && !cloneOk {
+			if err = applyInstanceBootedState(client, instance.ID, true, 0, int(d.Timeout(schema.TimeoutCreate).Seconds())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cloneOk {
+		if err = cloneOntoInstance(client, d.Get("clone_target.0.source_linode_id").(int), instance.ID, booted, int(d.Timeout(schema.TimeoutCreate).Seconds())); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -1089,6 +1950,12 @@ func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("Error fetching data about the current linode: %s", err)
 	}
 
+	// A resize shuts a running instance down, which must be acknowledged with shutdown_before_resize. This is checked
+	// before anything is changed. An instance which is to be powered off (booted = false) is shut down regardless.
+	if d.HasChange("type") && !d.Get("shutdown_before_resize").(bool) && d.Get("booted").(bool) && instance.Status == linodego.InstanceRunning {
+		return fmt.Errorf("Error resizing instance %d: it is running and shutdown_before_resize is false; shut it down first, or set shutdown_before_resize = true to allow the resize to shut it down", instance.ID)
+	}
+
 	// Handle all simple updates that don't require reboots, configs, or disks
 	d.Partial(true)
 
@@ -1102,22 +1969,11 @@ func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	if d.HasChange("group") {
-		updateOpts.Group = d.Get("group").(string)
+		updateOpts.Group = normalizeGroup(d.Get("group").(string))
 		d.SetPartial("group")
 		simpleUpdate = true
 	}
 
-	if d.HasChange("tags") {
-		tags := []string{}
-		for _, tag := range d.Get("tags").(*schema.Set).List() {
-			tags = append(tags, tag.(string))
-		}
-
-		updateOpts.Tags = &tags
-		d.SetPartial("tags")
-		simpleUpdate = true
-	}
-
 	if d.HasChange("watchdog_enabled") {
 		watchdogEnabled := d.Get("watchdog_enabled").(bool)
 		updateOpts.WatchdogEnabled = &watchdogEnabled
@@ -1143,8 +1999,55 @@ func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
+	if d.HasChange("migrated_group") && d.Get("migrated_group").(string) != "" && d.Get("group_migration").(string) == "move" {
+		if err := clearInstanceGroup(client, instance.ID); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") || d.HasChange("tags_all") || d.HasChange("group_tag") || d.HasChange("migrated_group") || (d.Get("group_tag").(bool) && d.HasChange("group")) {
+		if instance.Tags, err = applyTags(client, "linode", instance.ID, instance.Tags, mergeDefaultTags(meta, expandInstanceTags(d))); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+		d.SetPartial("tags_all")
+		d.SetPartial("group_tag")
+		d.SetPartial("migrated_group")
+	}
+
 	d.Partial(false)
 
+	if d.HasChange("placement_group_id") {
+		d.Partial(true)
+		oldGroupID, newGroupID := d.GetChange("placement_group_id")
+		if newGroupID.(int) > 0 {
+			if err = validatePlacementGroupRegion(client, newGroupID.(int), instance.Region); err != nil {
+				return err
+			}
+		}
+		if oldGroupID.(int) > 0 {
+			if err = unassignPlacementGroup(client, oldGroupID.(int), instance.ID); err != nil {
+				return err
+			}
+		}
+		if newGroupID.(int) > 0 {
+			if err = assignPlacementGroup(client, newGroupID.(int), instance.ID); err != nil {
+				return err
+			}
+		}
+		d.SetPartial("placement_group_id")
+		d.Partial(false)
+	}
+
+	if d.HasChange("maintenance_policy") {
+		d.Partial(true)
+		if err = updateInstanceMaintenancePolicy(client, instance.ID, d.Get("maintenance_policy").(string)); err != nil {
+			return err
+		}
+		d.SetPartial("maintenance_policy")
+		d.Partial(false)
+	}
+
 	if d.HasChange("backups_enabled") {
 		d.Partial(true)
 		if d.Get("backups_enabled").(bool) {
@@ -1160,45 +2063,173 @@ func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) erro
 		d.Partial(false)
 	}
 
+	if d.HasChange("backups.0.schedule") {
+		d.Partial(true)
+		if err = updateInstanceBackupSchedule(client, instance.ID, d.Get("backups.0.schedule.0.day").(string), d.Get("backups.0.schedule.0.window").(string)); err != nil {
+			return err
+		}
+		d.SetPartial("backups")
+		d.Partial(false)
+	}
+
+	if d.HasChange("root_pass") {
+		if rootPass := d.Get("root_pass").(string); rootPass != "" {
+			d.Partial(true)
+			if err = rotateInstanceRootPass(client, instance.ID, rootPass, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+				return err
+			}
+			d.SetPartial("root_pass")
+			d.Partial(false)
+		}
+	}
+
+	// A smaller swap disk frees space which a resize to a smaller type may need, and a bigger swap disk may need the
+	// space added by a resize to a bigger type, so swap disks are shrunk before and grown after the type changes
+	var swapShrinks, swapGrows bool
+	var swapSize int
+	if d.HasChange("swap_size") {
+		var oldSwapSize int
+		if oldSwapSize, swapSize, err = instanceSwapSizeChange(d); err != nil {
+			return err
+		}
+		swapShrinks, swapGrows = swapSize < oldSwapSize, swapSize > oldSwapSize
+	}
+	if swapShrinks {
+		// A resize reboots the instance into the change
+		if err = applyInstanceSwapSize(client, d, instance.ID, swapSize, !d.HasChange("type")); err != nil {
+			return err
+		}
+		if instance, err = client.GetInstance(context.Background(), instance.ID); err != nil {
+			return fmt.Errorf("Error fetching data about the current linode: %s", err)
+		}
+	}
+
 	if d.HasChange("type") {
+		// A resize boots a running instance back up, so an instance which should be powered off is shut down first
+		// rather than booted by the resize and shut down again afterwards
+		if !d.Get("booted").(bool) && instance.Status == linodego.InstanceRunning {
+			if err = applyInstanceBootedState(client, instance.ID, false, 0, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+				return err
+			}
+			if instance, err = client.GetInstance(context.Background(), instance.ID); err != nil {
+				return fmt.Errorf("Error fetching data about the current linode: %s", err)
+			}
+		}
+
 		if err = changeInstanceType(&client, instance, d.Get("type").(string), d); err != nil {
 			return err
 		}
 		d.Set("type", d.Get("type").(string))
 	}
 
+	if swapGrows {
+		if err = applyInstanceSwapSize(client, d, instance.ID, swapSize, true); err != nil {
+			return err
+		}
+	}
+
 	tfDisksOld, tfDisksNew := d.GetChange("disk")
+	tfConfigsOld, tfConfigsNew := d.GetChange("config")
+	if swapShrinks || swapGrows {
+		if err = setInstanceDisksAndConfigs(client, d, instance.ID); err != nil {
+			return err
+		}
+		tfDisksOld, tfDisksNew = d.Get("disk"), d.Get("disk")
+		tfConfigsOld, tfConfigsNew = d.Get("config"), d.Get("config")
+	}
 
-	rebootInstance, diskIDLabelMap, err := updateInstanceDisks(client, d, *instance, tfDisksOld, tfDisksNew)
+	diskReboot, diskIDLabelMap, err := updateInstanceDisks(client, d, *instance, tfDisksOld, tfDisksNew)
 	if err != nil {
 		return err
 	}
 
+	if d.HasChange("rdns_ipv6") {
+		d.Partial(true)
+		if err = updateInstanceIPv6RDNS(client, instance.ID, d.Get("rdns_ipv6").(string)); err != nil {
+			return err
+		}
+		d.SetPartial("rdns_ipv6")
+		d.Partial(false)
+	}
+
+	if d.HasChange("reserved_ipv4") {
+		d.Partial(true)
+		oldIPs, newIPs := d.GetChange("reserved_ipv4")
+		removed := oldIPs.(*schema.Set).Difference(newIPs.(*schema.Set)).List()
+		added := newIPs.(*schema.Set).Difference(oldIPs.(*schema.Set)).List()
+
+		for _, address := range added {
+			if err = validateReservedIPRegion(client, address.(string), instance.Region); err != nil {
+				return err
+			}
+		}
+		for _, address := range removed {
+			if err = unassignReservedIP(client, instance.ID, address.(string)); err != nil {
+				return err
+			}
+		}
+		for _, address := range added {
+			if err = assignReservedIP(client, instance.ID, address.(string)); err != nil {
+				return err
+			}
+		}
+		d.SetPartial("reserved_ipv4")
+		d.Partial(false)
+	}
+
+	privateIPReboot := false
 	if d.HasChange("private_ip") {
 		if !d.Get("private_ip").(bool) {
 			return fmt.Errorf("Error removing private IP address for Instance %d: Removing a Private IP address must be handled through a support ticket", instance.ID)
 		}
 
 		d.Partial(true)
-		resp, err := client.AddInstanceIPAddress(context.Background(), instance.ID, false)
-
+		address, err := addInstancePrivateIP(client, instance.ID)
 		if err != nil {
 			return fmt.Errorf("Error activating private networking on Instance %d: %s", instance.ID, err)
 		}
 
 		d.SetPartial("private_ip")
-		d.Set("private_ip_address", resp.Address)
+		d.Set("private_ip_address", address)
 		d.SetPartial("private_ip_address")
 		d.Partial(false)
-		rebootInstance = true
+		// The network helper configures the new address at boot
+		privateIPReboot = true
 	}
 
-	tfConfigsOld, tfConfigsNew := d.GetChange("config")
-	cRebootInstance, updatedConfigMap, updatedConfigs, err := updateInstanceConfigs(client, d, *instance, tfConfigsOld, tfConfigsNew, diskIDLabelMap)
+	if d.HasChange("config") || d.HasChange("type") {
+		if err = validateInstanceConfigMemoryLimits(client, d.Get("type").(string), tfConfigsNew.([]interface{})); err != nil {
+			return err
+		}
+		if err = validateInstanceConfigRootDeviceNums(tfConfigsNew.([]interface{}), d.Get("disk").([]interface{})); err != nil {
+			return err
+		}
+	}
+	configReboot, updatedConfigMap, updatedConfigs, err := updateInstanceConfigs(client, d, *instance, tfConfigsOld, tfConfigsNew, diskIDLabelMap)
 	if err != nil {
 		return err
 	}
-	rebootInstance = rebootInstance || cRebootInstance
+
+	// helpers conflicts with config blocks, so a change is always to the config the API created
+	if d.HasChange("helpers") {
+		d.Partial(true)
+		deployedHelpersChanged, err := updateInstanceDeployedConfigHelpers(client, d, instance.ID)
+		if err != nil {
+			return err
+		}
+		d.SetPartial("helpers")
+		d.Partial(false)
+
+		// Helpers only take effect at boot
+		if instanceRebootNeeded(instance.Status, d.Get("booted").(bool), deployedHelpersChanged) {
+			if err = client.RebootInstance(context.Background(), instance.ID, 0); err != nil {
+				return fmt.Errorf("Error rebooting Instance %d: %s", instance.ID, err)
+			}
+			if _, err = client.WaitForEventFinished(context.Background(), id, linodego.EntityLinode, linodego.ActionLinodeReboot, *instance.Created, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+				return fmt.Errorf("Error waiting for Instance %d to finish rebooting: %s", instance.ID, err)
+			}
+		}
+	}
 
 	bootConfig := 0
 
@@ -1214,6 +2245,7 @@ func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) erro
 		bootConfig = updatedConfigs[0].ID
 	}
 
+	rebootInstance := instanceRebootNeeded(instance.Status, d.Get("booted").(bool), diskReboot || privateIPReboot || configReboot)
 	if rebootInstance && len(diskIDLabelMap) > 0 && len(updatedConfigMap) > 0 && bootConfig > 0 {
 		err = client.RebootInstance(context.Background(), instance.ID, bootConfig)
 
@@ -1232,18 +2264,59 @@ func resourceLinodeInstanceUpdate(d *schema.ResourceData, meta interface{}) erro
 
 	}
 
+	if d.HasChange("clone_target") {
+		if _, cloneOk := d.GetOk("clone_target.0"); cloneOk {
+			d.Partial(true)
+			if err = cloneOntoInstance(client, d.Get("clone_target.0.source_linode_id").(int), instance.ID, d.Get("booted").(bool), int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+				return err
+			}
+			d.SetPartial("clone_target")
+			d.Partial(false)
+		}
+	}
+
+	// A resize returns the instance to its previous power state, honor the desired state instead
+	if d.HasChange("type") || d.HasChange("booted") {
+		if err = applyInstanceBootedState(client, instance.ID, d.Get("booted").(bool), bootConfig, int(d.Timeout(schema.TimeoutUpdate).Seconds())); err != nil {
+			return err
+		}
+	}
+
 	return resourceLinodeInstanceRead(d, meta)
 }
 
 func resourceLinodeInstanceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
-		return fmt.Errorf("Error parsing Linode Instance ID %s as int", d.Id())
+		return fmt.Errorf("Error parsing Linode instance ID %s as int: %s", d.Id(), err)
+	}
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("Error deleting Linode instance %d: deletion_protection is enabled; set deletion_protection = false and apply it before deleting or replacing the instance", id)
+	}
+	if shutdownTimeout := d.Get("shutdown_timeout").(int); shutdownTimeout > 0 {
+		shutdownInstanceBeforeDelete(client, int(id), shutdownTimeout)
+	}
+	if d.Get("create_image_on_destroy").(bool) {
+		image, err := imageInstanceBeforeDelete(client, int(id), d.Get("label").(string), d.Get("boot_config_label").(string), int(d.Timeout(schema.TimeoutDelete).Seconds()))
+		if err != nil {
+			return fmt.Errorf("Error creating an Image of Linode instance %d before deleting it: %s", id, err)
+		}
+		if image != nil {
+			log.Printf("[INFO] Created Image %s (%s) of Linode instance %d before deleting it", image.ID, image.Label, id)
+		} else {
+			log.Printf("[WARN] Linode instance %d no longer exists, there is nothing to image before deleting it", id)
+		}
 	}
+
 	minDelete := time.Now().AddDate(0, 0, -1)
 	err = client.DeleteInstance(context.Background(), int(id))
 	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			log.Printf("[WARN] Linode Instance ID %q was already deleted", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return fmt.Errorf("Error deleting Linode instance %d: %s", id, err)
 	}
 	// Wait for full deletion to assure volumes are detached
@@ -1,16 +1,30 @@
 package linode
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/linode/linodego"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 )
 
 func init() {
@@ -205,6 +219,42 @@ func TestAccLinodeInstance_configPair(t *testing.T) {
 	})
 }
 
+func TestAccLinodeInstance_configPairBootLabelDefault(t *testing.T) {
+	t.Parallel()
+
+	resName := "linode_instance.foobar"
+	var instance linodego.Instance
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithMultipleConfigsNoBootLabel(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeInstanceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "label", instanceName),
+					resource.TestCheckResourceAttr(resName, "config.#", "2"),
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", "linode/latest-64bit"),
+					resource.TestCheckResourceAttr(resName, "config.1.kernel", "linode/latest-32bit"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "configa"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccLinodeInstance_disk(t *testing.T) {
 	t.Parallel()
 
@@ -507,6 +557,58 @@ func TestAccLinodeInstance_updateSimple(t *testing.T) {
 	})
 }
 
+func TestAccLinodeInstance_configMemoryLimit(t *testing.T) {
+	t.Parallel()
+	var instance linodego.Instance
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	resName := "linode_instance.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithConfigMemoryLimit(instanceName, 512),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeInstanceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+					resource.TestCheckResourceAttr(resName, "config.0.memory_limit", "512"),
+					resource.TestCheckResourceAttr(resName, "config.0.comments", "memory limited"),
+					testAccCheckComputeInstanceConfigs(&instance, testConfig("config", testConfigComments("memory limited"))),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithConfigMemoryLimit(instanceName, 4096),
+				ExpectError: regexp.MustCompile("memory_limit 4096 exceeds"),
+			},
+		},
+	})
+}
+
+func TestAccLinodeInstance_configNetworkHelperDefault(t *testing.T) {
+	t.Parallel()
+	var instance linodego.Instance
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	resName := "linode_instance.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithConfigHelpersNoNetwork(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeInstanceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "config.0.helpers.0.distro", "false"),
+					testAccCheckLinodeInstanceNetworkHelperDefault(resName, "config.0.helpers.0.network"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccLinodeInstance_configUpdate(t *testing.T) {
 	t.Parallel()
 	var instance linodego.Instance
@@ -719,6 +821,44 @@ func TestAccLinodeInstance_resize(t *testing.T) {
 	})
 }
 
+func TestAccLinodeInstance_resizeBootedFalse(t *testing.T) {
+	t.Parallel()
+	var instance linodego.Instance
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	resName := "linode_instance.foobar"
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Error generating test SSH key pair: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceConfigUpsizeSmall(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeInstanceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "type", "g6-nanode-1"),
+					resource.TestCheckResourceAttr(resName, "booted", "true"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+				),
+			},
+			// Resize and stay powered off afterwards
+			{
+				Config: testAccCheckLinodeInstanceConfigUpsizeBiggerBootedFalse(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeInstanceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "type", "g6-standard-1"),
+					resource.TestCheckResourceAttr(resName, "booted", "false"),
+					resource.TestCheckResourceAttr(resName, "status", "offline"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccLinodeInstance_diskRawResize(t *testing.T) {
 	t.Parallel()
 	var instance linodego.Instance
@@ -976,607 +1116,5600 @@ func TestAccLinodeInstance_privateNetworking(t *testing.T) {
 	})
 }
 
-func testAccCheckLinodeInstanceExists(name string, instance *linodego.Instance) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(linodego.Client)
+func TestLinodeInstance_mockBasic(t *testing.T) {
+	api := newMockLinodeAPI(t)
 
-		rs, ok := s.RootModule().Resources[name]
-		if !ok {
-			return fmt.Errorf("Not found: %s", name)
-		}
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
 
-		if rs.Primary.ID == "" {
-			return fmt.Errorf("No ID is set")
-		}
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceBasic(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", instanceName),
+					resource.TestCheckResourceAttr(resName, "hostname", strings.Replace(instanceName, "_", "-", -1)),
+					resource.TestCheckResourceAttr(resName, "type", "g6-nanode-1"),
+					resource.TestCheckResourceAttr(resName, "region", "us-east"),
+					resource.TestCheckResourceAttr(resName, "group", "tf_test"),
+					resource.TestCheckResourceAttr(resName, "swap_size", "256"),
+					resource.TestCheckResourceAttrPair(resName, "linode_id", resName, "id"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "booted", "true"),
+					resource.TestCheckResourceAttr(resName, "specs.0.disk", "25600"),
+					resource.TestCheckResourceAttrSet(resName, "ip_address"),
+					resource.TestMatchResourceAttr(resName, "ip_gateway", regexp.MustCompile(`^198\.51\.\d+\.1$`)),
+					resource.TestCheckResourceAttr(resName, "ip_subnet_mask", "255.255.255.0"),
+					resource.TestCheckResourceAttrPair(resName, "ssh_connection.0.host", resName, "ip_address"),
+					resource.TestCheckResourceAttr(resName, "ssh_connection.0.user", "root"),
+					resource.TestCheckResourceAttr(resName, "ssh_connection.0.port", "22"),
+					resource.TestCheckResourceAttr(resName, "transfer_reset_date", instanceTransferResetDate(time.Now()).Format(time.RFC3339)),
+				),
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"root_pass", "authorized_keys", "image"},
+			},
+		},
+	})
+}
 
-		id, err := strconv.Atoi(rs.Primary.ID)
-		if err != nil {
-			return fmt.Errorf("Error parsing %v to int", rs.Primary.ID)
-		}
+func TestLinodeInstance_mockDiskAndConfig(t *testing.T) {
+	api := newMockLinodeAPI(t)
 
-		found, err := client.GetInstance(context.Background(), id)
-		if err != nil {
-			return fmt.Errorf("Error retrieving state of Instance %s: %s", rs.Primary.Attributes["label"], err)
-		}
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
 
-		*instance = *found
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDiskAndConfig(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", instanceName),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "1"),
+					resource.TestCheckResourceAttr(resName, "disk.0.size", "3000"),
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", "linode/latest-64bit"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "config"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithDiskAndConfigResizedAndExpanded(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "type", "g6-standard-1"),
+					resource.TestCheckResourceAttr(resName, "disk.0.size", "6000"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+				),
+			},
+		},
+	})
+}
 
-		return nil
+func TestLinodeInstance_mockDiskBusy(t *testing.T) {
+	defer func(interval time.Duration) { instanceBusyPollInterval = interval }(instanceBusyPollInterval)
+	instanceBusyPollInterval = 10 * time.Millisecond
+
+	api := newMockLinodeAPI(t)
+	api.diskBusy = 2
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
 	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithMultipleDiskAndConfig(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+					func(*terraform.State) error {
+						// Both disks are created once the jobs keeping the instance busy finish, which are found among
+						// the jobs of another instance
+						if calls := api.callCount(fmt.Sprintf("POST linode/instances/%d/disks", api.instanceID())); calls != 4 {
+							return fmt.Errorf("Expected 2 busy disk creations to be retried, got %d disk creations", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
 }
 
-func testAccCheckLinodeInstanceDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "linode_instance" {
-			continue
-		}
+func TestLinodeInstance_mockDiskBusyRetriesExhausted(t *testing.T) {
+	defer func(interval time.Duration) { instanceBusyPollInterval = interval }(instanceBusyPollInterval)
+	instanceBusyPollInterval = 10 * time.Millisecond
 
-		id, err := strconv.Atoi(rs.Primary.ID)
-		if err != nil {
-			return fmt.Errorf("Error parsing %v as int", rs.Primary.ID)
-		}
+	api := newMockLinodeAPI(t)
+	api.diskBusy = instanceDiskBusyRetries + 1
 
-		if id == 0 {
-			return fmt.Errorf("should not have Linode ID 0")
-		}
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
 
-		_, err = client.GetInstance(context.Background(), id)
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithMultipleDiskAndConfig(instanceName, publicKeyMaterial),
+				ExpectError: regexp.MustCompile("Linode busy"),
+			},
+		},
+	})
+}
 
-		if err == nil {
-			return fmt.Errorf("should not find Linode ID %d existing after delete", id)
-		}
+func TestLinodeInstance_mockExternalPrivateIP(t *testing.T) {
+	api := newMockLinodeAPI(t)
 
-		if apiErr, ok := err.(*linodego.Error); ok && apiErr.Code != 404 {
-			return fmt.Errorf("Error getting Linode ID %d: %s", id, err)
-		}
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	privateIPCalls := func() int {
+		return api.callCount(fmt.Sprintf("POST linode/instances/%d/ips", api.instanceID()))
 	}
 
-	return nil
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check:  resource.TestCheckResourceAttr(resName, "private_ip", "false"),
+			},
+			{
+				PreConfig: func() {
+					// A private IP is added outside of Terraform
+					client := api.client()
+					if _, err := client.AddInstanceIPAddress(context.Background(), api.instanceID(), false); err != nil {
+						t.Fatalf("Error adding the private IP: %s", err)
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "private_ip", "false"),
+					resource.TestMatchResourceAttr(resName, "private_ip_address", regexp.MustCompile(`^192\.168\.`)),
+					func(*terraform.State) error {
+						if calls := privateIPCalls(); calls != 1 {
+							return fmt.Errorf("Expected only the external private IP to be added, got %d IP additions", calls)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// An import takes over the private IP the instance has
+				ResourceName: resName,
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if privateIP := states[0].Attributes["private_ip"]; privateIP != "true" {
+						return fmt.Errorf("Expected the imported private_ip to be true, got %q", privateIP)
+					}
+					return nil
+				},
+			},
+		},
+	})
 }
 
-func testAccCheckLinodeInstanceAttributesPrivateNetworking(n string) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		rs, ok := s.RootModule().Resources[n]
-		if !ok {
-			return fmt.Errorf("should have found linode_instance resource %s", n)
-		}
+func TestLinodeInstance_mockPrivateIPExists(t *testing.T) {
+	api := newMockLinodeAPI(t)
 
-		if rs.Primary.ID == "" {
-			return fmt.Errorf("should have a Linode ID")
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	var privateAddress string
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check:  resource.TestCheckResourceAttr(resName, "private_ip", "false"),
+			},
+			{
+				PreConfig: func() {
+					// The private IP was added by an earlier apply which failed before it was saved
+					client := api.client()
+					ip, err := client.AddInstanceIPAddress(context.Background(), api.instanceID(), false)
+					if err != nil {
+						t.Fatalf("Error adding the private IP: %s", err)
+					}
+					privateAddress = ip.Address
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "private_ip = true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "private_ip", "true"),
+					func(s *terraform.State) error {
+						if address := s.RootModule().Resources[resName].Primary.Attributes["private_ip_address"]; address != privateAddress {
+							return fmt.Errorf("Expected the existing private IP %s to be used, got %q", privateAddress, address)
+						}
+						instanceID := api.instanceID()
+						if calls := api.callCount(fmt.Sprintf("POST linode/instances/%d/ips", instanceID)); calls != 2 {
+							return fmt.Errorf("Expected the external and the rejected IP additions, got %d IP additions", calls)
+						}
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						private := 0
+						for _, ip := range api.ips[instanceID] {
+							if !ip.Public {
+								private++
+							}
+						}
+						if private != 1 {
+							return fmt.Errorf("Expected the instance to have 1 private IP, got %d", private)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockSwapSizeUpdate(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	var instanceID string
+
+	// checkSwap verifies the swap disk of the instance and that it is attached to its config
+	checkSwap := func(size int) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			if id := s.RootModule().Resources[resName].Primary.ID; id != instanceID {
+				return fmt.Errorf("Expected Instance %s to be updated in place, got Instance %s", instanceID, id)
+			}
+			id := api.instanceID()
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			var swapID int
+			for _, disk := range api.disks[id] {
+				if disk.Filesystem != "swap" {
+					continue
+				}
+				if disk.Size != size {
+					return fmt.Errorf("Expected a swap disk of %d MB, got %d MB", size, disk.Size)
+				}
+				swapID = disk.ID
+			}
+			if size > 0 && swapID == 0 {
+				return fmt.Errorf("Expected a swap disk of %d MB, got none", size)
+			}
+			if size == 0 && swapID != 0 {
+				return fmt.Errorf("Expected the swap disk %d to be deleted", swapID)
+			}
+
+			attached := false
+			for _, config := range api.configs[id] {
+				for _, device := range []*linodego.InstanceConfigDevice{config.Devices.SDA, config.Devices.SDB, config.Devices.SDC} {
+					if device != nil && device.DiskID != 0 && device.DiskID == swapID {
+						attached = true
+					}
+				}
+			}
+			if size > 0 && !attached {
+				return fmt.Errorf("Expected the swap disk %d to be attached to the config", swapID)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "swap_size = 256"),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						instanceID = s.RootModule().Resources[resName].Primary.ID
+						return nil
+					},
+					checkSwap(256),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "swap_size = 128"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "128"),
+					checkSwap(128),
+				),
+			},
+			{
+				// The space freed by the smaller swap disk is available to grow it again
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "swap_size = 256"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "256"),
+					checkSwap(256),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "swap_size = 0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "0"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "1"),
+					checkSwap(0),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "swap_size = 256"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "256"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+					checkSwap(256),
+				),
+			},
+			{
+				// The image disk fills the rest of the instance, so there is no space for a bigger swap disk
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, "swap_size = 512"),
+				ExpectError: regexp.MustCompile("Insufficient space"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockResizeBooted(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceConfigUpsizeSmall(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "type", "g6-nanode-1"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceConfigUpsizeBiggerBootedFalse(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "type", "g6-standard-1"),
+					resource.TestCheckResourceAttr(resName, "specs.0.disk", "51200"),
+					resource.TestCheckResourceAttr(resName, "booted", "false"),
+					resource.TestCheckResourceAttr(resName, "status", "offline"),
+					func(*terraform.State) error {
+						instancePath := fmt.Sprintf("linode/instances/%d", api.instanceID())
+						shutdown := api.callIndex("POST "+instancePath+"/shutdown", -1)
+						resize := api.callIndex("POST "+instancePath+"/resize", -1)
+						if shutdown < 0 || resize < shutdown {
+							return fmt.Errorf("Expected the instance to be shut down before it is resized")
+						}
+						if boot := api.callIndex("POST "+instancePath+"/boot", resize); boot >= 0 {
+							return fmt.Errorf("Expected the instance not to be booted after it is resized")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceConfigUpsizeBiggestBootedTrue(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "type", "g6-standard-2"),
+					resource.TestCheckResourceAttr(resName, "booted", "true"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					func(*terraform.State) error {
+						instancePath := fmt.Sprintf("linode/instances/%d", api.instanceID())
+						resize := api.callIndex("POST "+instancePath+"/resize", api.callIndex("POST "+instancePath+"/resize", -1))
+						if resize < 0 || api.callIndex("POST "+instancePath+"/boot", resize) < 0 {
+							return fmt.Errorf("Expected the instance to be booted after it is resized")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockShutdownBeforeResize(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	resizeCalls := func() int {
+		return api.callCount(fmt.Sprintf("POST linode/instances/%d/resize", api.instanceID()))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithShutdownBeforeResize(instanceName, "g6-nanode-1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "shutdown_before_resize", "false"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithShutdownBeforeResize(instanceName, "g6-standard-1", false),
+				ExpectError: regexp.MustCompile("it is running and shutdown_before_resize is false"),
+			},
+			{
+				PreConfig: func() {
+					if calls := resizeCalls(); calls != 0 {
+						t.Errorf("Expected the running instance not to be resized, got %d resize calls", calls)
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithShutdownBeforeResize(instanceName, "g6-standard-1", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "shutdown_before_resize", "true"),
+					resource.TestCheckResourceAttr(resName, "type", "g6-standard-1"),
+					func(*terraform.State) error {
+						if calls := resizeCalls(); calls != 1 {
+							return fmt.Errorf("Expected the instance to be resized once, got %d resize calls", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockRootPassRotation(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	var instanceID string
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithRootPass(instanceName, "terraform-test"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "root_pass", rootPasswordState("terraform-test")),
+					func(s *terraform.State) error {
+						instanceID = s.RootModule().Resources[resName].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithRootPass(instanceName, "terraform-rotated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "root_pass", rootPasswordState("terraform-rotated")),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources[resName].Primary.ID; id != instanceID {
+							return fmt.Errorf("Expected the root_pass change to keep instance %s, got %s", instanceID, id)
+						}
+
+						id := api.instanceID()
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						for _, disk := range api.disks[id] {
+							if disk.Filesystem != linodego.FilesystemSwap {
+								if pass := api.diskPasswords[disk.ID]; pass != "terraform-rotated" {
+									return fmt.Errorf("Expected the root disk password to be reset, got %q", pass)
+								}
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDiskRootPass(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDiskRootPass(instanceName, "terraform-test", "appliance-setup"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "root_pass", rootPasswordState("terraform-test")),
+					resource.TestCheckResourceAttr(resName, "disk_root_pass", rootPasswordState("appliance-setup")),
+					func(*terraform.State) error {
+						var createOpts linodego.InstanceCreateOptions
+						if err := api.lastBody("POST linode/instances", &createOpts); err != nil {
+							return err
+						}
+						if createOpts.RootPass != "appliance-setup" {
+							return fmt.Errorf("Expected the Image to be deployed with disk_root_pass, got %q", createOpts.RootPass)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Changing root_pass still resets the password in place
+				Config: testAccCheckLinodeInstanceWithDiskRootPass(instanceName, "terraform-rotated", "appliance-setup"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "root_pass", rootPasswordState("terraform-rotated")),
+					func(*terraform.State) error {
+						if calls := api.callCount("POST linode/instances"); calls != 1 {
+							return fmt.Errorf("Expected the instance to be kept, got %d instance creations", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockGroupNormalization(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	updateCalls := func() int {
+		return api.callCount(fmt.Sprintf("PUT linode/instances/%d", api.instanceID()))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithGroupTag(instanceName, " Cafe\u0301 ", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "group", "Caf\u00e9"),
+				),
+			},
+			{
+				Config:   testAccCheckLinodeInstanceWithGroupTag(instanceName, "Caf\u00e9  ", false),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithGroupTag(instanceName, "Cafe\u0301", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "group", "Caf\u00e9"),
+					func(*terraform.State) error {
+						if calls := updateCalls(); calls != 0 {
+							return fmt.Errorf("Expected no instance updates for a cosmetically identical group, got %d", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockAuthorizedKeysFile(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	keysFile := `authorized_keys = ["# laptop\nssh-rsa AAAAB3Nza one@laptop\n\nssh-ed25519 AAAAC3Nza two@laptop\n"]`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, keysFile),
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						var createOpts linodego.InstanceCreateOptions
+						if err := api.lastBody("POST linode/instances", &createOpts); err != nil {
+							return err
+						}
+						expected := "ssh-rsa AAAAB3Nza one@laptop,ssh-ed25519 AAAAC3Nza two@laptop"
+						if keys := strings.Join(createOpts.AuthorizedKeys, ","); keys != expected {
+							return fmt.Errorf("Expected the authorized keys file to be deployed as %q, got %q", expected, keys)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   testAccCheckLinodeInstanceWithSettings(instanceName, keysFile),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockImageTooLarge(t *testing.T) {
+	api := newMockLinodeAPI(t)
+	api.images = append(api.images, linodego.Image{ID: "private/1234", Label: "golden", Type: "manual", Size: 30000})
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithImage(instanceName, "g6-nanode-1", "private/1234"),
+				ExpectError: regexp.MustCompile("requires a disk of at least 30000 MB and swap_size is 512 MB, 30512 MB in total, but type g6-nanode-1 only provides 25600 MB"),
+			},
+			{
+				PreConfig: func() {
+					if calls := api.callCount("POST linode/instances"); calls != 0 {
+						t.Errorf("Expected no instance to be created for an image larger than the type, got %d create calls", calls)
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithImage(instanceName, "g6-standard-1", "private/1234"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "image", "private/1234"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockImageAndSwapTooLarge(t *testing.T) {
+	api := newMockLinodeAPI(t)
+	api.images = append(api.images, linodego.Image{ID: "private/1234", Label: "golden", Type: "manual", Size: 24576})
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithImageAndSwapSize(instanceName, "private/1234", "2GB"),
+				ExpectError: regexp.MustCompile("requires a disk of at least 24576 MB and swap_size is 2048 MB, 26624 MB in total, but type g6-nanode-1 only provides 25600 MB"),
+			},
+			{
+				// The image and swap fill the disk exactly
+				Config: testAccCheckLinodeInstanceWithImageAndSwapSize(instanceName, "private/1234", "1GB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "swap_size", "1024"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockCloneTarget(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	targetDisks := func(s *terraform.State) ([]*linodego.InstanceDisk, error) {
+		id, err := strconv.Atoi(s.RootModule().Resources[resName].Primary.ID)
+		if err != nil {
+			return nil, err
+		}
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		return api.disks[id], nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithCloneTarget(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "My linode/ubuntu18.04 Disk Profile"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithCloneTarget(instanceName, "confirm_overwrite = false"),
+				ExpectError: regexp.MustCompile("set confirm_overwrite = true"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithCloneTarget(instanceName, "confirm_overwrite = true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "golden"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "clone_target.0.confirm_overwrite", "true"),
+					func(s *terraform.State) error {
+						disks, err := targetDisks(s)
+						if err != nil {
+							return err
+						}
+						if len(disks) != 1 || disks[0].Label != "golden" || disks[0].Size != 3000 {
+							return fmt.Errorf("Expected the instance disks to be replaced by the cloned golden disk, got %d disks", len(disks))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   testAccCheckLinodeInstanceWithCloneTarget(instanceName, "confirm_overwrite = true"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockCloneTargetCreate(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithCloneTarget(instanceName, "confirm_overwrite = true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "golden"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDiskLayout(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+					resource.TestCheckResourceAttr(resName, "disk.0.filesystem", "ext4"),
+					resource.TestCheckResourceAttr(resName, "disk.0.read_only", "false"),
+					resource.TestCheckResourceAttrSet(resName, "disk.0.id"),
+					resource.TestCheckResourceAttr(resName, "disk.1.filesystem", "swap"),
+					resource.TestCheckResourceAttr(resName, "disk.1.size", "512"),
+					resource.TestCheckResourceAttr(resName, "disk.1.read_only", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockShutdownTimeout(t *testing.T) {
+	for _, hung := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hung=%t", hung), func(t *testing.T) {
+			api := newMockLinodeAPI(t)
+
+			var instanceName = acctest.RandomWithPrefix("tf_test")
+			var instanceID int
+
+			resource.UnitTest(t, resource.TestCase{
+				Providers: api.providers(),
+				CheckDestroy: func(s *terraform.State) error {
+					if err := api.checkInstanceDestroy(s); err != nil {
+						return err
+					}
+					shutdown := api.callIndex(fmt.Sprintf("POST linode/instances/%d/shutdown", instanceID), -1)
+					if shutdown < 0 || shutdown > api.callIndex(fmt.Sprintf("DELETE linode/instances/%d", instanceID), -1) {
+						return fmt.Errorf("Expected the instance to be shut down before it was deleted")
+					}
+					return nil
+				},
+				Steps: []resource.TestStep{
+					{
+						Config: testAccCheckLinodeInstanceWithSettings(instanceName, "shutdown_timeout = 1"),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("linode_instance.foobar", "shutdown_timeout", "1"),
+							func(*terraform.State) error {
+								instanceID = api.instanceID()
+								if hung {
+									// The shutdown is accepted but the instance never powers off
+									api.mu.Lock()
+									api.overrides[fmt.Sprintf("POST linode/instances/%d/shutdown", instanceID)] = func(w http.ResponseWriter, r *http.Request) {
+										writeMockResponse(w, http.StatusOK, nil)
+									}
+									api.mu.Unlock()
+								}
+								return nil
+							},
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestLinodeInstance_mockCreateImageOnDestroy(t *testing.T) {
+	defer func(interval time.Duration) { imageAvailablePollInterval = interval }(imageAvailablePollInterval)
+	imageAvailablePollInterval = 10 * time.Millisecond
+
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	var instanceID int
+	var imageLabel string
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		CheckDestroy: func(s *terraform.State) error {
+			if err := api.checkInstanceDestroy(s); err != nil {
+				return err
+			}
+			api.mu.Lock()
+			var image *linodego.Image
+			for i := range api.images {
+				if api.images[i].Label == imageLabel {
+					image = &api.images[i]
+				}
+			}
+			api.mu.Unlock()
+			if image == nil {
+				return fmt.Errorf("Expected an Image labeled %s to be created", imageLabel)
+			}
+
+			// The root disk is imaged rather than the larger data disk, and deleting waits for the Image
+			if image.Size != 2000 {
+				return fmt.Errorf("Expected the 2000MB root disk to be imaged, got a %dMB disk", image.Size)
+			}
+			deleted := api.callIndex(fmt.Sprintf("DELETE linode/instances/%d", instanceID), -1)
+			if polled := api.callIndex("GET images/"+image.ID, -1); polled < 0 || polled > deleted || api.callCount("GET images/"+image.ID) < 2 {
+				return fmt.Errorf("Expected the instance to be deleted once Image %s was available", image.ID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithImageOnDestroy(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "create_image_on_destroy", "true"),
+					func(s *terraform.State) error {
+						instanceID = api.instanceID()
+						imageLabel = fmt.Sprintf("%s-%d", instanceName, instanceID)
+						return resource.TestCheckResourceAttr(resName, "destroy_image_label", imageLabel)(s)
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockPendingJobs(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	config := testAccCheckLinodeInstanceWithSettings(instanceName, "")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "pending_jobs", "0"),
+					func(*terraform.State) error {
+						// A backup of this instance and a job of another instance are still running, and the account
+						// is busy enough that the backup is no longer on the first page of account events
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						for _, instance := range api.instances {
+							api.addEvent(instance, linodego.ActionBackupsRestore)
+							api.events[len(api.events)-1].Status = linodego.EventStarted
+							for i := 0; i < mockEventsPageSize+50; i++ {
+								api.addEvent(&linodego.Instance{ID: instance.ID + 1}, linodego.ActionLinodeBoot)
+								api.events[len(api.events)-1].Status = linodego.EventStarted
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "pending_jobs", "1"),
+					func(*terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						api.overrides["GET account/events"] = func(w http.ResponseWriter, r *http.Request) {
+							status, body := mockAPIError(http.StatusInternalServerError, "events are unavailable")
+							writeMockResponse(w, status, body)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The refresh does not fail when the events can't be listed, and keeps the jobs from state
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "pending_jobs", "1"),
+					func(*terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						delete(api.overrides, "GET account/events")
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockPowerEvents(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	config := testAccCheckLinodeInstanceWithSettings(instanceName, "")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "power_events.#", "1"),
+					resource.TestCheckResourceAttr(resName, "power_events.0.action", "linode_boot"),
+					resource.TestCheckResourceAttr(resName, "power_events.0.status", "finished"),
+					resource.TestCheckResourceAttrSet(resName, "power_events.0.created"),
+					func(*terraform.State) error {
+						// Lassie reboots the instance outside of Terraform
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						for _, instance := range api.instances {
+							api.addEvent(instance, linodego.EventAction("lassie_reboot"))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "power_events.#", "2"),
+					resource.TestCheckResourceAttr(resName, "power_events.0.action", "lassie_reboot"),
+					resource.TestCheckResourceAttr(resName, "power_events.1.action", "linode_boot"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_powerEventsLimit(t *testing.T) {
+	var events []linodego.Event
+	for i := 0; i < instancePowerEventsLimit+5; i++ {
+		events = append(events, linodego.Event{Action: linodego.ActionLinodeReboot}, linodego.Event{Action: linodego.ActionDiskCreate})
+	}
+
+	powerEvents := flattenInstancePowerEvents(events)
+	if len(powerEvents) != instancePowerEventsLimit {
+		t.Fatalf("Expected %d power events, got %d", instancePowerEventsLimit, len(powerEvents))
+	}
+	for _, event := range powerEvents {
+		if event["action"] != string(linodego.ActionLinodeReboot) {
+			t.Errorf("Expected only power events, got %v", event["action"])
+		}
+	}
+}
+
+func TestLinodeInstance_transferResetDate(t *testing.T) {
+	for _, tc := range []struct {
+		now, expected string
+	}{
+		{"2019-03-14T10:00:00Z", "2019-04-01T00:00:00Z"},
+		{"2019-03-01T00:00:00Z", "2019-04-01T00:00:00Z"},
+		{"2019-12-31T23:59:59Z", "2020-01-01T00:00:00Z"},
+		{"2019-04-30T22:00:00-05:00", "2019-06-01T00:00:00Z"},
+	} {
+		now, err := time.Parse(time.RFC3339, tc.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reset := instanceTransferResetDate(now).Format(time.RFC3339); reset != tc.expected {
+			t.Errorf("Expected the transfer used at %s to be reset at %s, got %s", tc.now, tc.expected, reset)
+		}
+	}
+}
+
+func TestLinodeInstance_mockRootDeviceNum(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	apiRootDevice := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			instanceID := api.instanceID()
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			for _, config := range api.configs[instanceID] {
+				if config.RootDevice != expected {
+					return fmt.Errorf("Expected the config root device to be %s, got %s", expected, config.RootDevice)
+				}
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithRootDeviceNum(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.root_device_num", "1"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithRootDeviceNum(instanceName, "root_device_num = 2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.root_device_num", "2"),
+					resource.TestCheckResourceAttr(resName, "config.0.root_device", "/dev/sdb"),
+					apiRootDevice("/dev/sdb"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithRootDeviceNum(instanceName, "root_device_num = 3"),
+				ExpectError: regexp.MustCompile(`root_device_num 3 is device sdc, but no disk or volume is attached to it`),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDeployedConfigHelpers(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	apiHelpers := func(distro, network bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			instanceID := api.instanceID()
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if api.instances[instanceID].Status != linodego.InstanceRunning {
+				return fmt.Errorf("Expected the instance to be running, got %s", api.instances[instanceID].Status)
+			}
+			for _, config := range api.configs[instanceID] {
+				if config.Helpers.Distro != distro || config.Helpers.Network != network {
+					return fmt.Errorf("Expected the distro and network helpers to be %t and %t, got %#v", distro, network, config.Helpers)
+				}
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "helpers {\n\t\tdistro = false\n\t\tnetwork = false\n\t}"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "helpers.0.distro", "false"),
+					resource.TestCheckResourceAttr(resName, "helpers.0.network", "false"),
+					resource.TestCheckResourceAttr(resName, "helpers.0.modules_dep", "true"),
+					apiHelpers(false, false),
+					func(*terraform.State) error {
+						// The helpers are applied before the first boot
+						instanceID := api.instanceID()
+						api.mu.Lock()
+						configID := api.configs[instanceID][0].ID
+						api.mu.Unlock()
+						configUpdate := api.callIndex(fmt.Sprintf("PUT linode/instances/%d/configs/%d", instanceID, configID), -1)
+						boot := api.callIndex(fmt.Sprintf("POST linode/instances/%d/boot", instanceID), -1)
+						if configUpdate < 0 || boot < configUpdate {
+							return fmt.Errorf("Expected the config helpers to be updated before the instance was booted")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "helpers {\n\t\tdistro = true\n\t\tnetwork = false\n\t}"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "helpers.0.distro", "true"),
+					apiHelpers(true, false),
+					func(*terraform.State) error {
+						if calls := api.callCount(fmt.Sprintf("POST linode/instances/%d/reboot", api.instanceID())); calls != 1 {
+							return fmt.Errorf("Expected the instance to be rebooted for the helpers to take effect, got %d reboots", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockConfigInterfaces(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDiskAndConfig(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.#", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					id := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.configInterfaces[api.configs[id][0].ID] = []instanceConfigInterface{
+						{Purpose: "public"},
+						{Purpose: "vlan", Label: "tf-test-vlan", IPAMAddress: "10.0.0.1/24"},
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithDiskAndConfig(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.#", "2"),
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.0.purpose", "public"),
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.0.label", ""),
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.1.purpose", "vlan"),
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.1.label", "tf-test-vlan"),
+					resource.TestCheckResourceAttr(resName, "config.0.interfaces.1.ipam_address", "10.0.0.1/24"),
+				),
+			},
+			{
+				Config:   testAccCheckLinodeInstanceWithDiskAndConfig(instanceName, publicKeyMaterial),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockIPv6Ranges(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resName, "ipv6_link_local", regexp.MustCompile(`^fe80::`)),
+					resource.TestCheckResourceAttr(resName, "ipv6_ranges.#", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					id := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.ipv6Ranges[id] = []*linodego.IPv6Range{
+						{Range: "2600:3c03:e000:123::", Region: "us-east"},
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resName, "ipv6_link_local", regexp.MustCompile(`^fe80::`)),
+					resource.TestCheckResourceAttr(resName, "ipv6_ranges.#", "1"),
+					resource.TestCheckResourceAttr(resName, "ipv6_ranges.0", "2600:3c03:e000:123::"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockHost(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "hypervisor", "kvm"),
+					resource.TestCheckResourceAttr(resName, "host_uuid", ""),
+				),
+			},
+			{
+				PreConfig: func() {
+					id := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.hostUUIDs[id] = "3a3fd025-5fae-4bae-a4c5-0a2d9e4b2d2a"
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "hypervisor", "kvm"),
+					resource.TestCheckResourceAttr(resName, "host_uuid", "3a3fd025-5fae-4bae-a4c5-0a2d9e4b2d2a"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDiskReadOnly(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithReadOnlyRootDisk(instanceName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "disk.#", "1"),
+					resource.TestCheckResourceAttr(resName, "disk.0.read_only", "true"),
+					func(*terraform.State) error {
+						var diskOpts linodego.InstanceDiskCreateOptions
+						if err := api.lastBody(fmt.Sprintf("POST linode/instances/%d/disks", api.instanceID()), &diskOpts); err != nil {
+							return err
+						}
+						if !diskOpts.ReadOnly {
+							return fmt.Errorf("Expected the disk to be created read-only")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockPlacementGroup(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithPlacementGroup(instanceName, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "placement_group_id", "1"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithPlacementGroup(instanceName, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "placement_group_id", "0"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithPlacementGroup(instanceName, 2),
+				ExpectError: regexp.MustCompile("the group is in region us-west, not us-east"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDiskDeviceOrder(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithOrderedDisks(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "disk.#", "3"),
+					resource.TestCheckResourceAttr(resName, "disk.0.label", "boot"),
+					resource.TestCheckResourceAttr(resName, "disk.1.label", "data"),
+					resource.TestCheckResourceAttr(resName, "disk.2.label", "swap"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sda.0.disk_label", "boot"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sdb.0.disk_label", "data"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sdc.0.disk_label", "swap"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithOrderedDisks(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "disk.#", "4"),
+					resource.TestCheckResourceAttr(resName, "disk.0.label", "boot"),
+					resource.TestCheckResourceAttr(resName, "disk.1.label", "data"),
+					resource.TestCheckResourceAttr(resName, "disk.2.label", "swap"),
+					resource.TestCheckResourceAttr(resName, "disk.3.label", "logs"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sda.0.disk_label", "boot"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sdb.0.disk_label", "data"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sdc.0.disk_label", "swap"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockImportDeviceOrder(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	// The instance is created outside of Terraform, with disks created in a different order than the devices they
+	// are attached to
+	client := api.client()
+	instance, err := client.CreateInstance(context.Background(), linodego.InstanceCreateOptions{
+		Label:  instanceName,
+		Type:   "g6-nanode-1",
+		Region: "us-east",
+	})
+	if err != nil {
+		t.Fatalf("Error creating the instance: %s", err)
+	}
+
+	diskIDs := make(map[string]int)
+	for _, opts := range []linodego.InstanceDiskCreateOptions{
+		{Label: "swap", Size: 512, Filesystem: "swap"},
+		{Label: "data", Size: 2000, Filesystem: "ext4"},
+		{Label: "boot", Size: 3000, Image: "linode/ubuntu18.04", RootPass: "b4d_p4s5"},
+	} {
+		disk, err := client.CreateInstanceDisk(context.Background(), instance.ID, opts)
+		if err != nil {
+			t.Fatalf("Error creating disk %s: %s", opts.Label, err)
+		}
+		diskIDs[opts.Label] = disk.ID
+	}
+
+	if _, err = client.CreateInstanceConfig(context.Background(), instance.ID, linodego.InstanceConfigCreateOptions{
+		Label:  "config",
+		Kernel: "linode/latest-64bit",
+		Devices: linodego.InstanceConfigDeviceMap{
+			SDA: &linodego.InstanceConfigDevice{DiskID: diskIDs["boot"]},
+			SDB: &linodego.InstanceConfigDevice{DiskID: diskIDs["data"]},
+			SDC: &linodego.InstanceConfigDevice{DiskID: diskIDs["swap"]},
+		},
+	}); err != nil {
+		t.Fatalf("Error creating the config: %s", err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccCheckLinodeInstanceWithDeviceOrder(instanceName),
+				ResourceName:  "linode_instance.foobar",
+				ImportState:   true,
+				ImportStateId: strconv.Itoa(instance.ID),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("Expected 1 imported instance, got %d", len(states))
+					}
+					attributes := states[0].Attributes
+					expected := map[string]string{
+						"disk.#":                              "3",
+						"disk.0.label":                        "boot",
+						"disk.1.label":                        "data",
+						"disk.2.label":                        "swap",
+						"config.0.devices.0.sda.0.disk_label": "boot",
+						"config.0.devices.0.sdb.0.disk_label": "data",
+						"config.0.devices.0.sdc.0.disk_label": "swap",
+					}
+					for key, value := range expected {
+						if attributes[key] != value {
+							return fmt.Errorf("Expected the imported %s to be %q, got %q", key, value, attributes[key])
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockCreateBootedFalse(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDiskAndConfigBooted(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "offline"),
+					resource.TestCheckResourceAttr(resName, "booted", "false"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "1"),
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+					func(*terraform.State) error {
+						if count := api.callCount(fmt.Sprintf("POST linode/instances/%d/boot", api.instanceID())); count != 0 {
+							return fmt.Errorf("Expected the instance not to be booted, got %d boot requests", count)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithDiskAndConfigBooted(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "booted", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockConfigHelpersReboot(t *testing.T) {
+	resName := "linode_instance.foobar"
+
+	cases := []struct {
+		name         string
+		booted       bool
+		bootDistro   bool
+		otherDistro  bool
+		reboots      int
+		expectStatus string
+	}{
+		{"running boot config", true, false, true, 1, "running"},
+		{"running other config", true, true, false, 0, "running"},
+		{"powered off boot config", false, false, true, 0, "offline"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			api := newMockLinodeAPI(t)
+			var instanceName = acctest.RandomWithPrefix("tf_test")
+
+			resource.UnitTest(t, resource.TestCase{
+				Providers:    api.providers(),
+				CheckDestroy: api.checkInstanceDestroy,
+				Steps: []resource.TestStep{
+					{
+						Config: testAccCheckLinodeInstanceWithConfigHelpers(instanceName, tc.booted, true, true),
+						Check:  resource.TestCheckResourceAttr(resName, "status", tc.expectStatus),
+					},
+					{
+						Config: testAccCheckLinodeInstanceWithConfigHelpers(instanceName, tc.booted, tc.bootDistro, tc.otherDistro),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr(resName, "status", tc.expectStatus),
+							resource.TestCheckResourceAttr(resName, "config.0.helpers.0.distro", strconv.FormatBool(tc.bootDistro)),
+							resource.TestCheckResourceAttr(resName, "config.1.helpers.0.distro", strconv.FormatBool(tc.otherDistro)),
+							func(*terraform.State) error {
+								if count := api.callCount(fmt.Sprintf("POST linode/instances/%d/reboot", api.instanceID())); count != tc.reboots {
+									return fmt.Errorf("Expected %d reboots after changing the config helpers, got %d", tc.reboots, count)
+								}
+								return nil
+							},
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestLinodeInstance_mockCreateImageBootedFalse(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithImageBooted(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "offline"),
+					resource.TestCheckResourceAttr(resName, "booted", "false"),
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockSwapSizeUnits(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, `swap_size = "1TB"`),
+				ExpectError: regexp.MustCompile("must be a number of MB, optionally with an MB or GB suffix"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, `swap_size = "1GB"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "1024"),
+					func(*terraform.State) error {
+						var createOpts linodego.InstanceCreateOptions
+						if err := api.lastBody("POST linode/instances", &createOpts); err != nil {
+							return err
+						}
+						if createOpts.SwapSize == nil || *createOpts.SwapSize != 1024 {
+							return fmt.Errorf("Expected swap_size 1GB to be sent as 1024 MB when creating the instance")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   testAccCheckLinodeInstanceWithSettings(instanceName, `swap_size = "1024MB"`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockNoSwap(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSwapSize(instanceName, "tf_test", 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "0"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "1"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sda.#", "1"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sdb.#", "0"),
+					func(*terraform.State) error {
+						var createOpts linodego.InstanceCreateOptions
+						if err := api.lastBody("POST linode/instances", &createOpts); err != nil {
+							return err
+						}
+						if createOpts.SwapSize == nil || *createOpts.SwapSize != 0 {
+							return fmt.Errorf("Expected swap_size 0 to be sent when creating the instance")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSwapSize(instanceName, "tf_test_renamed", 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "group", "tf_test_renamed"),
+					resource.TestCheckResourceAttr(resName, "swap_size", "0"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "1"),
+				),
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"root_pass", "image"},
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDefaultSwap(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithImageBooted(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "swap_size", "512"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+					resource.TestCheckResourceAttr(resName, "config.0.devices.0.sdb.#", "1"),
+					func(*terraform.State) error {
+						var createOpts linodego.InstanceCreateOptions
+						if err := api.lastBody("POST linode/instances", &createOpts); err != nil {
+							return err
+						}
+						if createOpts.SwapSize != nil {
+							return fmt.Errorf("Expected swap_size to be omitted, got %d", *createOpts.SwapSize)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockConfigVirtMode(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithConfigVirtMode(instanceName, "hvm"),
+				ExpectError: regexp.MustCompile("expected config.0.virt_mode to be one of"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithConfigVirtMode(instanceName, "fullvirt"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.virt_mode", "fullvirt"),
+					func(*terraform.State) error {
+						var configOpts linodego.InstanceConfigCreateOptions
+						if err := api.lastBody(fmt.Sprintf("POST linode/instances/%d/configs", api.instanceID()), &configOpts); err != nil {
+							return err
+						}
+						if configOpts.VirtMode != "fullvirt" {
+							return fmt.Errorf("Expected the config to be created with virt_mode fullvirt, got %q", configOpts.VirtMode)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithConfigVirtMode(instanceName, "paravirt"),
+				Check:  resource.TestCheckResourceAttr(resName, "config.0.virt_mode", "paravirt"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockBlankRootDisk(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithBlankRootDisk(instanceName, ""),
+				ExpectError: regexp.MustCompile(`its root disk "root" is blank`),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithBlankRootDisk(instanceName, "booted = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "offline"),
+					resource.TestCheckResourceAttr(resName, "disk.0.filesystem", "raw"),
+					resource.TestCheckResourceAttr(resName, "disk.0.image", ""),
+					func(*terraform.State) error {
+						if count := api.callCount("POST linode/instances"); count != 1 {
+							return fmt.Errorf("Expected a blank root disk to be rejected before creating an instance, got %d instances", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockConfigInitRD(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithConfigInitRD(instanceName, "missing"),
+				ExpectError: regexp.MustCompile("Error mapping initrd disk label missing to ID"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithConfigInitRD(instanceName, "initrd"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.initrd", "initrd"),
+					func(*terraform.State) error {
+						if count := api.callCount("POST linode/instances"); count != 1 {
+							return fmt.Errorf("Expected an invalid initrd to be rejected before creating an instance, got %d instances", count)
+						}
+						return nil
+					},
+					func(s *terraform.State) error {
+						var configOpts linodego.InstanceConfigCreateOptions
+						if err := api.lastBody(fmt.Sprintf("POST linode/instances/%d/configs", api.instanceID()), &configOpts); err != nil {
+							return err
+						}
+						if diskID := s.RootModule().Resources[resName].Primary.Attributes["disk.1.id"]; strconv.Itoa(configOpts.InitRD) != diskID {
+							return fmt.Errorf("Expected the config to be created with initrd disk %s, got %d", diskID, configOpts.InitRD)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithConfigInitRD(instanceName, ""),
+				Check:  resource.TestCheckResourceAttr(resName, "config.0.initrd", ""),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithConfigInitRD(instanceName, "missing"),
+				ExpectError: regexp.MustCompile("Error mapping initrd disk label missing to ID"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockConfigKernelInitRDUpgrade(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	var configPath string
+	var updates, reboots int
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/latest-64bit", "initrd_old"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					func(*terraform.State) error {
+						id := api.instanceID()
+						api.mu.Lock()
+						configPath = fmt.Sprintf("linode/instances/%d/configs/%d", id, api.configs[id][0].ID)
+						api.mu.Unlock()
+						updates = api.callCount("PUT " + configPath)
+						reboots = api.callCount(fmt.Sprintf("POST linode/instances/%d/reboot", id))
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/grub2", "initrd_new"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", "linode/grub2"),
+					resource.TestCheckResourceAttr(resName, "config.0.initrd", "initrd_new"),
+					func(s *terraform.State) error {
+						if count := api.callCount("PUT " + configPath); count != updates+1 {
+							return fmt.Errorf("Expected the kernel and initrd to be changed in 1 config update, got %d", count-updates)
+						}
+						var configOpts linodego.InstanceConfigUpdateOptions
+						if err := api.lastBody("PUT "+configPath, &configOpts); err != nil {
+							return err
+						}
+						diskID := s.RootModule().Resources[resName].Primary.Attributes["disk.2.id"]
+						if configOpts.Kernel != "linode/grub2" || configOpts.InitRD == nil || strconv.Itoa(*configOpts.InitRD) != diskID {
+							return fmt.Errorf("Expected the config update to set kernel linode/grub2 and initrd disk %s, got %s and %v", diskID, configOpts.Kernel, configOpts.InitRD)
+						}
+						if count := api.callCount(fmt.Sprintf("POST linode/instances/%d/reboot", api.instanceID())); count != reboots+1 {
+							return fmt.Errorf("Expected 1 reboot after changing the kernel and initrd, got %d", count-reboots)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockRebootRequired(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/latest-64bit", "initrd_old"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "reboot_required", "false"),
+				),
+			},
+			{
+				// The instance last booted an hour ago, before its config was updated outside of Terraform
+				PreConfig: func() {
+					id := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					booted := time.Now().UTC().Add(-time.Hour).Format(mockLinodeAPIDateLayout)
+					for _, event := range api.events {
+						event.CreatedStr = booted
+					}
+					api.configs[id][0].UpdatedStr = mockTimestamp()
+				},
+				Config: testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/latest-64bit", "initrd_old"),
+				Check:  resource.TestCheckResourceAttr(resName, "reboot_required", "true"),
+			},
+			{
+				// Changing the kernel reboots the instance, which boots the updated config
+				Config: testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/grub2", "initrd_new"),
+				Check:  resource.TestCheckResourceAttr(resName, "reboot_required", "false"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockCreateRollback(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	api.configCreateError = "Config creation failed"
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/latest-64bit", "initrd_old"),
+				ExpectError: regexp.MustCompile("Error creating Instance Config: .*Config creation failed"),
+			},
+			{
+				// The instance which failed to be configured was deleted, and the next apply creates a new one
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if len(api.instances) != 0 {
+						t.Errorf("Expected the partially created instance to be deleted, got %d instances", len(api.instances))
+					}
+					api.configCreateError = ""
+				},
+				Config: testAccCheckLinodeInstanceWithConfigKernelInitRD(instanceName, "linode/latest-64bit", "initrd_old"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+					func(*terraform.State) error {
+						if count := api.callCount("POST linode/instances"); count != 2 {
+							return fmt.Errorf("Expected the instance to be created again after the rollback, got %d creations", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockAlertProfile(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	checkAlerts := func(expected map[string]string) resource.TestCheckFunc {
+		checks := []resource.TestCheckFunc{}
+		for key, value := range expected {
+			checks = append(checks, resource.TestCheckResourceAttr(resName, "alerts.0."+key, value))
+		}
+		return resource.ComposeTestCheckFunc(checks...)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithAlertProfile(instanceName, 60, "web", "network_in = 20"),
+				Check: resource.ComposeTestCheckFunc(
+					checkAlerts(map[string]string{"cpu": "60", "transfer_quota": "50", "network_in": "20", "io": "10000"}),
+					resource.TestCheckResourceAttr(resName, "alert_profile_thresholds.cpu", "60"),
+				),
+			},
+			{
+				// Thresholds which follow the profile are updated with it, while overrides are kept
+				Config: testAccCheckLinodeInstanceWithAlertProfile(instanceName, 55, "web", "network_in = 20"),
+				Check:  checkAlerts(map[string]string{"cpu": "55", "transfer_quota": "50", "network_in": "20"}),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithAlertProfile(instanceName, 55, "web", "cpu = 40\n\t\tnetwork_in = 20"),
+				Check:  checkAlerts(map[string]string{"cpu": "40", "transfer_quota": "50"}),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithAlertProfile(instanceName, 50, "web", "cpu = 40\n\t\tnetwork_in = 20"),
+				Check: resource.ComposeTestCheckFunc(
+					checkAlerts(map[string]string{"cpu": "40", "transfer_quota": "50"}),
+					resource.TestCheckResourceAttr(resName, "alert_profile_thresholds.cpu", "50"),
+				),
+			},
+			{
+				// The db profile sets io, which follows it, and does not set transfer_quota, which is kept
+				Config: testAccCheckLinodeInstanceWithAlertProfile(instanceName, 50, "db", "cpu = 40\n\t\tnetwork_in = 20"),
+				Check: resource.ComposeTestCheckFunc(
+					checkAlerts(map[string]string{"cpu": "40", "io": "5000", "transfer_quota": "50", "network_in": "20"}),
+					resource.TestCheckResourceAttr(resName, "alert_profile_thresholds.%", "2"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithAlertProfile(instanceName, 50, "missing", "cpu = 40"),
+				ExpectError: regexp.MustCompile("missing is not an alert_profile of the provider"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDeletionProtection(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "deletion_protection = true"),
+				Check:  resource.TestCheckResourceAttr(resName, "deletion_protection", "true"),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, "deletion_protection = true"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion_protection is enabled"),
+			},
+			{
+				// disk_root_pass forces the instance to be replaced
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, "deletion_protection = true\n\tdisk_root_pass = \"r3pl4c3_m3\""),
+				ExpectError: regexp.MustCompile("deletion_protection is enabled"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "deletion_protection = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "deletion_protection", "false"),
+					func(*terraform.State) error {
+						if count := api.callCount(fmt.Sprintf("DELETE linode/instances/%d", api.instanceID())); count != 0 {
+							return fmt.Errorf("Expected the protected instance not to be deleted, got %d deletions", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockKernelWithExtraDisk(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", "linode/latest-64bit"),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := api.client()
+					disks, err := client.ListInstanceDisks(context.Background(), api.instanceID(), nil)
+					if err != nil {
+						t.Fatalf("Error listing the disks: %s", err)
+					}
+					// Make room for the data disk next to the root and swap disks
+					for _, disk := range disks {
+						if disk.Filesystem != linodego.FilesystemSwap {
+							if err := client.ResizeInstanceDisk(context.Background(), api.instanceID(), disk.ID, disk.Size-1000); err != nil {
+								t.Fatalf("Error resizing disk %d: %s", disk.ID, err)
+							}
+						}
+					}
+					if _, err := client.CreateInstanceDisk(context.Background(), api.instanceID(), linodego.InstanceDiskCreateOptions{Label: "data", Size: 1000, Filesystem: "ext4"}); err != nil {
+						t.Fatalf("Error adding a data disk: %s", err)
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "disk.#", "3"),
+					resource.TestCheckResourceAttr(resName, "swap_size", "512"),
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", "linode/latest-64bit"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "My linode/ubuntu18.04 Disk Profile"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDiskExpansionTarget(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		target    string
+		dataDisk  bool
+		rootSize  int
+		dataSize  int
+		autoSizes bool
+	}{
+		// The API grows the root disk of an instance with a single disk besides swap
+		{name: "default", target: "", rootSize: 51200 - 512, autoSizes: true},
+		{name: "none", target: "none", rootSize: 25600 - 512},
+		{name: "biggest", target: "biggest", dataDisk: true, rootSize: 25600 - 512 - 1000 + 25600, dataSize: 1000},
+		{name: "label", target: "data", dataDisk: true, rootSize: 25600 - 512 - 1000, dataSize: 1000 + 25600},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newMockLinodeAPI(t)
+
+			resName := "linode_instance.foobar"
+			var instanceName = acctest.RandomWithPrefix("tf_test")
+			diskSizes := func() (rootSize, dataSize int) {
+				for _, disk := range api.disks[api.instanceID()] {
+					switch {
+					case disk.Label == "data":
+						dataSize = disk.Size
+					case disk.Filesystem != linodego.FilesystemSwap:
+						rootSize = disk.Size
+					}
+				}
+				return
+			}
+
+			resource.UnitTest(t, resource.TestCase{
+				Providers:    api.providers(),
+				CheckDestroy: api.checkInstanceDestroy,
+				Steps: []resource.TestStep{
+					{
+						Config: testAccCheckLinodeInstanceWithDiskExpansionTarget(instanceName, "g6-nanode-1", tc.target),
+					},
+					{
+						PreConfig: func() {
+							if !tc.dataDisk {
+								return
+							}
+							client := api.client()
+							rootSize, _ := diskSizes()
+							for _, disk := range api.disks[api.instanceID()] {
+								if disk.Filesystem != linodego.FilesystemSwap {
+									if err := client.ResizeInstanceDisk(context.Background(), api.instanceID(), disk.ID, rootSize-1000); err != nil {
+										t.Fatalf("Error resizing disk %d: %s", disk.ID, err)
+									}
+								}
+							}
+							if _, err := client.CreateInstanceDisk(context.Background(), api.instanceID(), linodego.InstanceDiskCreateOptions{Label: "data", Size: 1000, Filesystem: "ext4"}); err != nil {
+								t.Fatalf("Error adding a data disk: %s", err)
+							}
+						},
+						Config: testAccCheckLinodeInstanceWithDiskExpansionTarget(instanceName, "g6-standard-1", tc.target),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr(resName, "type", "g6-standard-1"),
+							func(*terraform.State) error {
+								var resizeOpts map[string]interface{}
+								if err := api.lastBody(fmt.Sprintf("POST linode/instances/%d/resize", api.instanceID()), &resizeOpts); err != nil {
+									return err
+								}
+								if _, found := resizeOpts["allow_auto_disk_resize"]; found == tc.autoSizes {
+									return fmt.Errorf("Expected the resize to allow automatic disk resizing to be %t, got %v", tc.autoSizes, resizeOpts)
+								}
+
+								if rootSize, dataSize := diskSizes(); rootSize != tc.rootSize || dataSize != tc.dataSize {
+									return fmt.Errorf("Expected the root and data disks to be %d and %d MB, got %d and %d MB", tc.rootSize, tc.dataSize, rootSize, dataSize)
+								}
+								return nil
+							},
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestLinodeInstance_mockDiskExpansionTargetMissing(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDiskExpansionTarget(instanceName, "g6-nanode-1", "data"),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithDiskExpansionTarget(instanceName, "g6-standard-1", "data"),
+				ExpectError: regexp.MustCompile(`Error expanding disk "data" of Instance \d+: the instance has no such disk`),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockZeroConfigs(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "My linode/ubuntu18.04 Disk Profile"),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := api.client()
+					configs, err := client.ListInstanceConfigs(context.Background(), api.instanceID(), nil)
+					if err != nil {
+						t.Fatalf("Error listing the configs: %s", err)
+					}
+					for _, config := range configs {
+						if err := client.DeleteInstanceConfig(context.Background(), api.instanceID(), config.ID); err != nil {
+							t.Fatalf("Error deleting config %d: %s", config.ID, err)
+						}
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.#", "0"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", ""),
+					resource.TestCheckResourceAttr(resName, "boot_config_id", "0"),
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockIgnoreExtraConfigs(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	rescueConfigID := 0
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithIgnoreExtraConfigs(instanceName, "linode/latest-64bit"),
+			},
+			{
+				PreConfig: func() {
+					// A rescue config is added outside of Terraform
+					client := api.client()
+					config, err := client.CreateInstanceConfig(context.Background(), api.instanceID(), linodego.InstanceConfigCreateOptions{
+						Label:  "rescue",
+						Kernel: "linode/grub2",
+					})
+					if err != nil {
+						t.Fatalf("Error creating the rescue config: %s", err)
+					}
+					rescueConfigID = config.ID
+				},
+				Config: testAccCheckLinodeInstanceWithIgnoreExtraConfigs(instanceName, "linode/grub2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "config.#", "1"),
+					resource.TestCheckResourceAttr(resName, "config.0.label", "config"),
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", "linode/grub2"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "config"),
+					func(*terraform.State) error {
+						instanceID := api.instanceID()
+						if calls := api.callCount(fmt.Sprintf("PUT linode/instances/%d/configs/%d", instanceID, rescueConfigID)); calls != 0 {
+							return fmt.Errorf("Expected the rescue config to be left untouched, got %d updates", calls)
+						}
+						client := api.client()
+						rescue, err := client.GetInstanceConfig(context.Background(), instanceID, rescueConfigID)
+						if err != nil {
+							return fmt.Errorf("Expected the rescue config to be kept: %s", err)
+						}
+						if rescue.Kernel != "linode/grub2" {
+							return fmt.Errorf("Expected the rescue config kernel to be unchanged, got %s", rescue.Kernel)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockGPUType(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithGPUType(instanceName, "eu-west", "linode/latest-64bit"),
+				ExpectError: regexp.MustCompile("GPU types are not available in region eu-west"),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithGPUType(instanceName, "us-east", "linode/latest-32bit"),
+				ExpectError: regexp.MustCompile("GPU type g1-gpu-rtx6000-1 requires a 64 bit kernel, but the kernel is i386"),
+			},
+			{
+				PreConfig: func() {
+					if calls := api.callCount("POST linode/instances"); calls != 0 {
+						t.Errorf("Expected the invalid GPU instances to fail before they were created, got %d instance creations", calls)
+					}
+				},
+				Config: testAccCheckLinodeInstanceWithGPUType(instanceName, "us-east", "linode/latest-64bit"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "type", "g1-gpu-rtx6000-1"),
+					resource.TestCheckResourceAttr(resName, "status", "running"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockUnknownKernel(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	kernel := "linode/4.9.15-x86_64-linode81"
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithKernel(instanceName, kernel),
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						for _, k := range api.kernels {
+							if k.ID == kernel {
+								return fmt.Errorf("Expected kernel %s to be unknown to the API", kernel)
+							}
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr(resName, "config.0.kernel", kernel),
+				),
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"disk.0.root_pass", "disk.0.image", "boot_config_label"},
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockUnlistedRegion(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check:  resource.TestCheckResourceAttr(resName, "region", "us-east"),
+			},
+			{
+				// The region of the instance is no longer listed, as if the regions were stale or it was renamed
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					regions := api.regions[:0]
+					for _, region := range api.regions {
+						if region.ID != "us-east" {
+							regions = append(regions, region)
+						}
+					}
+					api.regions = regions
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check:  resource.TestCheckResourceAttr(resName, "region", "us-east"),
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"image", "root_pass"},
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockIPv4Breakdown(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithReservedIPv4(instanceName, `"203.0.113.10"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "ipv4_reserved.#", "1"),
+					resource.TestCheckResourceAttr(resName, "ipv4_reserved.0", "203.0.113.10"),
+					resource.TestCheckResourceAttr(resName, "ipv4_shared.#", "0"),
+				),
+			},
+			{
+				// An address of another instance is shared with the instance
+				PreConfig: func() {
+					instanceID := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.sharedIPs[instanceID] = []string{"198.51.100.5"}
+				},
+				Config: testAccCheckLinodeInstanceWithReservedIPv4(instanceName, `"203.0.113.10"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "ipv4_reserved.#", "1"),
+					resource.TestCheckResourceAttr(resName, "ipv4_reserved.0", "203.0.113.10"),
+					resource.TestCheckResourceAttr(resName, "ipv4_shared.#", "1"),
+					resource.TestCheckResourceAttr(resName, "ipv4_shared.0", "198.51.100.5"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockReservedIPv4(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithReservedIPv4(instanceName, `"203.0.113.30"`),
+				ExpectError: regexp.MustCompile("the address is in region us-west, not us-east"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithReservedIPv4(instanceName, `"203.0.113.10"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "reserved_ipv4.#", "1"),
+					resource.TestCheckResourceAttr(resName, "ipv4.#", "2"),
+					testLinodeInstanceReservedIPAssigned(api, "203.0.113.10", true),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithReservedIPv4(instanceName, `"203.0.113.20"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "reserved_ipv4.#", "1"),
+					resource.TestCheckResourceAttr(resName, "ipv4.#", "2"),
+					testLinodeInstanceReservedIPAssigned(api, "203.0.113.10", false),
+					testLinodeInstanceReservedIPAssigned(api, "203.0.113.20", true),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithReservedIPv4(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "reserved_ipv4.#", "0"),
+					resource.TestCheckResourceAttr(resName, "ipv4.#", "1"),
+					testLinodeInstanceReservedIPAssigned(api, "203.0.113.20", false),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockReservedIPv4Replacement(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	reservedIPv4 := `reserved_ipv4 = ["203.0.113.10"]`
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, reservedIPv4),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "linode_id", "1001"),
+					testLinodeInstanceReservedIPAssigned(api, "203.0.113.10", true),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, reservedIPv4+"\n\tauthorized_keys = [\""+publicKeyMaterial+"\"]"),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources[resName].Primary.ID; id == "1001" {
+							return fmt.Errorf("Expected changing authorized_keys to replace Linode instance %s", id)
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr(resName, "reserved_ipv4.#", "1"),
+					testLinodeInstanceReservedIPAssigned(api, "203.0.113.10", true),
+				),
+			},
+		},
+	})
+}
+
+func testLinodeInstanceReservedIPAssigned(api *mockLinodeAPI, address string, assigned bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := api.client()
+		ip, err := getReservedIP(client, address)
+		if err != nil {
+			return err
+		}
+		if (ip.LinodeID != nil) != assigned {
+			return fmt.Errorf("Expected reserved IP %s assigned to be %t", address, assigned)
+		}
+		return nil
+	}
+}
+
+func TestLinodeInstance_mockSkipInstanceReadyPoll(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	disksCall := fmt.Sprintf("GET linode/instances/%d/disks", 1001)
+	backupsCall := fmt.Sprintf("GET linode/instances/%d/backups", 1001)
+	var disksRead, backupsRead int
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSkipInstanceReadyPoll(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "disk.#", "2"),
+					resource.TestCheckResourceAttr("linode_instance.foobar", "config.#", "1"),
+					func(*terraform.State) error {
+						if api.callCount(disksCall) == 0 {
+							return fmt.Errorf("Expected the disks to be read")
+						}
+						if api.callCount(backupsCall) == 0 {
+							return fmt.Errorf("Expected the backups to be read")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					disksRead = api.callCount(disksCall)
+					backupsRead = api.callCount(backupsCall)
+				},
+				Config: testAccCheckLinodeInstanceWithSkipInstanceReadyPoll(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "disk.#", "2"),
+					resource.TestCheckResourceAttr("linode_instance.foobar", "config.#", "1"),
+					func(*terraform.State) error {
+						if n := api.callCount(disksCall); n != disksRead {
+							return fmt.Errorf("Expected no disk reads with skip_instance_ready_poll, got %d", n-disksRead)
+						}
+						if n := api.callCount(backupsCall); n != backupsRead {
+							return fmt.Errorf("Expected no backup reads with skip_instance_ready_poll, got %d", n-backupsRead)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockGroupTag(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithGroupTag(instanceName, "tf_test", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					testLinodeInstanceAPITags(api, "tf_test", "web"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithGroupTag(instanceName, "tf_test_renamed", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "group", "tf_test_renamed"),
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					testLinodeInstanceAPITags(api, "tf_test_renamed", "web"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithGroupTag(instanceName, "tf_test_renamed", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					testLinodeInstanceAPITags(api, "web"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockGroupMigration(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	var instanceID int
+
+	checkAPIGroup := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			instance := api.instances[instanceID]
+			if instance == nil {
+				return fmt.Errorf("Expected instance %d to be kept", instanceID)
+			}
+			if instance.Group != expected {
+				return fmt.Errorf("Expected the instance to have the group %q, got %q", expected, instance.Group)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithGroupMigration(instanceName, "tf_test", `tags = ["web"]`),
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						instanceID = api.instanceID()
+						return nil
+					},
+					resource.TestCheckResourceAttr(resName, "migrated_group", ""),
+					testLinodeInstanceAPITags(api, "web"),
+				),
+			},
+			{
+				// The group is moved to a tag in place, and the cleared group is not a diff afterwards
+				Config: testAccCheckLinodeInstanceWithGroupMigration(instanceName, "tf_test", `tags = ["web"]
+	group_migration = "move"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "migrated_group", "tf_test"),
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					testLinodeInstanceAPITags(api, "tf_test", "web"),
+					checkAPIGroup(""),
+				),
+			},
+			{
+				// The migration is finished by listing the tag and removing the group and group_migration
+				Config: testAccCheckLinodeInstanceWithGroupMigration(instanceName, "", `tags = ["web", "tf_test"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "migrated_group", ""),
+					resource.TestCheckResourceAttr(resName, "tags.#", "2"),
+					testLinodeInstanceAPITags(api, "tf_test", "web"),
+					checkAPIGroup(""),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDefaultTags(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDefaultTags(instanceName, `"web", "managed-by-terraform"`, `"web", "app"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "2"),
+					testLinodeInstanceAPITags(api, "app", "managed-by-terraform", "web"),
+				),
+			},
+			{
+				// A default tag which is no longer configured on the resource stays on the instance
+				Config: testAccCheckLinodeInstanceWithDefaultTags(instanceName, `"web", "managed-by-terraform"`, `"app"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					testLinodeInstanceAPITags(api, "app", "managed-by-terraform", "web"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithDefaultTags(instanceName, `"managed-by-terraform"`, `"database"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					testLinodeInstanceAPITags(api, "database", "managed-by-terraform"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockDefaultTagsAll(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithDefaultTags(instanceName, `"web"`, `"app"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					resource.TestCheckResourceAttr(resName, "tags_all.#", "2"),
+					testLinodeInstanceAPITags(api, "app", "web"),
+				),
+			},
+			{
+				// A default tag added to the provider reaches the existing instance
+				Config: testAccCheckLinodeInstanceWithDefaultTags(instanceName, `"web", "managed-by-terraform"`, `"app"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					resource.TestCheckResourceAttr(resName, "tags_all.#", "3"),
+					testLinodeInstanceAPITags(api, "app", "managed-by-terraform", "web"),
+				),
+			},
+			{
+				// A default tag removed outside of Terraform is restored
+				PreConfig: func() {
+					instanceID := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.instances[instanceID].Tags = []string{"app", "managed-by-terraform"}
+				},
+				Config: testAccCheckLinodeInstanceWithDefaultTags(instanceName, `"web", "managed-by-terraform"`, `"app"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "tags_all.#", "3"),
+					testLinodeInstanceAPITags(api, "app", "managed-by-terraform", "web"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockAutoEnableBackups(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	checkAPIBackups := func(label string, enabled bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			for _, instance := range api.instances {
+				if instance.Label != label {
+					continue
+				}
+				if instance.Backups.Enabled != enabled {
+					return fmt.Errorf("Expected backups of Instance %s to be enabled %t, got %t", label, enabled, instance.Backups.Enabled)
+				}
+				return nil
+			}
+			return fmt.Errorf("Instance %s not found", label)
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithAutoEnableBackups(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "backups_enabled", "true"),
+					resource.TestCheckResourceAttr("linode_instance.optout", "backups_enabled", "false"),
+					checkAPIBackups(instanceName, true),
+					checkAPIBackups(instanceName+"-optout", false),
+				),
+			},
+			{
+				// Existing instances keep their backups when the provider no longer enables them
+				Config: testAccCheckLinodeInstanceWithAutoEnableBackups(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "backups_enabled", "true"),
+					checkAPIBackups(instanceName, true),
+					checkAPIBackups(instanceName+"-optout", false),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockMaintenancePolicy(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	checkAPIPolicy := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			id := api.instanceID()
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if policy := api.maintenancePolicies[id]; policy != expected {
+				return fmt.Errorf("Expected the maintenance policy %q, got %q", expected, policy)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Without a maintenance_policy, the default policy is read
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "maintenance_policy", "linode/migrate"),
+					checkAPIPolicy(""),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, `maintenance_policy = "linode/power_off_on"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "maintenance_policy", "linode/power_off_on"),
+					checkAPIPolicy("linode/power_off_on"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, `maintenance_policy = "linode/reboot"`),
+				ExpectError: regexp.MustCompile(`"linode/reboot" is not one of the policies the Linode API accepts \(linode/migrate, linode/power_off_on\)`),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, `maintenance_policy = "linode/migrate"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "maintenance_policy", "linode/migrate"),
+					checkAPIPolicy("linode/migrate"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockWaitForCloudInit(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, `
+	wait_for_cloud_init = true
+	booted = false`),
+				ExpectError: regexp.MustCompile("Error validating wait_for_cloud_init: the Linode Instance must be booted"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check:  resource.TestCheckResourceAttr(resName, "wait_for_cloud_init", "false"),
+			},
+			{
+				// Existing instances do not wait for cloud-init again
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "wait_for_cloud_init = true"),
+				Check:  resource.TestCheckResourceAttr(resName, "wait_for_cloud_init", "true"),
+			},
+		},
+	})
+}
+
+// TestLinodeInstance_mockWaitForCloudInitFailed verifies that a new instance which cloud-init fails on is kept in state
+// as tainted, instead of being deleted
+func TestLinodeInstance_mockWaitForCloudInitFailed(t *testing.T) {
+	defer func(interval time.Duration) { cloudInitPollInterval = interval }(cloudInitPollInterval)
+	cloudInitPollInterval = 10 * time.Millisecond
+
+	publicKey, privateKey, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	authorizedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := []string{"status: running", "status: error"}
+	address, _, _ := newMockCloudInitSSHServer(t, authorizedKey, outputs)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(port string) { cloudInitSSHPort = port }(cloudInitSSHPort)
+	cloudInitSSHPort = port
+
+	api := newMockLinodeAPI(t)
+	api.publicIPv4 = host
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	config := testAccCheckLinodeInstanceWithSettings(instanceName, fmt.Sprintf(`
+	authorized_keys = ["%s"]
+	wait_for_cloud_init = true
+	wait_for_cloud_init_private_key = <<EOF
+%sEOF`, strings.TrimSpace(publicKey), privateKey))
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile("cloud-init is error"),
+			},
+			{
+				PreConfig: func() {
+					if calls := api.callCount(fmt.Sprintf("DELETE linode/instances/%d", api.instanceID())); calls != 0 || api.instanceID() == 0 {
+						t.Errorf("Expected the instance to be kept when cloud-init fails, got %d deletions", calls)
+					}
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// The next apply replaces the instance, which cloud-init is done on this time
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					for i := range outputs {
+						outputs[i] = "status: done"
+					}
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "cloud_init_status", "done"),
+					func(*terraform.State) error {
+						if calls := api.callCount("POST linode/instances"); calls != 2 {
+							return fmt.Errorf("Expected the instance to be replaced, got %d instance creations", calls)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockAlertTransferQuota(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	transferQuota := func(quota int) string {
+		return fmt.Sprintf(`alerts {
+		transfer_quota = %d
+	}`, quota)
+	}
+	checkAPIQuota := func(expected int) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			id := api.instanceID()
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if quota := api.instances[id].Alerts.TransferQuota; quota != expected {
+				return fmt.Errorf("Expected the transfer quota alert at %d%%, got %d%%", expected, quota)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, transferQuota(101)),
+				ExpectError: regexp.MustCompile(`expected alerts.0.transfer_quota to be in the range \(0 - 100\), got 101`),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithSettings(instanceName, transferQuota(-1)),
+				ExpectError: regexp.MustCompile(`expected alerts.0.transfer_quota to be in the range \(0 - 100\), got -1`),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, transferQuota(100)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "alerts.0.transfer_quota", "100"),
+					checkAPIQuota(100),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, transferQuota(0)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "alerts.0.transfer_quota", "0"),
+					checkAPIQuota(0),
+				),
+			},
+			{
+				// A threshold set outside of Terraform, even outside of the range, is read as it is and corrected
+				PreConfig: func() {
+					id := api.instanceID()
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.instances[id].Alerts.TransferQuota = 150
+				},
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, transferQuota(0)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "alerts.0.transfer_quota", "0"),
+					checkAPIQuota(0),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockWithoutConfig(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, `
+	create_config = false
+	booted = true`),
+				ExpectError: regexp.MustCompile("a Linode Instance created without a config can not be booted"),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, "create_config = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "status", "offline"),
+					resource.TestCheckResourceAttr(resName, "booted", "false"),
+					resource.TestCheckResourceAttr(resName, "config.#", "0"),
+					resource.TestCheckResourceAttr(resName, "boot_config_label", ""),
+					resource.TestCheckResourceAttr(resName, "disk.#", "2"),
+					func(*terraform.State) error {
+						id := api.instanceID()
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						if configs := len(api.configs[id]); configs != 0 {
+							return fmt.Errorf("Expected the Instance to have no configs, got %d", configs)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Refreshing the instance without a config plans no changes
+				Config:   testAccCheckLinodeInstanceWithSettings(instanceName, "create_config = false"),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"image", "root_pass", "create_config"},
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockLongDiskLabel(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	longLabel := strings.Repeat("d", instanceDiskLabelMaxLength+1)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithDiskLabels(instanceName, longLabel, longLabel),
+				ExpectError: regexp.MustCompile(`expected length of disk.0.label to be in the range \(1 - 48\), got ` + longLabel),
+			},
+			{
+				// A device naming a label longer than any disk can have fails when planning too
+				Config:      testAccCheckLinodeInstanceWithDiskLabels(instanceName, "disk", longLabel),
+				ExpectError: regexp.MustCompile(`expected length of config.0.devices.0.sda.0.disk_label to be in the range \(0 - 48\), got ` + longLabel),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithDiskLabels(instanceName, longLabel[1:], longLabel[1:]),
+				Check:  resource.TestCheckResourceAttr("linode_instance.foobar", "disk.0.label", longLabel[1:]),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockBootConfigID(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	checkBootConfigID := func(label string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			id := api.instanceID()
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			for _, config := range api.configs[id] {
+				if config.Label == label {
+					return resource.TestCheckResourceAttr(resName, "boot_config_id", strconv.Itoa(config.ID))(s)
+				}
+			}
+			return fmt.Errorf("Config %s not found", label)
+		}
+	}
+
+	configs := testAccCheckLinodeInstanceWithMultipleConfigs(instanceName, "")
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: configs,
+				Check:  checkBootConfigID("configa"),
+			},
+			{
+				Config: strings.Replace(configs, `boot_config_label = "configa"`, `boot_config_label = "configb"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "boot_config_label", "configb"),
+					checkBootConfigID("configb"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockRefreshRequests(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	// Each refresh lists the disks once, the other requests are counted per refresh. The instance is also fetched by
+	// Exists before it is read.
+	perRefresh := map[string]int{
+		"GET linode/instances/%d":         2,
+		"GET linode/instances/%d/configs": 1,
+		"GET linode/instances/%d/ips":     1,
+	}
+	counts := map[string]int{}
+	countRequests := func() (refreshes int, calls map[string]int) {
+		id := api.instanceID()
+		calls = map[string]int{}
+		for call := range perRefresh {
+			calls[call] = api.callCount(fmt.Sprintf(call, id))
+		}
+		return api.callCount(fmt.Sprintf("GET linode/instances/%d/disks", id)), calls
+	}
+
+	config := testAccCheckLinodeInstanceWithConfig(instanceName, "")
+	var refreshes int
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					refreshes, counts = countRequests()
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				PreConfig: func() {
+					after, calls := countRequests()
+					refreshed := after - refreshes
+					if refreshed == 0 {
+						t.Fatal("Expected the instance to be refreshed")
+					}
+					for call, expected := range perRefresh {
+						if made := calls[call] - counts[call]; made != expected*refreshed {
+							t.Errorf("Expected %d %q requests for %d refreshes, got %d", expected*refreshed, call, refreshed, made)
+						}
+					}
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockStackscriptRevision(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	// The StackScript was revised since the revision the Linode pins
+	api.stackscripts[123] = &linodego.Stackscript{ID: 123, Label: "shared", Script: "#!/bin/bash\n", RevNote: "hotfix", UpdatedStr: "2019-01-01T01:00:00"}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithStackscriptRevision(acctest.RandomWithPrefix("tf_test"), 123, "2019-01-01 00:00:00 +0000 UTC"),
+				ExpectError: regexp.MustCompile(`Linode Stackscript 123 was revised since revision 2019-01-01 00:00:00 \+0000 UTC, it is now at revision 2019-01-01 01:00:00 \+0000 UTC \(rev_note "hotfix"\)`),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithStackscriptRevision(acctest.RandomWithPrefix("tf_test"), 123, "2019-01-01 01:00:00 +0000 UTC"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("linode_instance.foobar", "stackscript_revision", "2019-01-01 01:00:00 +0000 UTC"),
+				),
+			},
+		},
+	})
+}
+
+// testLinodeInstanceAPITags verifies the tags the API has for the instance, in sorted order
+func testLinodeInstanceAPITags(api *mockLinodeAPI, expected ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := api.client()
+		instance, err := client.GetInstance(context.Background(), api.instanceID())
+		if err != nil {
+			return err
+		}
+		tags := append([]string{}, instance.Tags...)
+		sort.Strings(tags)
+		if strings.Join(tags, ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("Expected the instance to have the tags %v, got %v", expected, tags)
+		}
+		return nil
+	}
+}
+
+func TestLinodeInstance_mockLabelTrimmed(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(" "+instanceName+" ", ""),
+				Check:  resource.TestCheckResourceAttr(resName, "label", instanceName),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName+"_renamed\t", ""),
+				Check:  resource.TestCheckResourceAttr(resName, "label", instanceName+"_renamed"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockSettingsDrift(t *testing.T) {
+	resName := "linode_instance.foobar"
+
+	cases := []struct {
+		name     string
+		settings string
+		expected map[string]string
+	}{
+		{
+			name: "unset",
+			expected: map[string]string{
+				"watchdog_enabled":        "true",
+				"alerts.0.cpu":            "90",
+				"alerts.0.io":             "10000",
+				"alerts.0.network_in":     "10",
+				"alerts.0.network_out":    "10",
+				"alerts.0.transfer_quota": "80",
+				"alerts_enabled.#":        "5",
+			},
+		},
+		{
+			name:     "watchdog disabled",
+			settings: "watchdog_enabled = false",
+			expected: map[string]string{
+				"watchdog_enabled": "false",
+				"alerts.0.cpu":     "90",
+			},
+		},
+		{
+			name:     "watchdog enabled",
+			settings: "watchdog_enabled = true",
+			expected: map[string]string{
+				"watchdog_enabled": "true",
+			},
+		},
+		{
+			name: "alert disabled",
+			settings: `alerts {
+		cpu = 0
+	}`,
+			expected: map[string]string{
+				"watchdog_enabled":        "true",
+				"alerts.0.cpu":            "0",
+				"alerts.0.io":             "10000",
+				"alerts.0.transfer_quota": "80",
+				"alerts_enabled.#":        "4",
+				"alerts_enabled.0":        "io",
+			},
+		},
+		{
+			name: "alerts set",
+			settings: `alerts {
+		cpu = 50
+		io = 5000
+		network_in = 20
+		network_out = 30
+		transfer_quota = 60
+	}`,
+			expected: map[string]string{
+				"alerts.0.cpu":            "50",
+				"alerts.0.io":             "5000",
+				"alerts.0.network_in":     "20",
+				"alerts.0.network_out":    "30",
+				"alerts.0.transfer_quota": "60",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			api := newMockLinodeAPI(t)
+			var instanceName = acctest.RandomWithPrefix("tf_test")
+
+			checks := []resource.TestCheckFunc{}
+			for key, value := range tc.expected {
+				checks = append(checks, resource.TestCheckResourceAttr(resName, key, value))
+			}
+
+			resource.UnitTest(t, resource.TestCase{
+				Providers:    api.providers(),
+				CheckDestroy: api.checkInstanceDestroy,
+				Steps: []resource.TestStep{
+					{
+						Config: testAccCheckLinodeInstanceWithSettings(instanceName, tc.settings),
+						Check:  resource.ComposeTestCheckFunc(checks...),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestLinodeInstance_mockDeleteAlreadyDeleted(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithImageBooted(instanceName, true),
+				Check: func(s *terraform.State) error {
+					client := api.client()
+					if err := client.DeleteInstance(context.Background(), api.instanceID()); err != nil {
+						return err
+					}
+
+					// There is nothing to image either
+					d := resourceLinodeInstance().TestResourceData()
+					d.SetId(s.RootModule().Resources[resName].Primary.ID)
+					d.Set("create_image_on_destroy", true)
+					if err := resourceLinodeInstanceDelete(d, api.meta()); err != nil {
+						return fmt.Errorf("Expected deleting a deleted instance to succeed, got %s", err)
+					}
+					if d.Id() != "" {
+						return fmt.Errorf("Expected the deleted instance ID to be cleared")
+					}
+					return nil
+				},
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_deleteInvalidID(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	d := resourceLinodeInstance().TestResourceData()
+	d.SetId("not-a-number")
+
+	err := resourceLinodeInstanceDelete(d, api.meta())
+	if err == nil {
+		t.Fatal("Expected an error deleting an instance with an invalid ID")
+	}
+
+	expected := `Error parsing Linode instance ID not-a-number as int: strconv.ParseInt: parsing "not-a-number": invalid syntax`
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err)
+	}
+}
+
+func TestLinodeInstance_mockRDNSIPv6(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithRDNSIPv6(instanceName, "mail.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "rdns_ipv6", "mail.example.com"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithRDNSIPv6(instanceName, "mx.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "rdns_ipv6", "mx.example.com"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithImageBooted(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "rdns_ipv6", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockRDNSIPv6WithoutIPv6(t *testing.T) {
+	api := newMockLinodeAPI(t)
+	api.withoutIPv6 = true
+
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeInstanceWithRDNSIPv6(instanceName, "mail.example.com"),
+				ExpectError: regexp.MustCompile("the instance has no IPv6 address"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockBackupSchedule(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+	var updates int
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "monday", "w10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "backups.0.enabled", "true"),
+					resource.TestCheckResourceAttr(resName, "backups.0.schedule.0.day", "Monday"),
+					resource.TestCheckResourceAttr(resName, "backups.0.schedule.0.window", "W10"),
+					func(*terraform.State) error {
+						updates = api.callCount(fmt.Sprintf("PUT linode/instances/%d", api.instanceID()))
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "Monday", "W10"),
+				Check: func(*terraform.State) error {
+					if count := api.callCount(fmt.Sprintf("PUT linode/instances/%d", api.instanceID())); count != updates {
+						return fmt.Errorf("Expected the normalized backup schedule not to be updated, got %d updates", count-updates)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "Tue", "02:00"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "backups.0.schedule.0.day", "Tuesday"),
+					resource.TestCheckResourceAttr(resName, "backups.0.schedule.0.window", "W2"),
+				),
+			},
+			{
+				Config:      testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "Someday", "W3"),
+				ExpectError: regexp.MustCompile("must be a day of the week"),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_mockAvailableBackups(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_instance.foobar"
+	var instanceName = acctest.RandomWithPrefix("tf_test")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeInstanceWithSettings(instanceName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "available_backups.#", "0"),
+					func(*terraform.State) error {
+						if calls := api.callCount(fmt.Sprintf("GET linode/instances/%d/backups", api.instanceID())); calls != 0 {
+							return fmt.Errorf("Expected no backups to be listed without the Backup service, got %d calls", calls)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "Monday", "W10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "available_backups.#", "0"),
+					func(*terraform.State) error {
+						instanceID := api.instanceID()
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						api.backups = map[int]*linodego.InstanceBackupsResponse{instanceID: {
+							Automatic: []*linodego.InstanceSnapshot{{
+								ID:          123,
+								Type:        "auto",
+								Status:      linodego.SnapshotSuccessful,
+								CreatedStr:  "2019-01-01T01:00:00",
+								FinishedStr: "2019-01-01T01:10:00",
+								Configs:     []string{"My Ubuntu Profile"},
+								Disks:       []*linodego.InstanceSnapshotDisk{{Label: "Ubuntu Disk", Size: 25088, Filesystem: "ext4"}},
+							}},
+							Snapshot: &linodego.InstanceBackupSnapshotResponse{
+								InProgress: &linodego.InstanceSnapshot{ID: 456, Type: "snapshot", Label: "before-upgrade", Status: linodego.SnapshotRunning, CreatedStr: "2019-01-02T01:00:00"},
+							},
+						}}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "Monday", "W10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "available_backups.#", "2"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.id", "123"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.type", "auto"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.status", "successful"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.created", "2019-01-01T01:00:00Z"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.finished", "2019-01-01T01:10:00Z"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.configs.0", "My Ubuntu Profile"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.disks.0.label", "Ubuntu Disk"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.disks.0.size", "25088"),
+					resource.TestCheckResourceAttr(resName, "available_backups.1.id", "456"),
+					resource.TestCheckResourceAttr(resName, "available_backups.1.label", "before-upgrade"),
+					resource.TestCheckResourceAttr(resName, "available_backups.1.finished", ""),
+					func(*terraform.State) error {
+						instanceID := api.instanceID()
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						api.overrides[fmt.Sprintf("GET linode/instances/%d/backups", instanceID)] = func(w http.ResponseWriter, r *http.Request) {
+							status, body := mockAPIError(http.StatusInternalServerError, "backups are unavailable")
+							writeMockResponse(w, status, body)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The refresh does not fail when the backups can't be listed, and keeps the backups from state
+				Config: testAccCheckLinodeInstanceWithBackupSchedule(instanceName, "Monday", "W10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "available_backups.#", "2"),
+					resource.TestCheckResourceAttr(resName, "available_backups.0.id", "123"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeInstance_diskExpansionDiagnostic(t *testing.T) {
+	root := linodego.InstanceDisk{ID: 1, Label: "root", Size: 20000, Filesystem: linodego.FilesystemExt4}
+	swap := linodego.InstanceDisk{ID: 2, Label: "swap", Size: 30000, Filesystem: linodego.FilesystemSwap}
+
+	cases := []struct {
+		disks         []linodego.InstanceDisk
+		biggestDiskID int
+		typeDiskSize  int
+		expected      string
+	}{
+		{[]linodego.InstanceDisk{root}, 1, 20000, ""},
+		{[]linodego.InstanceDisk{}, 0, 25600, "has 25600 MB of unallocated disk space which was not used: the instance has no disks to expand"},
+		{[]linodego.InstanceDisk{root}, 1, 25600, "has 5600 MB of unallocated disk space which was not used: increase the size of disk \"root\" to use the space"},
+		{[]linodego.InstanceDisk{root, swap}, 2, 81920, "has 31920 MB of unallocated disk space which was not used: the biggest disk \"swap\" is swap"},
+	}
+
+	for _, c := range cases {
+		if diagnostic := diskExpansionDiagnostic(c.disks, c.biggestDiskID, c.typeDiskSize); diagnostic != c.expected {
+			t.Errorf("Expected diagnostic %q, got %q", c.expected, diagnostic)
+		}
+	}
+}
+
+func TestLinodeInstance_bootDiskValidation(t *testing.T) {
+	root := map[string]interface{}{"label": "root", "image": "linode/ubuntu18.04"}
+	blank := map[string]interface{}{"label": "blank", "image": ""}
+	config := func(label string, rootDevice string, sda string) map[string]interface{} {
+		config := map[string]interface{}{"label": label, "root_device": rootDevice, "devices": []interface{}{}}
+		if sda != "" {
+			config["devices"] = []interface{}{map[string]interface{}{
+				"sda": []interface{}{map[string]interface{}{"disk_label": sda}},
+			}}
+		}
+		return config
+	}
+
+	cases := []struct {
+		disks           []interface{}
+		configs         []interface{}
+		bootConfigLabel string
+		valid           bool
+	}{
+		{[]interface{}{root, blank}, []interface{}{config("boot", "", "")}, "", true},
+		{[]interface{}{blank, root}, []interface{}{config("boot", "", "")}, "", false},
+		{[]interface{}{blank, root}, []interface{}{config("boot", "/dev/sdb", "")}, "", true},
+		{[]interface{}{root, blank}, []interface{}{config("boot", "", "blank")}, "", false},
+		{[]interface{}{root, blank}, []interface{}{config("install", "", "blank"), config("boot", "", "root")}, "boot", true},
+		{[]interface{}{root, blank}, []interface{}{config("boot", "", "root"), config("install", "", "blank")}, "install", false},
+		{[]interface{}{blank}, []interface{}{}, "", true},
+	}
+
+	for i, c := range cases {
+		err := validateInstanceBootDisk(c.disks, c.configs, c.bootConfigLabel)
+		if c.valid && err != nil {
+			t.Errorf("Expected case %d to be valid, got %s", i, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("Expected case %d to boot a blank disk", i)
+		}
+	}
+}
+
+func TestLinodeInstance_forceNewKeys(t *testing.T) {
+	changedKeys := []string{"label", "region", "disk.0.size", "disk.1.image", "disk.#", "config.0.kernel", "stackscript_data.foo", "authorized_keys.#", "authorized_keys.0", "tags.1234"}
+	expected := []string{"authorized_keys", "disk.1.image", "region", "stackscript_data"}
+
+	forceNewKeys := instanceForceNewKeys(resourceLinodeInstance().Schema, changedKeys)
+	if strings.Join(forceNewKeys, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected the keys %v to force a new instance, got %v", expected, forceNewKeys)
+	}
+}
+
+func TestLinodeInstance_labelHostname(t *testing.T) {
+	labels := map[string]string{
+		"web-1":                 "web-1",
+		"Web_Server.1":          "web-server-1",
+		" tf_test_label ":       "tf-test-label",
+		"db--primary":           "db--primary",
+		"_leading":              "leading",
+		strings.Repeat("a", 70): strings.Repeat("a", 63),
+		"___":                   "localhost",
+	}
+	for in, expected := range labels {
+		if out := labelHostname(in); out != expected {
+			t.Errorf("labelHostname(%q) = %q, expected %q", in, out, expected)
+		}
+	}
+}
+
+func TestLinodeInstance_normalizeGroup(t *testing.T) {
+	groups := map[string]string{
+		"web":          "web",
+		" web ":        "web",
+		"web\t\n":      "web",
+		"Cafe\u0301":   "Caf\u00e9",
+		" Cafe\u0301 ": "Caf\u00e9",
+		"Caf\u00e9":    "Caf\u00e9",
+		"web servers":  "web servers",
+		"":             "",
+	}
+	for in, expected := range groups {
+		if out := normalizeGroup(in); out != expected {
+			t.Errorf("normalizeGroup(%q) = %q, expected %q", in, out, expected)
+		}
+	}
+}
+
+func TestLinodeInstance_expandAuthorizedKeys(t *testing.T) {
+	keys := []interface{}{
+		"# laptop\nssh-rsa AAAAB3Nza one@laptop\n\n  ssh-ed25519 AAAAC3Nza two@laptop  \r\n",
+		"ssh-rsa AAAAB3Nzb three@desktop",
+		"",
+		"   # only a comment\n",
+	}
+	expected := []string{"ssh-rsa AAAAB3Nza one@laptop", "ssh-ed25519 AAAAC3Nza two@laptop", "ssh-rsa AAAAB3Nzb three@desktop"}
+
+	if authorizedKeys := expandAuthorizedKeys(keys); strings.Join(authorizedKeys, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected the authorized keys %v, got %v", expected, authorizedKeys)
+	}
+}
+
+func TestLinodeInstance_imageFitsType(t *testing.T) {
+	nanode := &linodego.LinodeType{ID: "g6-nanode-1", Disk: 25600}
+
+	if err := imageFitsType(&linodego.Image{ID: "linode/ubuntu18.04", Size: 2500}, nanode, 512); err != nil {
+		t.Errorf("Expected the image to fit on a nanode, got %s", err)
+	}
+	if err := imageFitsType(&linodego.Image{ID: "private/1", Size: 25600}, nanode, 0); err != nil {
+		t.Errorf("Expected an image filling the disk to fit without swap, got %s", err)
+	}
+	if err := imageFitsType(&linodego.Image{ID: "private/1", Size: 24576}, nanode, 1024); err != nil {
+		t.Errorf("Expected an image and swap exactly filling the disk to fit, got %s", err)
+	}
+	err := imageFitsType(&linodego.Image{ID: "private/2", Size: 30000}, nanode, 0)
+	if err == nil || !strings.Contains(err.Error(), "requires a disk of at least 30000 MB, but type g6-nanode-1 only provides 25600 MB") {
+		t.Errorf("Expected an error for an image larger than the type disk, got %v", err)
+	}
+	err = imageFitsType(&linodego.Image{ID: "private/1", Size: 24576}, nanode, 1025)
+	if err == nil || !strings.Contains(err.Error(), "requires a disk of at least 24576 MB and swap_size is 1025 MB, 25601 MB in total, but type g6-nanode-1 only provides 25600 MB") {
+		t.Errorf("Expected an error with a breakdown for an image and swap larger than the type disk, got %v", err)
+	}
+}
+
+func TestLinodeInstance_expandInstanceConfigRootDevice(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		config    map[string]interface{}
+		oldConfig map[string]interface{}
+		expected  string
+		err       string
+	}{
+		{"unset", map[string]interface{}{"root_device": "", "root_device_num": 0}, nil, "", ""},
+		{"root_device", map[string]interface{}{"root_device": "/dev/sdb", "root_device_num": 0}, nil, "/dev/sdb", ""},
+		{"root_device_num", map[string]interface{}{"root_device": "", "root_device_num": 3}, nil, "/dev/sdc", ""},
+		{"both agree", map[string]interface{}{"root_device": "/dev/sdb", "root_device_num": 2}, nil, "/dev/sdb", ""},
+		{"both differ", map[string]interface{}{"label": "boot", "root_device": "/dev/sdb", "root_device_num": 1}, nil, "",
+			`config "boot": root_device "/dev/sdb" and root_device_num 1 (/dev/sda) name different devices`},
+		{"num changed",
+			map[string]interface{}{"root_device": "/dev/root", "root_device_num": 2},
+			map[string]interface{}{"root_device": "/dev/root", "root_device_num": 1}, "/dev/sdb", ""},
+		{"root_device changed",
+			map[string]interface{}{"root_device": "/dev/sdc", "root_device_num": 2},
+			map[string]interface{}{"root_device": "/dev/sdb", "root_device_num": 2}, "/dev/sdc", ""},
+	} {
+		rootDevice, err := expandInstanceConfigRootDevice(tc.config, tc.oldConfig)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.name, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || rootDevice != tc.expected {
+			t.Errorf("%s: expected root device %q, got %q (%v)", tc.name, tc.expected, rootDevice, err)
+		}
+	}
+}
+
+func TestLinodeInstance_validateCloneTarget(t *testing.T) {
+	if err := validateCloneTarget(1234, 5678, true); err != nil {
+		t.Errorf("Expected a confirmed clone_target to be valid, got %s", err)
+	}
+	if err := validateCloneTarget(1234, 0, true); err != nil {
+		t.Errorf("Expected a confirmed clone_target of a new instance to be valid, got %s", err)
+	}
+	if err := validateCloneTarget(1234, 5678, false); err == nil || !strings.Contains(err.Error(), "set confirm_overwrite = true") {
+		t.Errorf("Expected an unconfirmed clone_target to be rejected, got %v", err)
+	}
+	if err := validateCloneTarget(1234, 1234, true); err == nil || !strings.Contains(err.Error(), "onto itself") {
+		t.Errorf("Expected cloning an instance onto itself to be rejected, got %v", err)
+	}
+}
+
+func TestLinodeInstance_parseSwapSize(t *testing.T) {
+	sizes := map[string]int{
+		"512":     512,
+		"0":       0,
+		"512MB":   512,
+		"512mb":   512,
+		"2GB":     2048,
+		" 1 gb ":  1024,
+		"256 MB":  256,
+		"1.5GB":   -1,
+		"-512":    -1,
+		"512KB":   -1,
+		"":        -1,
+		"GB":      -1,
+		"swap512": -1,
+	}
+	for in, expected := range sizes {
+		mb, err := parseSwapSize(in)
+		if expected < 0 {
+			if err == nil {
+				t.Errorf("Expected parseSwapSize(%q) to fail, got %d", in, mb)
+			}
+			continue
+		}
+		if err != nil || mb != expected {
+			t.Errorf("parseSwapSize(%q) = %d, %v, expected %d", in, mb, err, expected)
+		}
+	}
+}
+
+func TestLinodeInstance_backupScheduleNormalization(t *testing.T) {
+	days := map[string]string{
+		"Monday":     "Monday",
+		"monday":     "Monday",
+		"MON":        "Monday",
+		" sunday ":   "Sunday",
+		"Sat":        "Saturday",
+		"Scheduling": "Scheduling",
+		"":           "",
+	}
+	for in, expected := range days {
+		if out := normalizeBackupScheduleDay(in); out != expected {
+			t.Errorf("normalizeBackupScheduleDay(%q) = %q, expected %q", in, out, expected)
+		}
+	}
+
+	windows := map[string]string{
+		"W0":         "W0",
+		"w10":        "W10",
+		"W02":        "W2",
+		"22":         "W22",
+		"04:00":      "W4",
+		"W3":         "W3",
+		"W24":        "W24",
+		"Scheduling": "Scheduling",
+		"":           "",
+	}
+	for in, expected := range windows {
+		if out := normalizeBackupScheduleWindow(in); out != expected {
+			t.Errorf("normalizeBackupScheduleWindow(%q) = %q, expected %q", in, out, expected)
+		}
+	}
+
+	for _, day := range []string{"Friday", "fri"} {
+		if _, errs := validateBackupScheduleDay(day, "day"); len(errs) > 0 {
+			t.Errorf("Expected %q to be valid, got %s", day, errs)
+		}
+	}
+	for _, window := range []string{"W22", "w0"} {
+		if _, errs := validateBackupScheduleWindow(window, "window"); len(errs) > 0 {
+			t.Errorf("Expected %q to be valid, got %s", window, errs)
+		}
+	}
+	if _, errs := validateBackupScheduleWindow("W23", "window"); len(errs) == 0 {
+		t.Errorf("Expected an odd backup window to be rejected")
+	}
+}
+
+func TestLinodeInstance_imageBeforeDeleteLabel(t *testing.T) {
+	for _, tc := range []struct {
+		label     string
+		expected  string
+		truncated bool
+	}{
+		{"web", "web-12345678", false},
+		{strings.Repeat("a", 41), strings.Repeat("a", 41) + "-12345678", false},
+		{strings.Repeat("a", 42), strings.Repeat("a", 41) + "-12345678", true},
+		{strings.Repeat("a", 64), strings.Repeat("a", 41) + "-12345678", true},
+	} {
+		label, truncated := imageBeforeDeleteLabel(tc.label, 12345678)
+		if label != tc.expected {
+			t.Errorf("Expected the Image label %q for %q, got %q", tc.expected, tc.label, label)
+		}
+		if truncated != tc.truncated {
+			t.Errorf("Expected the truncation of %q to be reported as %t, got %t", tc.label, tc.truncated, truncated)
+		}
+		if len(label) > imageLabelMaxLength {
+			t.Errorf("Expected the Image label %q to be at most %d characters", label, imageLabelMaxLength)
+		}
+	}
+}
+
+func TestLinodeInstance_waitForCloudInit(t *testing.T) {
+	defer func(interval time.Duration) { cloudInitPollInterval = interval }(cloudInitPollInterval)
+	cloudInitPollInterval = 10 * time.Millisecond
+
+	publicKey, privateKey, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	_, otherPrivateKey, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	otherHostKey, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	authorizedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		privateKey string
+		hostKey    string
+		outputs    []string
+		err        string
+	}{
+		{name: "done", privateKey: privateKey, outputs: []string{"status: running", "status: running", "status: done"}},
+		{name: "error", privateKey: privateKey, outputs: []string{"status: running", "status: error"}, err: "cloud-init is error"},
+		{name: "not installed", privateKey: privateKey, outputs: []string{""}, err: "cloud-init is not installed"},
+		{name: "still running", privateKey: privateKey, outputs: []string{"status: running"}, err: "Timed-out waiting for cloud-init on .*: cloud-init is running"},
+		{name: "wrong key", privateKey: otherPrivateKey, outputs: []string{"status: done"}, err: "Timed-out waiting for cloud-init on .*: ssh: handshake failed"},
+		{name: "host key", privateKey: privateKey, hostKey: "server", outputs: []string{"status: running", "status: done"}},
+		{name: "wrong host key", privateKey: privateKey, hostKey: otherHostKey, outputs: []string{"status: done"}, err: "Timed-out waiting for cloud-init on .*: ssh: handshake failed: ssh: host key mismatch"},
+		{name: "agent", outputs: []string{"status: running", "status: done"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			address, serverKey, checks := newMockCloudInitSSHServer(t, authorizedKey, tc.outputs)
+			hostKey := tc.hostKey
+			if hostKey == "server" {
+				hostKey = string(ssh.MarshalAuthorizedKey(serverKey))
+			}
+			if tc.privateKey == "" {
+				newMockSSHAgent(t, privateKey)
+			}
+
+			config, closeAgent, err := instanceCloudInitSSHConfig(tc.privateKey, hostKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeAgent()
+
+			_, err = waitForInstanceCloudInit(address, config, 200*time.Millisecond)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Expected cloud-init to be done, got %s", err)
+				}
+				if *checks != len(tc.outputs) {
+					t.Errorf("Expected %d status checks, got %d", len(tc.outputs), *checks)
+				}
+				return
+			}
+			if err == nil || !regexp.MustCompile(tc.err).MatchString(err.Error()) {
+				t.Fatalf("Expected an error matching %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestLinodeInstance_cloudInitSSHConfigHostKey(t *testing.T) {
+	_, privateKey, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	hostKeys := make([]ssh.PublicKey, 2)
+	for i := range hostKeys {
+		publicKey, _, err := acctest.RandSSHKeyPair("linode@ssh-acceptance-test")
+		if err != nil {
+			t.Fatalf("Cannot generate test SSH key pair: %s", err)
+		}
+		if hostKeys[i], _, _, _, err = ssh.ParseAuthorizedKey([]byte(publicKey)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, _, err := instanceCloudInitSSHConfig(privateKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first host key is trusted, and then kept for the rest of the wait
+	address := &net.TCPAddr{IP: net.ParseIP("198.51.100.1"), Port: 22}
+	if err := config.HostKeyCallback("198.51.100.1:22", address, hostKeys[0]); err != nil {
+		t.Fatalf("Expected the first host key to be trusted, got %s", err)
+	}
+	if err := config.HostKeyCallback("198.51.100.1:22", address, hostKeys[0]); err != nil {
+		t.Fatalf("Expected the trusted host key to be accepted again, got %s", err)
+	}
+	if err := config.HostKeyCallback("198.51.100.1:22", address, hostKeys[1]); err == nil || !strings.Contains(err.Error(), "host key of 198.51.100.1:22 changed") {
+		t.Fatalf("Expected a changed host key to be rejected, got %v", err)
+	}
+
+	defer func(socket string) { os.Setenv("SSH_AUTH_SOCK", socket) }(os.Getenv("SSH_AUTH_SOCK"))
+	os.Setenv("SSH_AUTH_SOCK", "")
+	if _, _, err := instanceCloudInitSSHConfig("", ""); err == nil || !strings.Contains(err.Error(), "SSH_AUTH_SOCK is empty") {
+		t.Fatalf("Expected an error without a private key or SSH agent, got %v", err)
+	}
+}
+
+// newMockSSHAgent serves an SSH agent holding the private key on a socket, which is set as SSH_AUTH_SOCK until the
+// test ends
+func newMockSSHAgent(t *testing.T, privateKey string) {
+	key, err := ssh.ParseRawPrivateKey([]byte(privateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+
+	previous := os.Getenv("SSH_AUTH_SOCK")
+	os.Setenv("SSH_AUTH_SOCK", socket)
+	t.Cleanup(func() {
+		os.Setenv("SSH_AUTH_SOCK", previous)
+		listener.Close()
+	})
+}
+
+// newMockCloudInitSSHServer starts an SSH server accepting root logins with the authorized key. Each command answers
+// with the next of the outputs and exits with status 0, or 127 for an empty output, repeating the last output when
+// they run out. It returns the address, the host key of the server and the count of commands run.
+func newMockCloudInitSSHServer(t *testing.T, authorizedKey ssh.PublicKey, outputs []string) (string, ssh.PublicKey, *int) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() != "root" || !bytes.Equal(key.Marshal(), authorizedKey.Marshal()) {
+				return nil, fmt.Errorf("key rejected for %s", conn.User())
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	checks := 0
+	nextOutput := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		output := outputs[len(outputs)-1]
+		if checks < len(outputs) {
+			output = outputs[checks]
+		}
+		checks++
+		return output
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for newChannel := range chans {
+					channel, requests, err := newChannel.Accept()
+					if err != nil {
+						continue
+					}
+					go func() {
+						defer channel.Close()
+						for req := range requests {
+							if req.Type != "exec" {
+								req.Reply(false, nil)
+								continue
+							}
+							req.Reply(true, nil)
+							var status uint32
+							output := nextOutput()
+							if output == "" {
+								output, status = "bash: cloud-init: command not found", 127
+							}
+							channel.Write([]byte(output + "\n"))
+							channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+							return
+						}
+					}()
+				}
+			}()
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+
+	return listener.Addr().String(), signer.PublicKey(), &checks
+}
+
+func testAccCheckLinodeInstanceNetworkHelperDefault(name, key string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		settings, err := getAccountSettings(client)
+		if err != nil {
+			return fmt.Errorf("Error fetching account settings: %s", err)
+		}
+
+		return resource.TestCheckResourceAttr(name, key, strconv.FormatBool(settings.NetworkHelper))(s)
+	}
+}
+
+func testAccCheckLinodeInstanceExists(name string, instance *linodego.Instance) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		id, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error parsing %v to int", rs.Primary.ID)
+		}
+
+		found, err := client.GetInstance(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("Error retrieving state of Instance %s: %s", rs.Primary.Attributes["label"], err)
+		}
+
+		*instance = *found
+
+		return nil
+	}
+}
+
+func testAccCheckLinodeInstanceDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*ProviderMeta).Client
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "linode_instance" {
+			continue
+		}
+
+		id, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error parsing %v as int", rs.Primary.ID)
+		}
+
+		if id == 0 {
+			return fmt.Errorf("should not have Linode ID 0")
+		}
+
+		_, err = client.GetInstance(context.Background(), id)
+
+		if err == nil {
+			return fmt.Errorf("should not find Linode ID %d existing after delete", id)
+		}
+
+		if apiErr, ok := err.(*linodego.Error); ok && apiErr.Code != 404 {
+			return fmt.Errorf("Error getting Linode ID %d: %s", id, err)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckLinodeInstanceAttributesPrivateNetworking(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("should have found linode_instance resource %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("should have a Linode ID")
+		}
+
+		id, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("should have an integer Linode ID: %s", err)
+		}
+
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		if err != nil {
+			return err
+		}
+
+		instanceIPs, err := client.GetInstanceIPAddresses(context.Background(), id)
+		if err != nil {
+			return err
+		}
+		if len(instanceIPs.IPv4.Private) == 0 {
+			return fmt.Errorf("should have a private ip on Linode ID %d", id)
+		}
+		return nil
+	}
+}
+
+type testDiskFunc func(disk linodego.InstanceDisk) error
+type testDisksFunc func(disk []linodego.InstanceDisk) error
+
+func testDisk(label string, diskTests ...testDiskFunc) testDisksFunc {
+	return func(disks []linodego.InstanceDisk) error {
+		for _, disk := range disks {
+			if disk.Label == label {
+				for _, test := range diskTests {
+					if err := test(disk); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("should have found Instance disk with label: %s", label)
+	}
+}
+
+func testDiskExists(diskPtr *linodego.InstanceDisk) testDiskFunc {
+	return func(disk linodego.InstanceDisk) error {
+		*diskPtr = disk
+		return nil
+	}
+}
+
+func testDiskSize(size int) testDiskFunc {
+	return func(disk linodego.InstanceDisk) error {
+		if disk.Size != size {
+			return fmt.Errorf("should have matching sizes: %d != %d", disk.Size, size)
+		}
+		return nil
+	}
+}
+
+func testAccCheckComputeInstanceDisks(instance *linodego.Instance, disksTests ...testDisksFunc) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		if instance == nil || instance.ID == 0 {
+			return fmt.Errorf("Error fetching disks: invalid Instance argument")
+		}
+
+		instanceDisks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+
+		if err != nil {
+			return fmt.Errorf("Error fetching disks: %s", err)
+		}
+
+		if len(instanceDisks) == 0 {
+			return fmt.Errorf("No disks")
+		}
+
+		for _, tests := range disksTests {
+			if err := tests(instanceDisks); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+type testConfigFunc func(config linodego.InstanceConfig) error
+type testConfigsFunc func(config []linodego.InstanceConfig) error
+
+// testConfig verifies a labeled config exists and runs many tests against that config
+func testConfig(label string, configTests ...testConfigFunc) testConfigsFunc {
+	return func(configs []linodego.InstanceConfig) error {
+		for _, config := range configs {
+			if config.Label == label {
+				for _, test := range configTests {
+					if err := test(config); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("should have found Instance config with label: %s", label)
+	}
+}
+
+func testConfigExists(configPtr *linodego.InstanceConfig) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		*configPtr = config
+		return nil
+	}
+}
+
+func testConfigLabel(label string) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		if config.Label != label {
+			return fmt.Errorf("should have matching labels: %s != %s", config.Label, label)
+		}
+		return nil
+	}
+}
+
+func testConfigKernel(kernel string) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		if config.Kernel != kernel {
+			return fmt.Errorf("should have matching kernels: %s != %s", config.Kernel, kernel)
+		}
+		return nil
+	}
+}
+
+func testConfigComments(comments string) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		if config.Comments != comments {
+			return fmt.Errorf("should have matching comments: %s != %s", config.Comments, comments)
+		}
+		return nil
+	}
+}
+
+func testConfigSDADisk(disk *linodego.InstanceDisk) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		if disk == nil || config.Devices == nil || config.Devices.SDA == nil || config.Devices.SDA.DiskID != disk.ID {
+			return fmt.Errorf("should have SDA with expected disk id")
+		}
+		return nil
+	}
+}
+
+func testConfigSDBDisk(disk *linodego.InstanceDisk) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		if disk == nil || config.Devices == nil || config.Devices.SDB == nil || config.Devices.SDB.DiskID != disk.ID {
+			return fmt.Errorf("should have SDB with expected disk id")
+		}
+		return nil
+	}
+}
+
+func testConfigSDBVolume(volume *linodego.Volume) testConfigFunc {
+	return func(config linodego.InstanceConfig) error {
+		if volume == nil || config.Devices == nil || config.Devices.SDB == nil || config.Devices.SDB.VolumeID != volume.ID {
+			return fmt.Errorf("should have SDB with expected volume id")
+		}
+		return nil
+	}
+}
+
+func instanceDiskID(disk *linodego.InstanceDisk) string {
+	return strconv.Itoa(disk.ID)
+}
+
+// testAccCheckComputeInstanceConfigs verifies any configs exist and runs config specific tests against a target instance
+func testAccCheckComputeInstanceConfigs(instance *linodego.Instance, configsTests ...testConfigsFunc) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		if instance == nil || instance.ID == 0 {
+			return fmt.Errorf("Error fetching configs: invalid Instance argument")
+		}
+
+		instanceConfigs, err := client.ListInstanceConfigs(context.Background(), instance.ID, nil)
+
+		if err != nil {
+			return fmt.Errorf("Error fetching configs: %s", err)
+		}
+
+		if len(instanceConfigs) == 0 {
+			return fmt.Errorf("No configs")
+		}
+
+		for _, tests := range configsTests {
+			if err := tests(instanceConfigs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckLinodeInstanceDiskExists(instance *linodego.Instance, label string, instanceDisk *linodego.InstanceDisk) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		if instance == nil || instance.ID == 0 {
+			return fmt.Errorf("Error fetching disks: invalid Instance argument")
+		}
+
+		instanceDisks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+
+		if err != nil {
+			return fmt.Errorf("Error fetching disks: %s", err)
+		}
+
+		if len(instanceDisks) == 0 {
+			return fmt.Errorf("No disks")
+		}
+
+		for _, disk := range instanceDisks {
+			if disk.Label == label {
+				*instanceDisk = disk
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Disk not found: %s", label)
+	}
+}
+
+func testAccCheckComputeInstanceDisk(instance *linodego.Instance, label string, size int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*ProviderMeta).Client
+
+		if instance == nil || instance.ID == 0 {
+			return fmt.Errorf("Error fetching disks: invalid Instance argument")
+		}
+
+		instanceDisks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+
+		if err != nil {
+			return fmt.Errorf("Error fetching disks: %s", err)
+		}
+
+		if len(instanceDisks) == 0 {
+			return fmt.Errorf("No disks")
+		}
+
+		for _, disk := range instanceDisks {
+			if disk.Label == label && disk.Size == size {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Disk not found: %s", label)
+	}
+}
+
+func testAccCheckLinodeInstanceBasic(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	image = "linode/ubuntu18.04"
+	region = "us-east"
+	root_pass = "terraform-test"
+	swap_size = 256
+	authorized_keys = ["%s"]
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceWithPlacementGroup(instance string, placementGroupID int) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	placement_group_id = %d
+}`, instance, placementGroupID)
+}
+
+func testAccCheckLinodeInstanceWithConfig(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	alerts {
+		cpu = 60
+	}
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+		helpers {
+			network = true
+		}
+	}
+
+	boot_config_label = "config"
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithConfigMemoryLimit(instance string, memoryLimit int) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+		comments = "memory limited"
+		memory_limit = %d
+	}
+
+	boot_config_label = "config"
+}`, instance, memoryLimit)
+}
+
+func testAccCheckLinodeInstanceWithConfigHelpersNoNetwork(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+		helpers {
+			distro = false
 		}
+	}
+
+	boot_config_label = "config"
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithMultipleConfigs(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "configa"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+	}
+	config {
+		label = "configb"
+		kernel = "linode/latest-32bit"
+		root_device = "/dev/root"
+	}
+
+	boot_config_label = "configa"
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithMultipleConfigsNoBootLabel(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "configa"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+	}
+	config {
+		label = "configb"
+		kernel = "linode/latest-32bit"
+		root_device = "/dev/root"
+	}
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithMultipleConfigsReverseOrder(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "configa"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+	}
+	config {
+		label = "configb"
+		kernel = "linode/latest-32bit"
+		root_device = "/dev/root"
+	}
+
+	boot_config_label = "configa"
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithMultipleConfigsAllUpdated(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "configa"
+		comments = "configa"
+		kernel = "linode/latest-32bit"
+		root_device = "/dev/root"
+	}
+	config {
+		label = "configb"
+		comments = "configb"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+	}
+	config {
+		label = "configc"
+		comments = "configc"
+		kernel = "linode/latest-64bit"
+		root_device = "/dev/root"
+	}
+
+	boot_config_label = "configa"
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithDiskRaw(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	disk {
+		label = "disk"
+		size = 3000
+	}
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithDiskRawDeleted(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithTag(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	tags = ["tf_test"]
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+	}
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithUpdatedTag(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	tags = ["tf_test", "tf_test_2"]
+	type = "g6-nanode-1"
+	region = "us-east"
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+	}
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithDiskRawResizedAndExpanded(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-standard-1"
+	region = "us-east"
+	disk {
+		label = "disk"
+		size = 6000
+	}
+}`, instance)
+}
+
+func testAccCheckLinodeInstanceWithDisk(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
+	}
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceMultipleDisks(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	disk {
+		label = "diska"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
+	}
+	disk {
+		label = "diskb"
+		filesystem = "swap"
+		size = 512
+	}
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceWithDiskAndConfig(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
+	}
 
-		id, err := strconv.Atoi(rs.Primary.ID)
-		if err != nil {
-			return fmt.Errorf("should have an integer Linode ID: %s", err)
-		}
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "disk" } }
+	}
+}`, instance, pubkey)
+}
 
-		client, ok := testAccProvider.Meta().(linodego.Client)
-		if !ok {
-			return fmt.Errorf("should have a linodego.Client")
-		}
+func testAccCheckLinodeInstanceWithDiskLabels(instance string, diskLabel string, deviceLabel string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
 
-		if err != nil {
-			return err
-		}
+	disk {
+		label = "%s"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
 
-		instanceIPs, err := client.GetInstanceIPAddresses(context.Background(), id)
-		if err != nil {
-			return err
-		}
-		if len(instanceIPs.IPv4.Private) == 0 {
-			return fmt.Errorf("should have a private ip on Linode ID %d", id)
-		}
-		return nil
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "%s" } }
 	}
+}`, instance, diskLabel, deviceLabel)
 }
 
-type testDiskFunc func(disk linodego.InstanceDisk) error
-type testDisksFunc func(disk []linodego.InstanceDisk) error
+func testAccCheckLinodeInstanceWithIgnoreExtraConfigs(instance string, kernel string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+	ignore_extra_configs = true
 
-func testDisk(label string, diskTests ...testDiskFunc) testDisksFunc {
-	return func(disks []linodego.InstanceDisk) error {
-		for _, disk := range disks {
-			if disk.Label == label {
-				for _, test := range diskTests {
-					if err := test(disk); err != nil {
-						return err
-					}
-				}
-				return nil
-			}
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
+
+	config {
+		label = "config"
+		kernel = "%s"
+		devices = { sda = { disk_label = "disk" } }
+	}
+}`, instance, kernel)
+}
+
+func testAccCheckLinodeInstanceWithRootDeviceNum(instance string, rootDevice string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+
+	disk {
+		label = "boot"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
+
+	disk {
+		label = "data"
+		size = 1000
+		filesystem = "ext4"
+	}
+
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = {
+			sda = { disk_label = "boot" }
+			sdb = { disk_label = "data" }
 		}
-		return fmt.Errorf("should have found Instance disk with label: %s", label)
+		%s
 	}
+}`, instance, rootDevice)
 }
 
-func testDiskExists(diskPtr *linodego.InstanceDisk) testDiskFunc {
-	return func(disk linodego.InstanceDisk) error {
-		*diskPtr = disk
-		return nil
+func testAccCheckLinodeInstanceWithOrderedDisks(instance string, logsDisk bool) string {
+	logs := ""
+	if logsDisk {
+		logs = `
+	disk {
+		label = "logs"
+		size = 1000
+		filesystem = "ext4"
 	}
+`
+	}
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+
+	disk {
+		label = "boot"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
+
+	disk {
+		label = "data"
+		size = 2000
+		filesystem = "ext4"
+	}
+
+	disk {
+		label = "swap"
+		size = 512
+		filesystem = "swap"
+	}
+%s
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+	}
+}`, instance, logs)
 }
 
-func testDiskSize(size int) testDiskFunc {
-	return func(disk linodego.InstanceDisk) error {
-		if disk.Size != size {
-			return fmt.Errorf("should have matching sizes: %d != %d", disk.Size, size)
+func testAccCheckLinodeInstanceWithImageOnDestroy(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	create_image_on_destroy = true
+
+	disk {
+		label = "boot"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 2000
+	}
+
+	disk {
+		label = "data"
+		size = 3000
+		filesystem = "ext4"
+	}
+
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = {
+			sda = { disk_label = "boot" }
+			sdb = { disk_label = "data" }
 		}
-		return nil
 	}
+}`, instance)
 }
 
-func testAccCheckComputeInstanceDisks(instance *linodego.Instance, disksTests ...testDisksFunc) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(linodego.Client)
+func testAccCheckLinodeInstanceWithDeviceOrder(instance string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
 
-		if instance == nil || instance.ID == 0 {
-			return fmt.Errorf("Error fetching disks: invalid Instance argument")
-		}
+	disk {
+		label = "boot"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
 
-		instanceDisks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+	disk {
+		label = "data"
+		size = 2000
+		filesystem = "ext4"
+	}
 
-		if err != nil {
-			return fmt.Errorf("Error fetching disks: %s", err)
-		}
+	disk {
+		label = "swap"
+		size = 512
+		filesystem = "swap"
+	}
 
-		if len(instanceDisks) == 0 {
-			return fmt.Errorf("No disks")
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = {
+			sda = { disk_label = "boot" }
+			sdb = { disk_label = "data" }
+			sdc = { disk_label = "swap" }
 		}
+	}
+}`, instance)
+}
 
-		for _, tests := range disksTests {
-			if err := tests(instanceDisks); err != nil {
-				return err
-			}
-		}
+func testAccCheckLinodeInstanceWithDiskAndConfigBooted(instance string, booted bool) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+	booted = %t
 
-		return nil
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
+
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "disk" } }
 	}
+}`, instance, booted)
 }
 
-type testConfigFunc func(config linodego.InstanceConfig) error
-type testConfigsFunc func(config []linodego.InstanceConfig) error
+func testAccCheckLinodeInstanceWithImageBooted(instance string, booted bool) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	booted = %t
+}`, instance, booted)
+}
 
-// testConfig verifies a labeled config exists and runs many tests against that config
-func testConfig(label string, configTests ...testConfigFunc) testConfigsFunc {
-	return func(configs []linodego.InstanceConfig) error {
-		for _, config := range configs {
-			if config.Label == label {
-				for _, test := range configTests {
-					if err := test(config); err != nil {
-						return err
-					}
-				}
-				return nil
-			}
-		}
-		return fmt.Errorf("should have found Instance config with label: %s", label)
+func testAccCheckLinodeInstanceWithSwapSize(instance string, group string, swapSize int) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	swap_size = %d
+}`, instance, group, swapSize)
+}
+
+func testAccCheckLinodeInstanceWithConfigKernelInitRD(instance string, kernel string, initrd string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
+
+	disk {
+		label = "initrd_old"
+		size = 32
+		filesystem = "initrd"
+	}
+
+	disk {
+		label = "initrd_new"
+		size = 32
+		filesystem = "initrd"
+	}
+
+	config {
+		label = "config"
+		kernel = "%s"
+		initrd = "%s"
+		devices = { sda = { disk_label = "disk" } }
 	}
+}`, instance, kernel, initrd)
 }
 
-func testConfigExists(configPtr *linodego.InstanceConfig) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		*configPtr = config
-		return nil
+func testAccCheckLinodeInstanceWithConfigInitRD(instance string, initrd string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
 	}
-}
 
-func testConfigLabel(label string) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		if config.Label != label {
-			return fmt.Errorf("should have matching labels: %s != %s", config.Label, label)
-		}
-		return nil
+	disk {
+		label = "initrd"
+		size = 32
+		filesystem = "initrd"
 	}
-}
 
-func testConfigKernel(kernel string) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		if config.Kernel != kernel {
-			return fmt.Errorf("should have matching kernels: %s != %s", config.Kernel, kernel)
-		}
-		return nil
+	config {
+		label = "config"
+		kernel = "linode/direct-disk"
+		initrd = "%s"
+		devices = { sda = { disk_label = "disk" } }
 	}
+}`, instance, initrd)
 }
 
-func testConfigComments(comments string) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		if config.Comments != comments {
-			return fmt.Errorf("should have matching comments: %s != %s", config.Comments, comments)
-		}
-		return nil
+func testAccCheckLinodeInstanceWithKernel(instance string, kernel string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
+
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
 	}
-}
 
-func testConfigSDADisk(disk *linodego.InstanceDisk) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		if disk == nil || config.Devices == nil || config.Devices.SDA == nil || config.Devices.SDA.DiskID != disk.ID {
-			return fmt.Errorf("should have SDA with expected disk id")
-		}
-		return nil
+	config {
+		label = "config"
+		kernel = "%s"
+		devices = { sda = { disk_label = "disk" } }
 	}
+}`, instance, kernel)
 }
 
-func testConfigSDBDisk(disk *linodego.InstanceDisk) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		if disk == nil || config.Devices == nil || config.Devices.SDB == nil || config.Devices.SDB.DiskID != disk.ID {
-			return fmt.Errorf("should have SDB with expected disk id")
-		}
-		return nil
+func testAccCheckLinodeInstanceWithGPUType(instance string, region string, kernel string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g1-gpu-rtx6000-1"
+	region = "%s"
+	group = "tf_test"
+
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
 	}
-}
 
-func testConfigSDBVolume(volume *linodego.Volume) testConfigFunc {
-	return func(config linodego.InstanceConfig) error {
-		if volume == nil || config.Devices == nil || config.Devices.SDB == nil || config.Devices.SDB.VolumeID != volume.ID {
-			return fmt.Errorf("should have SDB with expected volume id")
-		}
-		return nil
+	config {
+		label = "config"
+		kernel = "%s"
+		devices = { sda = { disk_label = "disk" } }
 	}
+}`, instance, region, kernel)
 }
 
-func instanceDiskID(disk *linodego.InstanceDisk) string {
-	return strconv.Itoa(disk.ID)
+func testAccCheckLinodeInstanceWithReservedIPv4(instance string, reservedIPs string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	reserved_ipv4 = [%s]
+}`, instance, reservedIPs)
 }
 
-// testAccCheckComputeInstanceConfigs verifies any configs exist and runs config specific tests against a target instance
-func testAccCheckComputeInstanceConfigs(instance *linodego.Instance, configsTests ...testConfigsFunc) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(linodego.Client)
-
-		if instance == nil || instance.ID == 0 {
-			return fmt.Errorf("Error fetching configs: invalid Instance argument")
-		}
-
-		instanceConfigs, err := client.ListInstanceConfigs(context.Background(), instance.ID, nil)
+func testAccCheckLinodeInstanceWithRDNSIPv6(instance string, rdns string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	rdns_ipv6 = "%s"
+}`, instance, rdns)
+}
 
-		if err != nil {
-			return fmt.Errorf("Error fetching configs: %s", err)
+func testAccCheckLinodeInstanceWithBackupSchedule(instance string, day string, window string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	backups_enabled = true
+	backups {
+		schedule {
+			day = "%s"
+			window = "%s"
 		}
+	}
+}`, instance, day, window)
+}
 
-		if len(instanceConfigs) == 0 {
-			return fmt.Errorf("No configs")
-		}
+func testAccCheckLinodeInstanceWithReadOnlyRootDisk(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
 
-		for _, tests := range configsTests {
-			if err := tests(instanceConfigs); err != nil {
-				return err
-			}
-		}
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
+		read_only = true
+	}
 
-		return nil
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "disk" } }
 	}
+}`, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceDiskExists(instance *linodego.Instance, label string, instanceDisk *linodego.InstanceDisk) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(linodego.Client)
-
-		if instance == nil || instance.ID == 0 {
-			return fmt.Errorf("Error fetching disks: invalid Instance argument")
-		}
-
-		instanceDisks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+func testAccCheckLinodeInstanceWithDiskAndConfigResizedAndExpanded(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-standard-1"
+	region = "us-east"
+	group = "tf_test"
 
-		if err != nil {
-			return fmt.Errorf("Error fetching disks: %s", err)
-		}
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 6000
+	}
 
-		if len(instanceDisks) == 0 {
-			return fmt.Errorf("No disks")
-		}
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "disk" } }
+	}
+}`, instance, pubkey)
+}
 
-		for _, disk := range instanceDisks {
-			if disk.Label == label {
-				*instanceDisk = disk
-				return nil
-			}
-		}
+func testAccCheckLinodeInstanceWithDiskAndConfigAddedAndReordered(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-standard-1"
+	region = "us-east"
+	group = "tf_test"
 
-		return fmt.Errorf("Disk not found: %s", label)
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
 	}
-}
 
-func testAccCheckComputeInstanceDisk(instance *linodego.Instance, label string, size int) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(linodego.Client)
+	disk {
+		label = "diskb"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
+	}
 
-		if instance == nil || instance.ID == 0 {
-			return fmt.Errorf("Error fetching disks: invalid Instance argument")
+	config {
+		label = "config"
+		kernel = "linode/latest-64bit"
+		devices = {
+			sda = { disk_label = "diskb" }, 
+			sdb = { disk_label = "disk" }
 		}
+	}
+}`, instance, pubkey, pubkey)
+}
 
-		instanceDisks, err := client.ListInstanceDisks(context.Background(), instance.ID, nil)
+func testAccCheckLinodeInstanceWithMultipleDiskAndConfig(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
 
-		if err != nil {
-			return fmt.Errorf("Error fetching disks: %s", err)
-		}
+	disk {
+		label = "diska"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
+	}
 
-		if len(instanceDisks) == 0 {
-			return fmt.Errorf("No disks")
-		}
+	disk {
+		label = "diskb"
+		filesystem = "swap"
+		size = 512
+	}
 
-		for _, disk := range instanceDisks {
-			if disk.Label == label && disk.Size == size {
-				return nil
-			}
-		}
+	config {
+		label = "configa"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "diska" }, sdb = { disk_label = "diskb" } }
+	}
 
-		return fmt.Errorf("Disk not found: %s", label)
+	config {
+		label = "configb"
+		comments = "won't boot"
+		kernel = "linode/grub2"
+		devices = { sda = { disk_label = "diskb" }, sdb = { disk_label = "diska" } }
 	}
+
+	boot_config_label = "configa"
+}`, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceBasic(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithVolumeAndConfig(instance string, pubkey string) string {
 	return fmt.Sprintf(`
-resource "linode_instance" "foobar" {
+resource "linode_volume" "foo" {
 	label = "%s"
-	group = "tf_test"
-	type = "g6-nanode-1"
-	image = "linode/ubuntu18.04"
+	size = "10"
 	region = "us-east"
-	root_pass = "terraform-test"
-	swap_size = 256
-	authorized_keys = ["%s"]
-}`, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceWithConfig(instance string, pubkey string) string {
-	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
-	group = "tf_test"
 	type = "g6-nanode-1"
 	region = "us-east"
-	alerts {
-		cpu = 60
+	group = "tf_test"
+
+	disk {
+		label = "disk"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		authorized_keys = ["%s"]
+		size = 3000
 	}
+
 	config {
 		label = "config"
 		kernel = "linode/latest-64bit"
-		root_device = "/dev/root"
-		helpers {
-			network = true
+		devices = {
+			sda = { disk_label = "disk" },
+			sdb = { volume_id = "${linode_volume.foo.id}" }
 		}
 	}
-
-	boot_config_label = "config"
-}`, instance)
+}`, instance, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceWithMultipleConfigs(instance string, pubkey string) string {
+// testAccCheckLinodeInstanceSimpleUpdates is testAccCheckLinodeInstanceWithConfig with an instance and group rename
+func testAccCheckLinodeInstanceSimpleUpdates(instance string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
-	label = "%s"
-	group = "tf_test"
+	label = "%s_r"
 	type = "g6-nanode-1"
 	region = "us-east"
+	group = "tf_test_r"
+
 	config {
-		label = "configa"
+		label = "config"
 		kernel = "linode/latest-64bit"
 		root_device = "/dev/root"
 	}
-	config {
-		label = "configb"
-		kernel = "linode/latest-32bit"
-		root_device = "/dev/root"
-	}
 
-	boot_config_label = "configa"
+	boot_config_label = "config"
 }`, instance)
 }
 
-func testAccCheckLinodeInstanceWithMultipleConfigsReverseOrder(instance string, pubkey string) string {
+// testAccCheckLinodeInstanceConfigSimpleUpdates is testAccCheckLinodeInstanceWithConfig with an instance and group rename and a different kernel
+func testAccCheckLinodeInstanceConfigSimpleUpdates(instance string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
-	label = "%s"
-	group = "tf_test"
+	label = "%s_r"
 	type = "g6-nanode-1"
 	region = "us-east"
-	config {
-		label = "configa"
-		kernel = "linode/latest-64bit"
-		root_device = "/dev/root"
+	group = "tf_test_r"
+
+	alerts {
+		cpu = 80
 	}
+
 	config {
-		label = "configb"
+		label = "config"
 		kernel = "linode/latest-32bit"
 		root_device = "/dev/root"
+		helpers {
+			network = false
+		}
 	}
-
-	boot_config_label = "configa"
+	boot_config_label = "config"
 }`, instance)
 }
 
-func testAccCheckLinodeInstanceWithMultipleConfigsAllUpdated(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceConfigUpsizeSmall(instance string, pubkey string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
-	group = "tf_test"
 	type = "g6-nanode-1"
+	image = "linode/ubuntu18.04"
 	region = "us-east"
-	config {
-		label = "configa"
-		comments = "configa"
-		kernel = "linode/latest-32bit"
-		root_device = "/dev/root"
-	}
-	config {
-		label = "configb"
-		comments = "configb"
-		kernel = "linode/latest-64bit"
-		root_device = "/dev/root"
-	}
-	config {
-		label = "configc"
-		comments = "configc"
-		kernel = "linode/latest-64bit"
-		root_device = "/dev/root"
-	}
+	root_pass = "terraform-test"
+	swap_size = 512
+	authorized_keys = ["%s"]
+	group = "tf_test"
+}`, instance, pubkey)
+}
 
-	boot_config_label = "configa"
-}`, instance)
+func testAccCheckLinodeInstanceConfigUpsizeBigger(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-standard-1"
+	image = "linode/ubuntu18.04"
+	region = "us-east"
+	root_pass = "terraform-test"
+	swap_size = 512
+	authorized_keys = ["%s"]
+	group = "tf_test"
+}`, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceWithDiskRaw(instance string) string {
+func testAccCheckLinodeInstanceConfigUpsizeBiggerBootedFalse(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-standard-1"
+	image = "linode/ubuntu18.04"
+	region = "us-east"
+	root_pass = "terraform-test"
+	swap_size = 512
+	authorized_keys = ["%s"]
+	group = "tf_test"
+	booted = false
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceConfigUpsizeBiggestBootedTrue(instance string, pubkey string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
+	type = "g6-standard-2"
+	image = "linode/ubuntu18.04"
+	region = "us-east"
+	root_pass = "terraform-test"
+	swap_size = 512
+	authorized_keys = ["%s"]
 	group = "tf_test"
+	booted = true
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceConfigDownsize(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s_downsized"
 	type = "g6-nanode-1"
+	image = "linode/ubuntu18.04"
 	region = "us-east"
-	disk {
-		label = "disk"
-		size = 3000
-	}
-}`, instance)
+	root_pass = "terraform-test"
+	swap_size = 256
+	authorized_keys = ["%s"]
+	group = "tf_test"
+}`, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceWithDiskRawDeleted(instance string) string {
+func testAccCheckLinodeInstanceConfigUpsizeExpandDisk(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s_expanded"
+	type = "g6-standard-1"
+	disk_expansion = true
+	image = "linode/ubuntu18.04"
+	region = "us-east"
+	root_pass = "terraform-test"
+	swap_size = 256
+	authorized_keys = ["%s"]
+	group = "tf_test"
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceConfigPrivateNetworking(instance string, pubkey string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	type = "g6-nanode-1"
+	image = "linode/ubuntu18.04"
+	region = "us-east"
+	root_pass = "terraform-test"
+	swap_size = 256
+	private_ip = true
+	authorized_keys = ["%s"]
+	group = "tf_test"
+}`, instance, pubkey)
+}
+
+func testAccCheckLinodeInstanceAuthorizedUsers(instance string, pubkey string) string {
 	return fmt.Sprintf(`
+data "linode_profile" "profile" {}
+
+resource "linode_sshkey" "key" {
+	label = "tf_test_authorized_keys"
+	ssh_key = "%s"
+}
+
 resource "linode_instance" "foobar" {
 	label = "%s"
 	group = "tf_test"
 	type = "g6-nanode-1"
+	image = "linode/ubuntu18.04"
 	region = "us-east"
-}`, instance)
+	root_pass = "terraform-test"
+	swap_size = 256
+	authorized_users = [ "${data.linode_profile.profile.username}" ]
+}`, pubkey, instance)
 }
 
-func testAccCheckLinodeInstanceWithTag(instance string) string {
+func testAccCheckLinodeInstanceWithSkipInstanceReadyPoll(instance string, skip bool) string {
 	return fmt.Sprintf(`
+provider "linode" {
+	skip_instance_ready_poll = %t
+}
+
 resource "linode_instance" "foobar" {
 	label = "%s"
-	tags = ["tf_test"]
+	group = "tf_test"
 	type = "g6-nanode-1"
 	region = "us-east"
-	config {
-		label = "config"
-		kernel = "linode/latest-64bit"
-	}
-}`, instance)
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	backups_enabled = true
+}`, skip, instance)
 }
 
-func testAccCheckLinodeInstanceWithUpdatedTag(instance string) string {
+func testAccCheckLinodeInstanceWithSettings(instance string, settings string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
-	tags = ["tf_test", "tf_test_2"]
+	group = "tf_test"
 	type = "g6-nanode-1"
 	region = "us-east"
-	config {
-		label = "config"
-		kernel = "linode/latest-64bit"
-	}
-}`, instance)
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	%s
+}`, instance, settings)
 }
 
-func testAccCheckLinodeInstanceWithDiskRawResizedAndExpanded(instance string) string {
+func testAccCheckLinodeInstanceWithDefaultTags(instance string, defaultTags string, tags string) string {
 	return fmt.Sprintf(`
+provider "linode" {
+	default_tags = [%s]
+}
+
 resource "linode_instance" "foobar" {
 	label = "%s"
 	group = "tf_test"
-	type = "g6-standard-1"
+	type = "g6-nanode-1"
 	region = "us-east"
-	disk {
-		label = "disk"
-		size = 6000
-	}
-}`, instance)
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	tags = [%s]
+}`, defaultTags, instance, tags)
 }
 
-func testAccCheckLinodeInstanceWithDisk(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithAutoEnableBackups(instance string, autoEnable bool) string {
 	return fmt.Sprintf(`
+provider "linode" {
+	auto_enable_backups = %t
+}
+
 resource "linode_instance" "foobar" {
 	label = "%s"
 	group = "tf_test"
 	type = "g6-nanode-1"
 	region = "us-east"
-	disk {
-		label = "disk"
-		image = "linode/ubuntu18.04"
-		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
-		size = 3000
-	}
-}`, instance, pubkey)
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
 }
 
-func testAccCheckLinodeInstanceMultipleDisks(instance string, pubkey string) string {
-	return fmt.Sprintf(`
-resource "linode_instance" "foobar" {
-	label = "%s"
+resource "linode_instance" "optout" {
+	label = "%s-optout"
 	group = "tf_test"
 	type = "g6-nanode-1"
 	region = "us-east"
-	disk {
-		label = "diska"
-		image = "linode/ubuntu18.04"
-		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
-		size = 3000
-	}
-	disk {
-		label = "diskb"
-		filesystem = "swap"
-		size = 512
-	}
-}`, instance, pubkey)
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	backups_enabled = false
+}`, autoEnable, instance, instance)
 }
 
-func testAccCheckLinodeInstanceWithDiskAndConfig(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithStackscriptRevision(instance string, stackscriptID int, revision string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
 	type = "g6-nanode-1"
 	region = "us-east"
-	group = "tf_test"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	stackscript_id = %d
+	stackscript_revision = "%s"
+}`, instance, stackscriptID, revision)
+}
 
-	disk {
-		label = "disk"
-		image = "linode/ubuntu18.04"
-		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
-		size = 3000
+func testAccCheckLinodeInstanceWithAlertProfile(instance string, webCPU int, profile string, alerts string) string {
+	return fmt.Sprintf(`
+provider "linode" {
+	alert_profile {
+		name = "web"
+		cpu = %d
+		transfer_quota = 50
 	}
 
-	config {
-		label = "config"
-		kernel = "linode/latest-64bit"
-		devices = { sda = { disk_label = "disk" } }
+	alert_profile {
+		name = "db"
+		cpu = 70
+		io = 5000
 	}
-}`, instance, pubkey)
 }
 
-func testAccCheckLinodeInstanceWithDiskAndConfigResizedAndExpanded(instance string, pubkey string) string {
-	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
-	type = "g6-standard-1"
-	region = "us-east"
 	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	alert_profile = "%s"
 
-	disk {
-		label = "disk"
-		image = "linode/ubuntu18.04"
-		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
-		size = 6000
-	}
-
-	config {
-		label = "config"
-		kernel = "linode/latest-64bit"
-		devices = { sda = { disk_label = "disk" } }
+	alerts {
+		%s
 	}
-}`, instance, pubkey)
+}`, webCPU, instance, profile, alerts)
 }
 
-func testAccCheckLinodeInstanceWithDiskAndConfigAddedAndReordered(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithConfigVirtMode(instance string, virtMode string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
-	type = "g6-standard-1"
+	type = "g6-nanode-1"
 	region = "us-east"
 	group = "tf_test"
 
@@ -1584,234 +6717,221 @@ resource "linode_instance" "foobar" {
 		label = "disk"
 		image = "linode/ubuntu18.04"
 		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
-		size = 3000
-	}
-
-	disk {
-		label = "diskb"
-		image = "linode/ubuntu18.04"
-		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
 		size = 3000
 	}
 
 	config {
 		label = "config"
-		kernel = "linode/latest-64bit"
-		devices = {
-			sda = { disk_label = "diskb" }, 
-			sdb = { disk_label = "disk" }
-		}
+		kernel = "linode/direct-disk"
+		virt_mode = "%s"
+		devices = { sda = { disk_label = "disk" } }
 	}
-}`, instance, pubkey, pubkey)
+}`, instance, virtMode)
 }
 
-func testAccCheckLinodeInstanceWithMultipleDiskAndConfig(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithBlankRootDisk(instance string, booted string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
 	type = "g6-nanode-1"
 	region = "us-east"
 	group = "tf_test"
+	%s
 
 	disk {
-		label = "diska"
-		image = "linode/ubuntu18.04"
-		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
+		label = "root"
 		size = 3000
-	}
-
-	disk {
-		label = "diskb"
-		filesystem = "swap"
-		size = 512
-	}
-
-	config {
-		label = "configa"
-		kernel = "linode/latest-64bit"
-		devices = { sda = { disk_label = "diska" }, sdb = { disk_label = "diskb" } }
+		filesystem = "raw"
 	}
 
 	config {
-		label = "configb"
-		comments = "won't boot"
-		kernel = "linode/grub2"
-		devices = { sda = { disk_label = "diskb" }, sdb = { disk_label = "diska" } }
+		label = "config"
+		kernel = "linode/direct-disk"
+		devices = { sda = { disk_label = "root" } }
 	}
-
-	boot_config_label = "configa"
-}`, instance, pubkey)
+}`, instance, booted)
 }
 
-func testAccCheckLinodeInstanceWithVolumeAndConfig(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithConfigHelpers(instance string, booted bool, bootDistro bool, otherDistro bool) string {
 	return fmt.Sprintf(`
-resource "linode_volume" "foo" {
-	label = "%s"
-	size = "10"
-	region = "us-east"
-}
-
 resource "linode_instance" "foobar" {
 	label = "%s"
 	type = "g6-nanode-1"
 	region = "us-east"
 	group = "tf_test"
+	booted = %t
+	boot_config_label = "boot"
 
 	disk {
 		label = "disk"
 		image = "linode/ubuntu18.04"
 		root_pass = "b4d_p4s5"
-		authorized_keys = ["%s"]
 		size = 3000
 	}
 
 	config {
-		label = "config"
+		label = "boot"
 		kernel = "linode/latest-64bit"
-		devices = {
-			sda = { disk_label = "disk" },
-			sdb = { volume_id = "${linode_volume.foo.id}" }
+		devices = { sda = { disk_label = "disk" } }
+		helpers {
+			distro = %t
 		}
 	}
-}`, instance, instance, pubkey)
-}
-
-// testAccCheckLinodeInstanceSimpleUpdates is testAccCheckLinodeInstanceWithConfig with an instance and group rename
-func testAccCheckLinodeInstanceSimpleUpdates(instance string) string {
-	return fmt.Sprintf(`
-resource "linode_instance" "foobar" {
-	label = "%s_r"
-	type = "g6-nanode-1"
-	region = "us-east"
-	group = "tf_test_r"
 
 	config {
-		label = "config"
+		label = "other"
 		kernel = "linode/latest-64bit"
-		root_device = "/dev/root"
+		devices = { sda = { disk_label = "disk" } }
+		helpers {
+			distro = %t
+		}
 	}
-
-	boot_config_label = "config"
-}`, instance)
+}`, instance, booted, bootDistro, otherDistro)
 }
 
-// testAccCheckLinodeInstanceConfigSimpleUpdates is testAccCheckLinodeInstanceWithConfig with an instance and group rename and a different kernel
-func testAccCheckLinodeInstanceConfigSimpleUpdates(instance string) string {
+func testAccCheckLinodeInstanceWithGroupTag(instance string, group string, groupTag bool) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
-	label = "%s_r"
+	label = "%s"
+	group = "%s"
+	group_tag = %t
+	tags = ["web"]
 	type = "g6-nanode-1"
 	region = "us-east"
-	group = "tf_test_r"
-
-	alerts {
-		cpu = 80
-	}
-
-	config {
-		label = "config"
-		kernel = "linode/latest-32bit"
-		root_device = "/dev/root"
-		helpers {
-			network = false
-		}
-	}
-	boot_config_label = "config"
-}`, instance)
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+}`, instance, group, groupTag)
 }
 
-func testAccCheckLinodeInstanceConfigUpsizeSmall(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithGroupMigration(instance string, group string, settings string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
+	group = "%s"
 	type = "g6-nanode-1"
-	image = "linode/ubuntu18.04"
 	region = "us-east"
+	image = "linode/ubuntu18.04"
 	root_pass = "terraform-test"
-	swap_size = 512
-	authorized_keys = ["%s"]
-	group = "tf_test"
-}`, instance, pubkey)
+	%s
+}`, instance, group, settings)
 }
 
-func testAccCheckLinodeInstanceConfigUpsizeBigger(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithShutdownBeforeResize(instance string, instanceType string, shutdownBeforeResize bool) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
-	type = "g6-standard-1"
-	image = "linode/ubuntu18.04"
+	group = "tf_test"
+	type = "%s"
 	region = "us-east"
+	image = "linode/ubuntu18.04"
 	root_pass = "terraform-test"
-	swap_size = 512
-	authorized_keys = ["%s"]
-	group = "tf_test"
-}`, instance, pubkey)
+	shutdown_before_resize = %t
+}`, instance, instanceType, shutdownBeforeResize)
 }
 
-func testAccCheckLinodeInstanceConfigDownsize(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithDiskExpansionTarget(instance string, instanceType string, target string) string {
+	settings := ""
+	if target != "" {
+		settings = fmt.Sprintf("disk_expansion_target = %q", target)
+	}
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
-	label = "%s_downsized"
-	type = "g6-nanode-1"
-	image = "linode/ubuntu18.04"
+	label = "%s"
+	group = "tf_test"
+	type = "%s"
 	region = "us-east"
+	image = "linode/ubuntu18.04"
 	root_pass = "terraform-test"
-	swap_size = 256
-	authorized_keys = ["%s"]
+	%s
+}`, instance, instanceType, settings)
+}
+
+func testAccCheckLinodeInstanceWithRootPass(instance string, rootPass string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
 	group = "tf_test"
-}`, instance, pubkey)
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "%s"
+}`, instance, rootPass)
 }
 
-func testAccCheckLinodeInstanceConfigUpsizeExpandDisk(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithDiskRootPass(instance string, rootPass string, diskRootPass string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
-	label = "%s_expanded"
-	type = "g6-standard-1"
-	disk_expansion = true
+	label = "%s"
+	group = "tf_test"
+	type = "g6-nanode-1"
+	region = "us-east"
 	image = "linode/ubuntu18.04"
+	root_pass = "%s"
+	disk_root_pass = "%s"
+}`, instance, rootPass, diskRootPass)
+}
+
+func testAccCheckLinodeInstanceWithImage(instance string, instanceType string, image string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foobar" {
+	label = "%s"
+	group = "tf_test"
+	type = "%s"
 	region = "us-east"
+	image = "%s"
 	root_pass = "terraform-test"
-	swap_size = 256
-	authorized_keys = ["%s"]
-	group = "tf_test"
-}`, instance, pubkey)
+}`, instance, instanceType, image)
 }
 
-func testAccCheckLinodeInstanceConfigPrivateNetworking(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithImageAndSwapSize(instance string, image string, swapSize string) string {
 	return fmt.Sprintf(`
 resource "linode_instance" "foobar" {
 	label = "%s"
+	group = "tf_test"
 	type = "g6-nanode-1"
-	image = "linode/ubuntu18.04"
 	region = "us-east"
+	image = "%s"
 	root_pass = "terraform-test"
-	swap_size = 256
-	private_ip = true
-	authorized_keys = ["%s"]
-	group = "tf_test"
-}`, instance, pubkey)
+	swap_size = "%s"
+}`, instance, image, swapSize)
 }
 
-func testAccCheckLinodeInstanceAuthorizedUsers(instance string, pubkey string) string {
+func testAccCheckLinodeInstanceWithCloneTarget(instance string, cloneTarget string) string {
+	if cloneTarget != "" {
+		cloneTarget = fmt.Sprintf(`
+	clone_target {
+		source_linode_id = "${linode_instance.golden.id}"
+		%s
+	}`, cloneTarget)
+	}
 	return fmt.Sprintf(`
-data "linode_profile" "profile" {}
+resource "linode_instance" "golden" {
+	label = "%s_golden"
+	type = "g6-nanode-1"
+	region = "us-east"
+	group = "tf_test"
 
-resource "linode_sshkey" "key" {
-	label = "tf_test_authorized_keys"
-	ssh_key = "%s"
+	disk {
+		label = "golden"
+		image = "linode/ubuntu18.04"
+		root_pass = "b4d_p4s5"
+		size = 3000
+	}
+
+	config {
+		label = "golden"
+		kernel = "linode/latest-64bit"
+		devices = { sda = { disk_label = "golden" } }
+	}
 }
 
 resource "linode_instance" "foobar" {
 	label = "%s"
 	group = "tf_test"
 	type = "g6-nanode-1"
-	image = "linode/ubuntu18.04"
 	region = "us-east"
+	image = "linode/ubuntu18.04"
 	root_pass = "terraform-test"
-	swap_size = 256
-	authorized_users = [ "${data.linode_profile.profile.username}" ]
-}`, pubkey, instance)
+	%s
+}`, instance, instance, cloneTarget)
 }
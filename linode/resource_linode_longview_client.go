@@ -0,0 +1,166 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/linode/linodego"
+)
+
+// longviewClient is a Longview client as returned by the Linode API. linodego only models the client ID.
+type longviewClient struct {
+	ID          int    `json:"id"`
+	Label       string `json:"label"`
+	APIKey      string `json:"api_key"`
+	InstallCode string `json:"install_code"`
+	Created     string `json:"created"`
+}
+
+func resourceLinodeLongviewClient() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLinodeLongviewClientCreate,
+		Read:   resourceLinodeLongviewClientRead,
+		Update: resourceLinodeLongviewClientUpdate,
+		Delete: resourceLinodeLongviewClientDelete,
+		Exists: resourceLinodeLongviewClientExists,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"label": {
+				Type:         schema.TypeString,
+				Description:  "The label of the Longview Client. If no label is provided, a default will be assigned.",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringLenBetween(3, 32),
+			},
+			"api_key": {
+				Type:        schema.TypeString,
+				Description: "The API key used by the Longview agent to report data for this client.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"install_code": {
+				Type:        schema.TypeString,
+				Description: "The install code used in the Longview agent installation command.",
+				Computed:    true,
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Description: "When this Longview Client was created.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceLinodeLongviewClientExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	client := meta.(*ProviderMeta).Client
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("Error parsing Linode Longview Client ID %s as int: %s", d.Id(), err)
+	}
+
+	_, err = getLongviewClient(client, int(id))
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			d.SetId("")
+			return false, nil
+		}
+
+		return false, fmt.Errorf("Error getting Linode Longview Client ID %s: %s", d.Id(), err)
+	}
+	return true, nil
+}
+
+func resourceLinodeLongviewClientRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Error parsing Linode Longview Client ID %s as int: %s", d.Id(), err)
+	}
+
+	longview, err := getLongviewClient(client, int(id))
+	if err != nil {
+		return fmt.Errorf("Error finding the specified Linode Longview Client: %s", err)
+	}
+
+	d.Set("label", longview.Label)
+	d.Set("api_key", longview.APIKey)
+	d.Set("install_code", longview.InstallCode)
+	d.Set("created", longview.Created)
+
+	return nil
+}
+
+func resourceLinodeLongviewClientCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+
+	body := map[string]string{}
+	if label, ok := d.GetOk("label"); ok {
+		body["label"] = label.(string)
+	}
+
+	r, err := client.R(context.Background()).SetBody(body).SetResult(&longviewClient{}).Post("longview/clients")
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error creating a Linode Longview Client: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%d", r.Result().(*longviewClient).ID))
+
+	return resourceLinodeLongviewClientRead(d, meta)
+}
+
+func resourceLinodeLongviewClientUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Error parsing Linode Longview Client id %s as int: %s", d.Id(), err)
+	}
+
+	if d.HasChange("label") {
+		body := map[string]string{"label": d.Get("label").(string)}
+		r, err := client.R(context.Background()).SetBody(body).Put(fmt.Sprintf("longview/clients/%d", id))
+		if err == nil && r.IsError() {
+			err = linodego.NewError(r)
+		}
+		if err != nil {
+			return fmt.Errorf("Error updating Linode Longview Client %d: %s", id, err)
+		}
+	}
+
+	return resourceLinodeLongviewClientRead(d, meta)
+}
+
+func resourceLinodeLongviewClientDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Error parsing Linode Longview Client id %s as int", d.Id())
+	}
+
+	r, err := client.R(context.Background()).Delete(fmt.Sprintf("longview/clients/%d", id))
+	if err == nil && r.IsError() {
+		err = linodego.NewError(r)
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting Linode Longview Client %d: %s", id, err)
+	}
+	return nil
+}
+
+func getLongviewClient(client linodego.Client, id int) (*longviewClient, error) {
+	r, err := client.R(context.Background()).SetResult(&longviewClient{}).Get(fmt.Sprintf("longview/clients/%d", id))
+	if err != nil {
+		return nil, linodego.NewError(err)
+	}
+	if r.IsError() {
+		return nil, linodego.NewError(r)
+	}
+	return r.Result().(*longviewClient), nil
+}
@@ -0,0 +1,121 @@
+package linode
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/linode/linodego"
+)
+
+func TestAccLinodeLongviewClient_basic(t *testing.T) {
+	t.Parallel()
+
+	resName := "linode_longview_client.foobar"
+	var clientName = acctest.RandomWithPrefix("tf_test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeLongviewClientDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeLongviewClientConfigBasic(clientName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", clientName),
+					resource.TestCheckResourceAttrSet(resName, "api_key"),
+					resource.TestCheckResourceAttrSet(resName, "install_code"),
+					resource.TestCheckResourceAttrSet(resName, "created"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeLongviewClientConfigBasic(clientName + "_r"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", clientName+"_r"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestLinodeLongviewClient_mock(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_longview_client.foobar"
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		CheckDestroy: func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			for id := range api.longviewClients {
+				return fmt.Errorf("Linode Longview Client with id %d still exists", id)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeLongviewClientConfigBasic("tf_test_web"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", "tf_test_web"),
+					resource.TestMatchResourceAttr(resName, "api_key", regexp.MustCompile(`^MOCK-API-KEY-\d+$`)),
+					resource.TestMatchResourceAttr(resName, "install_code", regexp.MustCompile(`^MOCK-INSTALL-CODE-\d+$`)),
+					resource.TestCheckResourceAttrSet(resName, "created"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeLongviewClientConfigBasic("tf_test_db"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", "tf_test_db"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckLinodeLongviewClientDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*ProviderMeta).Client
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "linode_longview_client" {
+			continue
+		}
+
+		id, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error parsing %v to int", rs.Primary.ID)
+		}
+
+		_, err = getLongviewClient(client, id)
+
+		if err == nil {
+			return fmt.Errorf("Linode Longview Client with id %d still exists", id)
+		}
+
+		if apiErr, ok := err.(*linodego.Error); ok && apiErr.Code != 404 {
+			return fmt.Errorf("Error requesting Linode Longview Client with id %d", id)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckLinodeLongviewClientConfigBasic(label string) string {
+	return fmt.Sprintf(`
+resource "linode_longview_client" "foobar" {
+	label = "%s"
+}`, label)
+}
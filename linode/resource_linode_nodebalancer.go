@@ -14,11 +14,12 @@ import (
 
 func resourceLinodeNodeBalancer() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceLinodeNodeBalancerCreate,
-		Read:   resourceLinodeNodeBalancerRead,
-		Update: resourceLinodeNodeBalancerUpdate,
-		Delete: resourceLinodeNodeBalancerDelete,
-		Exists: resourceLinodeNodeBalancerExists,
+		Create:        resourceLinodeNodeBalancerCreate,
+		Read:          resourceLinodeNodeBalancerRead,
+		Update:        resourceLinodeNodeBalancerUpdate,
+		Delete:        resourceLinodeNodeBalancerDelete,
+		CustomizeDiff: customizeResourceTagsAllDiff,
+		Exists:        resourceLinodeNodeBalancerExists,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -88,18 +89,14 @@ func resourceLinodeNodeBalancer() *schema.Resource {
 					},
 				},
 			},
-			"tags": {
-				Type:        schema.TypeSet,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Optional:    true,
-				Description: "An array of tags applied to this object. Tags are for organizational purposes only.",
-			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
 
 func resourceLinodeNodeBalancerExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode NodeBalancer ID %s as int: %s", d.Id(), err)
@@ -126,7 +123,7 @@ func floatString(f *float64) string {
 }
 
 func resourceLinodeNodeBalancerRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode NodeBalancer ID %s as int: %s", d.Id(), err)
@@ -149,7 +146,9 @@ func resourceLinodeNodeBalancerRead(d *schema.ResourceData, meta interface{}) er
 	d.Set("region", nodebalancer.Region)
 	d.Set("ipv4", nodebalancer.IPv4)
 	d.Set("ipv6", nodebalancer.IPv6)
-	d.Set("tags", nodebalancer.Tags)
+	if err := readTags(d, meta, "nodebalancer", nodebalancer.ID, nodebalancer.Tags); err != nil {
+		return err
+	}
 	d.Set("client_conn_throttle", nodebalancer.ClientConnThrottle)
 	d.Set("created", nodebalancer.Created.Format(time.RFC3339))
 	d.Set("updated", nodebalancer.Updated.Format(time.RFC3339))
@@ -167,10 +166,7 @@ func resourceLinodeNodeBalancerRead(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceLinodeNodeBalancerCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode NodeBalancer")
-	}
+	client := meta.(*ProviderMeta).Client
 	label := d.Get("label").(string)
 	clientConnThrottle := d.Get("client_conn_throttle").(int)
 
@@ -180,10 +176,8 @@ func resourceLinodeNodeBalancerCreate(d *schema.ResourceData, meta interface{})
 		ClientConnThrottle: &clientConnThrottle,
 	}
 
-	if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
-		for _, tag := range tagsRaw.(*schema.Set).List() {
-			createOpts.Tags = append(createOpts.Tags, tag.(string))
-		}
+	if tags := expandResourceTags(d, meta); len(tags) > 0 {
+		createOpts.Tags = tags
 	}
 
 	nodebalancer, err := client.CreateNodeBalancer(context.Background(), createOpts)
@@ -196,7 +190,7 @@ func resourceLinodeNodeBalancerCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceLinodeNodeBalancerUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -208,7 +202,7 @@ func resourceLinodeNodeBalancerUpdate(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error fetching data about the current NodeBalancer: %s", err)
 	}
 
-	if d.HasChange("label") || d.HasChange("client_conn_throttle") || d.HasChange("tags") {
+	if d.HasChange("label") || d.HasChange("client_conn_throttle") {
 		label := d.Get("label").(string)
 		clientConnThrottle := d.Get("client_conn_throttle").(int)
 
@@ -218,14 +212,13 @@ func resourceLinodeNodeBalancerUpdate(d *schema.ResourceData, meta interface{})
 			ClientConnThrottle: &clientConnThrottle,
 		}
 
-		tags := []string{}
-		for _, tag := range d.Get("tags").(*schema.Set).List() {
-			tags = append(tags, tag.(string))
+		if nodebalancer, err = client.UpdateNodeBalancer(context.Background(), nodebalancer.ID, updateOpts); err != nil {
+			return err
 		}
+	}
 
-		updateOpts.Tags = &tags
-
-		if nodebalancer, err = client.UpdateNodeBalancer(context.Background(), nodebalancer.ID, updateOpts); err != nil {
+	if d.HasChange("tags") || d.HasChange("tags_all") {
+		if _, err = applyTags(client, "nodebalancer", nodebalancer.ID, nodebalancer.Tags, expandResourceTags(d, meta)); err != nil {
 			return err
 		}
 	}
@@ -234,7 +227,7 @@ func resourceLinodeNodeBalancerUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceLinodeNodeBalancerDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode NodeBalancer id %s as int", d.Id())
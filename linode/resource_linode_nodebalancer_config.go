@@ -2,10 +2,13 @@ package linode
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
@@ -22,6 +25,7 @@ func resourceLinodeNodeBalancerConfig() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceLinodeNodeBalancerConfigImport,
 		},
+		CustomizeDiff: resourceLinodeNodeBalancerConfigCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"nodebalancer_id": {
 				Type:        schema.TypeInt,
@@ -119,6 +123,11 @@ func resourceLinodeNodeBalancerConfig() *schema.Resource {
 				Description: "The fingerprint for the SSL certification this port is serving if this port is not configured to use SSL.",
 				Computed:    true,
 			},
+			"ssl_cert_expiry": {
+				Type:        schema.TypeString,
+				Description: "When the certificate in ssl_cert expires, in RFC3339 format. This is read from ssl_cert, so it is only known when ssl_cert is set in Terraform.",
+				Computed:    true,
+			},
 			"ssl_cert": {
 				Type:        schema.TypeString,
 				Description: "The certificate this port is serving. This is not returned. If set, this field will come back as `<REDACTED>`. Please use the ssl_commonname and ssl_fingerprint to identify the certificate.",
@@ -152,8 +161,62 @@ func resourceLinodeNodeBalancerConfig() *schema.Resource {
 	}
 }
 
+func resourceLinodeNodeBalancerConfigCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange("ssl_cert") {
+		if !d.NewValueKnown("ssl_cert") {
+			d.SetNewComputed("ssl_cert_expiry")
+		} else if err := d.SetNew("ssl_cert_expiry", nodeBalancerSSLCertExpiry(d.Get("ssl_cert").(string))); err != nil {
+			return err
+		}
+	}
+
+	// check leaves the check type to the API when it is not set
+	if !d.NewValueKnown("check") || d.Get("check").(string) == "" {
+		return nil
+	}
+
+	// check_path and check_body are computed, so only values which are being changed are validated.
+	// This leaves values the API returned for an earlier check type alone.
+	var checkPath, checkBody string
+	if d.HasChange("check_path") {
+		checkPath = d.Get("check_path").(string)
+	}
+	if d.HasChange("check_body") {
+		checkBody = d.Get("check_body").(string)
+	}
+
+	return validateNodeBalancerConfigCheck(d.Get("check").(string), checkPath, checkBody)
+}
+
+// nodeBalancerSSLCertExpiry returns the expiry of the first certificate of a PEM encoded ssl_cert, in RFC3339
+// format. The API does not return the certificate, so an empty or unparseable certificate has no expiry.
+func nodeBalancerSSLCertExpiry(cert string) string {
+	block, _ := pem.Decode([]byte(cert))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return ""
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		log.Printf("[WARN] unable to parse the NodeBalancer Config ssl_cert: %s", err)
+		return ""
+	}
+	return parsed.NotAfter.UTC().Format(time.RFC3339)
+}
+
+// validateNodeBalancerConfigCheck checks that check_path is only set for http and http_body checks,
+// and that check_body is only set for http_body checks.
+func validateNodeBalancerConfigCheck(check, checkPath, checkBody string) error {
+	if checkPath != "" && check != string(linodego.CheckHTTP) && check != string(linodego.CheckHTTPBody) {
+		return fmt.Errorf("Error validating NodeBalancer Config: check_path is only used when check is %q or %q, not %q", linodego.CheckHTTP, linodego.CheckHTTPBody, check)
+	}
+	if checkBody != "" && check != string(linodego.CheckHTTPBody) {
+		return fmt.Errorf("Error validating NodeBalancer Config: check_body is only used when check is %q, not %q", linodego.CheckHTTPBody, check)
+	}
+	return nil
+}
+
 func resourceLinodeNodeBalancerConfigExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode NodeBalancerConfig ID %s as int: %s", d.Id(), err)
@@ -204,7 +267,7 @@ func resourceLinodeNodeBalancerConfigImport(d *schema.ResourceData, meta interfa
 }
 
 func resourceLinodeNodeBalancerConfigRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode NodeBalancerConfig ID %s as int: %s", d.Id(), err)
@@ -240,6 +303,7 @@ func resourceLinodeNodeBalancerConfigRead(d *schema.ResourceData, meta interface
 	d.Set("ssl_key", config.SSLKey)
 	d.Set("ssl_fingerprint", config.SSLFingerprint)
 	d.Set("ssl_commonname", config.SSLCommonName)
+	d.Set("ssl_cert_expiry", nodeBalancerSSLCertExpiry(d.Get("ssl_cert").(string)))
 	nodeStatus := map[string]interface{}{
 		"up":   fmt.Sprintf("%d", config.NodesStatus.Up),
 		"down": fmt.Sprintf("%d", config.NodesStatus.Down),
@@ -252,10 +316,7 @@ func resourceLinodeNodeBalancerConfigRead(d *schema.ResourceData, meta interface
 }
 
 func resourceLinodeNodeBalancerConfigCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode NodeBalancerConfig")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	nodebalancerID := d.Get("nodebalancer_id").(int)
 
@@ -290,14 +351,14 @@ func resourceLinodeNodeBalancerConfigCreate(d *schema.ResourceData, meta interfa
 }
 
 func resourceLinodeNodeBalancerConfigUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode NodeBalancerConfig ID %s as int: %s", d.Id(), err)
 	}
 	nodebalancerID, ok := d.Get("nodebalancer_id").(int)
 	if !ok {
-		return fmt.Errorf("Error parsing Linode NodeBalancer ID %s as int", d.Get("nodebalancer_id"))
+		return fmt.Errorf("Error parsing Linode NodeBalancer ID %v as int", d.Get("nodebalancer_id"))
 	}
 
 	updateOpts := linodego.NodeBalancerConfigUpdateOptions{
@@ -328,7 +389,7 @@ func resourceLinodeNodeBalancerConfigUpdate(d *schema.ResourceData, meta interfa
 }
 
 func resourceLinodeNodeBalancerConfigDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode NodeBalancerConfig ID %s as int: %s", d.Id(), err)
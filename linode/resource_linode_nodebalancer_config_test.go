@@ -2,9 +2,18 @@ package linode
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"regexp"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
@@ -103,8 +112,84 @@ func TestAccLinodeNodeBalancerConfig_update(t *testing.T) {
 	})
 }
 
+func TestLinodeNodeBalancerConfig_validateCheck(t *testing.T) {
+	for _, tc := range []struct {
+		check     string
+		checkPath string
+		checkBody string
+		valid     bool
+	}{
+		{"http", "/health", "", true},
+		{"http_body", "/health", "ok", true},
+		{"http_body", "", "ok", true},
+		{"connection", "", "", true},
+		{"none", "", "", true},
+		{"http", "/health", "ok", false},
+		{"connection", "/health", "", false},
+		{"none", "", "ok", false},
+	} {
+		err := validateNodeBalancerConfigCheck(tc.check, tc.checkPath, tc.checkBody)
+		if tc.valid && err != nil {
+			t.Errorf("Expected check %q with check_path %q and check_body %q to be valid, got %s", tc.check, tc.checkPath, tc.checkBody, err)
+		} else if !tc.valid && err == nil {
+			t.Errorf("Expected check %q with check_path %q and check_body %q to be invalid", tc.check, tc.checkPath, tc.checkBody)
+		}
+	}
+}
+
+func TestLinodeNodeBalancerConfig_sslCertExpiry(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating a key: %s", err)
+	}
+	notAfter := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating a certificate: %s", err)
+	}
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	for _, tc := range []struct {
+		cert     string
+		expected string
+	}{
+		{cert, "2030-03-01T12:00:00Z"},
+		{"", ""},
+		{"<REDACTED>", ""},
+		{string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})), ""},
+	} {
+		if expiry := nodeBalancerSSLCertExpiry(tc.cert); expiry != tc.expected {
+			t.Errorf("Expected the expiry of %q to be %q, got %q", tc.cert, tc.expected, expiry)
+		}
+	}
+}
+
+func TestLinodeNodeBalancerConfig_mockCheckValidation(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeNodeBalancerConfigCheck("connection", `check_path = "/health"`),
+				ExpectError: regexp.MustCompile(`check_path is only used when check is "http" or "http_body"`),
+			},
+			{
+				Config:      testAccCheckLinodeNodeBalancerConfigCheck("http", `check_body = "ok"`),
+				ExpectError: regexp.MustCompile(`check_body is only used when check is "http_body"`),
+			},
+		},
+	})
+}
+
 func testAccCheckLinodeNodeBalancerConfigExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_nodebalancer_config" {
@@ -130,10 +215,7 @@ func testAccCheckLinodeNodeBalancerConfigExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeNodeBalancerConfigDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_nodebalancer_config" {
 			continue
@@ -215,3 +297,14 @@ resource "linode_nodebalancer_config" "foofig" {
 }
 `
 }
+
+func testAccCheckLinodeNodeBalancerConfigCheck(check, settings string) string {
+	return fmt.Sprintf(`
+resource "linode_nodebalancer_config" "foofig" {
+	nodebalancer_id = 1234
+	port = 8080
+	protocol = "http"
+	check = "%s"
+	%s
+}`, check, settings)
+}
@@ -87,7 +87,7 @@ func TestAccLinodeNodeBalancerNode_update(t *testing.T) {
 }
 
 func testAccCheckLinodeNodeBalancerNodeExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_nodebalancer_node" {
@@ -119,10 +119,7 @@ func testAccCheckLinodeNodeBalancerNodeExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeNodeBalancerNodeDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_nodebalancer_node" {
 			continue
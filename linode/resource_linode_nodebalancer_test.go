@@ -118,7 +118,7 @@ func TestAccLinodeNodeBalancer_update(t *testing.T) {
 }
 
 func testAccCheckLinodeNodeBalancerExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_nodebalancer" {
@@ -140,10 +140,7 @@ func testAccCheckLinodeNodeBalancerExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeNodeBalancerDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_nodebalancer" {
 			continue
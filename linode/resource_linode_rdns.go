@@ -38,7 +38,7 @@ func resourceLinodeRDNS() *schema.Resource {
 }
 
 func resourceLinodeRDNSExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	ipStr := d.Id()
 
@@ -60,7 +60,7 @@ func resourceLinodeRDNSExists(d *schema.ResourceData, meta interface{}) (bool, e
 }
 
 func resourceLinodeRDNSRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	ipStr := d.Id()
 
 	if len(ipStr) == 0 {
@@ -80,10 +80,7 @@ func resourceLinodeRDNSRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeRDNSCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode RDNS")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	var address = d.Get("address").(string)
 	var rdns *string
@@ -105,7 +102,7 @@ func resourceLinodeRDNSCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeRDNSUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	ipStr := d.Id()
 
 	if len(ipStr) == 0 {
@@ -131,7 +128,7 @@ func resourceLinodeRDNSUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeRDNSDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	ipStr := d.Id()
 
 	if len(ipStr) == 0 {
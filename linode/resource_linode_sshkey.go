@@ -42,7 +42,7 @@ func resourceLinodeSSHKey() *schema.Resource {
 }
 
 func resourceLinodeSSHKeyExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode SSH Key ID %s as int: %s", d.Id(), err)
@@ -61,7 +61,7 @@ func resourceLinodeSSHKeyExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceLinodeSSHKeyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode SSH Key ID %s as int: %s", d.Id(), err)
@@ -83,10 +83,7 @@ func resourceLinodeSSHKeyRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeSSHKeyCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode SSH Key")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	createOpts := linodego.SSHKeyCreateOptions{
 		Label:  d.Get("label").(string),
@@ -107,7 +104,7 @@ func resourceLinodeSSHKeyCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeSSHKeyUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -134,7 +131,7 @@ func resourceLinodeSSHKeyUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeSSHKeyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode SSH Key id %s as int", d.Id())
@@ -120,7 +120,7 @@ func TestAccLinodeSSHKey_update(t *testing.T) {
 }
 
 func testAccCheckLinodeSSHKeyExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_sshkey" {
@@ -142,10 +142,7 @@ func testAccCheckLinodeSSHKeyExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeSSHKeyDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_sshkey" {
 			continue
@@ -20,6 +20,7 @@ func resourceLinodeStackscript() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: resourceLinodeStackscriptCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"label": {
 				Type:        schema.TypeString,
@@ -85,6 +86,17 @@ func resourceLinodeStackscript() *schema.Resource {
 				Description: "The date this StackScript was updated.",
 				Computed:    true,
 			},
+			"pin_revision": {
+				Type:        schema.TypeBool,
+				Description: "If true, the revision of the StackScript applied by Terraform is recorded in pinned_revision. When the StackScript is revised outside of Terraform, the plan updates it to restore the configured script.",
+				Optional:    true,
+				Default:     false,
+			},
+			"pinned_revision": {
+				Type:        schema.TypeString,
+				Description: "The updated date of the StackScript revision last applied by Terraform, when pin_revision is true.",
+				Computed:    true,
+			},
 			"user_defined_fields": {
 				Description: "This is a list of fields defined with a special syntax inside this StackScript that allow for supplying customized parameters during deployment.",
 				Type:        schema.TypeSet,
@@ -123,7 +135,7 @@ func resourceLinodeStackscript() *schema.Resource {
 }
 
 func resourceLinodeStackscriptExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode Stackscript ID %s as int: %s", d.Id(), err)
@@ -141,7 +153,7 @@ func resourceLinodeStackscriptExists(d *schema.ResourceData, meta interface{}) (
 }
 
 func resourceLinodeStackscriptRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Stackscript ID %s as int: %s", d.Id(), err)
@@ -197,6 +209,15 @@ func resourceLinodeStackscriptRead(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
+	// pin_revision and pinned_revision are not known to the API, keep the recorded values (or defaults, on import)
+	d.Set("pin_revision", d.Get("pin_revision").(bool))
+	d.Set("pinned_revision", d.Get("pinned_revision").(string))
+	if d.Get("pin_revision").(bool) {
+		if warning := stackscriptRevisionWarning(d.Get("pinned_revision").(string), *stackscript); warning != "" {
+			log.Printf("[WARN] %s", warning)
+		}
+	}
+
 	if stackscript.UserDefinedFields == nil {
 		if err := d.Set("user_defined_fields", nil); err != nil {
 			return fmt.Errorf("Error setting user_defined_fields: %s", err)
@@ -222,10 +243,7 @@ func resourceLinodeStackscriptRead(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceLinodeStackscriptCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Stackscript")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	createOpts := linodego.StackscriptCreateOptions{
 		Label:       d.Get("label").(string),
@@ -245,11 +263,15 @@ func resourceLinodeStackscriptCreate(d *schema.ResourceData, meta interface{}) e
 	}
 	d.SetId(fmt.Sprintf("%d", stackscript.ID))
 
-	return resourceLinodeStackscriptRead(d, meta)
+	if err = resourceLinodeStackscriptRead(d, meta); err != nil {
+		return err
+	}
+	pinStackscriptRevision(d)
+	return nil
 }
 
 func resourceLinodeStackscriptUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -272,11 +294,48 @@ func resourceLinodeStackscriptUpdate(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error updating Linode Stackscript %d: %s", int(id), err)
 	}
 
-	return resourceLinodeStackscriptRead(d, meta)
+	if err = resourceLinodeStackscriptRead(d, meta); err != nil {
+		return err
+	}
+	pinStackscriptRevision(d)
+	return nil
+}
+
+func resourceLinodeStackscriptCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// A StackScript revised outside of Terraform since the pinned revision is planned as an update, which restores the
+	// configured script and pins the new revision
+	if d.Id() == "" || !d.Get("pin_revision").(bool) {
+		return nil
+	}
+	if pinned := d.Get("pinned_revision").(string); pinned != "" && pinned != d.Get("updated").(string) {
+		if err := d.SetNewComputed("updated"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("pinned_revision")
+	}
+	return nil
+}
+
+// pinStackscriptRevision records the revision of the StackScript which was just applied, when pin_revision is true
+func pinStackscriptRevision(d *schema.ResourceData) {
+	if d.Get("pin_revision").(bool) {
+		d.Set("pinned_revision", d.Get("updated").(string))
+	} else {
+		d.Set("pinned_revision", "")
+	}
+}
+
+// stackscriptRevisionWarning describes how a StackScript has been revised since the pinned revision. An empty string is
+// returned when the StackScript is still at the pinned revision, or no revision has been pinned.
+func stackscriptRevisionWarning(pinnedRevision string, stackscript linodego.Stackscript) string {
+	if pinnedRevision == "" || stackscript.Updated == nil || stackscript.Updated.String() == pinnedRevision {
+		return ""
+	}
+	return fmt.Sprintf("Linode Stackscript %d was revised outside of Terraform since pinned revision %s: it is now at revision %s (rev_note %q). The plan updates it to restore the configured script.", stackscript.ID, pinnedRevision, stackscript.Updated.String(), stackscript.RevNote)
 }
 
 func resourceLinodeStackscriptDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Stackscript id %s as int", d.Id())
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
@@ -161,8 +163,114 @@ func TestAccLinodeStackscript_codeChange(t *testing.T) {
 	})
 }
 
+func TestAccLinodeStackscript_pinRevision(t *testing.T) {
+	t.Parallel()
+
+	var stackscriptName = acctest.RandomWithPrefix("tf_test")
+	var resName = "linode_stackscript.foobar"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeStackscriptDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeStackscriptBasic(stackscriptName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeStackscriptExists,
+					resource.TestCheckResourceAttr(resName, "pin_revision", "false"),
+					resource.TestCheckResourceAttr(resName, "pinned_revision", ""),
+				),
+			},
+			{
+				Config: testAccCheckLinodeStackscriptPinRevision(stackscriptName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLinodeStackscriptExists,
+					resource.TestCheckResourceAttr(resName, "pin_revision", "true"),
+					resource.TestCheckResourceAttrPair(resName, "pinned_revision", resName, "updated"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeStackscript_mockPinRevision(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	var stackscriptName = acctest.RandomWithPrefix("tf_test")
+	var resName = "linode_stackscript.foobar"
+	var instanceName = "linode_instance.foobar"
+
+	// reviseOutside makes a revision as if a teammate saved the StackScript in the Linode Manager, changing the script
+	// unless it is only saved again
+	reviseOutside := func(script string) func() {
+		return func() {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			for _, stackscript := range api.stackscripts {
+				if script != "" {
+					stackscript.Script = script
+				}
+				updated, _ := time.Parse(mockLinodeAPIDateLayout, stackscript.UpdatedStr)
+				stackscript.UpdatedStr = updated.Add(time.Hour).Format(mockLinodeAPIDateLayout)
+			}
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    api.providers(),
+		CheckDestroy: api.checkInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeStackscriptPinRevision(stackscriptName) + testAccCheckLinodeStackscriptInstance("${linode_stackscript.foobar.pinned_revision}"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resName, "pinned_revision", resName, "updated"),
+					resource.TestCheckResourceAttrPair(instanceName, "stackscript_revision", resName, "pinned_revision"),
+				),
+			},
+			{
+				// The revision made outside of Terraform shows in the plan, even when the script is unchanged
+				PreConfig:          reviseOutside(""),
+				Config:             testAccCheckLinodeStackscriptPinRevision(stackscriptName) + testAccCheckLinodeStackscriptInstance("${linode_stackscript.foobar.pinned_revision}"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Applying restores the configured script and pins the restored revision
+				PreConfig: reviseOutside("#!/bin/bash\necho revised\n"),
+				Config:    testAccCheckLinodeStackscriptPinRevision(stackscriptName) + testAccCheckLinodeStackscriptInstance("${linode_stackscript.foobar.pinned_revision}"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "script", "#!/bin/bash\necho hello\n"),
+					resource.TestCheckResourceAttrPair(resName, "pinned_revision", resName, "updated"),
+					resource.TestCheckResourceAttrPair(instanceName, "stackscript_revision", resName, "pinned_revision"),
+				),
+			},
+		},
+	})
+}
+
+func TestLinodeStackscript_revisionWarning(t *testing.T) {
+	pinned := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	revised := pinned.Add(time.Hour)
+
+	stackscript := linodego.Stackscript{ID: 123, Updated: &pinned, RevNote: "initial"}
+	if warning := stackscriptRevisionWarning(pinned.String(), stackscript); warning != "" {
+		t.Errorf("Expected no warning for the pinned revision, got %q", warning)
+	}
+	if warning := stackscriptRevisionWarning("", stackscript); warning != "" {
+		t.Errorf("Expected no warning without a pinned revision, got %q", warning)
+	}
+
+	stackscript.Updated, stackscript.RevNote = &revised, "hotfix"
+	warning := stackscriptRevisionWarning(pinned.String(), stackscript)
+	for _, expected := range []string{"Stackscript 123", pinned.String(), revised.String(), `"hotfix"`} {
+		if !strings.Contains(warning, expected) {
+			t.Errorf("Expected the warning to mention %s, got %q", expected, warning)
+		}
+	}
+}
+
 func testAccCheckLinodeStackscriptExists(s *terraform.State) error {
-	client := testAccProvider.Meta().(linodego.Client)
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_stackscript" {
@@ -184,10 +292,7 @@ func testAccCheckLinodeStackscriptExists(s *terraform.State) error {
 }
 
 func testAccCheckLinodeStackscriptDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_stackscript" {
 			continue
@@ -258,3 +363,31 @@ EOF
 	rev_note = "second"
 }`, stackscript)
 }
+
+func testAccCheckLinodeStackscriptPinRevision(stackscript string) string {
+	return fmt.Sprintf(`
+resource "linode_stackscript" "foobar" {
+	label = "%s"
+	script = <<EOF
+#!/bin/bash
+echo hello
+EOF
+	images = ["linode/ubuntu18.04"]
+	description = "tf_test stackscript"
+	rev_note = "initial"
+	pin_revision = true
+}`, stackscript)
+}
+
+func testAccCheckLinodeStackscriptInstance(revision string) string {
+	return fmt.Sprintf(`
+
+resource "linode_instance" "foobar" {
+	type = "g6-nanode-1"
+	region = "us-east"
+	image = "linode/ubuntu18.04"
+	root_pass = "terraform-test"
+	stackscript_id = "${linode_stackscript.foobar.id}"
+	stackscript_revision = "%s"
+}`, revision)
+}
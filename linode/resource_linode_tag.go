@@ -0,0 +1,186 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/linode/linodego"
+)
+
+// tagMemberAttributes are the attributes of a linode_tag listing the IDs of tagged entities, by entity type
+var tagMemberAttributes = map[string]string{
+	"linode":       "linodes",
+	"volume":       "volumes",
+	"nodebalancer": "nodebalancers",
+}
+
+func resourceLinodeTag() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLinodeTagCreate,
+		Read:   resourceLinodeTagRead,
+		Update: resourceLinodeTagUpdate,
+		Delete: resourceLinodeTagDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"label": {
+				Type:         schema.TypeString,
+				Description:  "The label of the tag.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTag,
+			},
+			"linodes": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The IDs of the Linode Instances with this tag.",
+				Optional:    true,
+			},
+			"volumes": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The IDs of the Volumes with this tag.",
+				Optional:    true,
+			},
+			"nodebalancers": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The IDs of the NodeBalancers with this tag.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceLinodeTagRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	label := d.Id()
+
+	objects, err := client.ListTaggedObjects(context.Background(), label, nil)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			log.Printf("[WARN] removing Linode Tag %q from state because it no longer exists", label)
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error finding the objects of Linode Tag %s: %s", label, err)
+	}
+
+	sorted, err := objects.SortedObjects()
+	if err != nil {
+		return fmt.Errorf("Error reading the objects of Linode Tag %s: %s", label, err)
+	}
+
+	members := map[string][]int{"linode": {}, "volume": {}, "nodebalancer": {}}
+	for _, instance := range sorted.Instances {
+		members["linode"] = append(members["linode"], instance.ID)
+	}
+	for _, volume := range sorted.Volumes {
+		members["volume"] = append(members["volume"], volume.ID)
+	}
+	for _, nodebalancer := range sorted.NodeBalancers {
+		members["nodebalancer"] = append(members["nodebalancer"], nodebalancer.ID)
+	}
+
+	d.Set("label", label)
+	for entityType, attribute := range tagMemberAttributes {
+		if err := d.Set(attribute, members[entityType]); err != nil {
+			return fmt.Errorf("Error setting %s of Linode Tag %s: %s", attribute, label, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLinodeTagCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+
+	createOpts := linodego.TagCreateOptions{
+		Label:         d.Get("label").(string),
+		Linodes:       expandTagMembers(d.Get("linodes").(*schema.Set)),
+		Volumes:       expandTagMembers(d.Get("volumes").(*schema.Set)),
+		NodeBalancers: expandTagMembers(d.Get("nodebalancers").(*schema.Set)),
+	}
+	tag, err := client.CreateTag(context.Background(), createOpts)
+	if err != nil {
+		return fmt.Errorf("Error creating a Linode Tag: %s", err)
+	}
+	d.SetId(tag.Label)
+
+	return resourceLinodeTagRead(d, meta)
+}
+
+func resourceLinodeTagUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	label := d.Id()
+
+	for entityType, attribute := range tagMemberAttributes {
+		if !d.HasChange(attribute) {
+			continue
+		}
+		o, n := d.GetChange(attribute)
+		oldMembers, newMembers := o.(*schema.Set), n.(*schema.Set)
+
+		for _, id := range expandTagMembers(newMembers.Difference(oldMembers)) {
+			if err := tagEntity(client, entityType, id, label, true); err != nil {
+				return err
+			}
+		}
+		for _, id := range expandTagMembers(oldMembers.Difference(newMembers)) {
+			if err := tagEntity(client, entityType, id, label, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceLinodeTagRead(d, meta)
+}
+
+func resourceLinodeTagDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderMeta).Client
+	label := d.Id()
+	if err := client.DeleteTag(context.Background(), label); err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error deleting Linode Tag %s: %s", label, err)
+	}
+	return nil
+}
+
+// expandTagMembers returns the entity IDs of a linode_tag member set
+func expandTagMembers(members *schema.Set) []int {
+	ids := make([]int, 0, members.Len())
+	for _, id := range members.List() {
+		ids = append(ids, id.(int))
+	}
+	return ids
+}
+
+// tagEntity adds the tag label to, or when tagged is false removes it from, the current tags of an entity. An entity
+// which no longer exists does not need the tag removed.
+func tagEntity(client linodego.Client, entityType string, entityID int, label string, tagged bool) error {
+	tags, err := getEntityTags(client, entityType, entityID)
+	if err != nil {
+		if lerr, ok := err.(*linodego.Error); ok && lerr.Code == 404 && !tagged {
+			return nil
+		}
+		return fmt.Errorf("Error getting the tags of %s %d: %s", entityType, entityID, err)
+	}
+
+	newTags := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		if tag != label {
+			newTags = append(newTags, tag)
+		}
+	}
+	if tagged {
+		newTags = append(newTags, label)
+	}
+
+	_, err = applyTags(client, entityType, entityID, tags, newTags)
+	return err
+}
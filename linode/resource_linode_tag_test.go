@@ -0,0 +1,223 @@
+package linode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/linode/linodego"
+)
+
+func init() {
+	resource.AddTestSweepers("linode_tag", &resource.Sweeper{
+		Name: "linode_tag",
+		F:    testSweepLinodeTag,
+	})
+}
+
+func testSweepLinodeTag(prefix string) error {
+	client, err := getClientForSweepers()
+	if err != nil {
+		return fmt.Errorf("Error getting client: %s", err)
+	}
+
+	tags, err := client.ListTags(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("Error getting tags: %s", err)
+	}
+	for _, tag := range tags {
+		if !shouldSweepAcceptanceTestResource(prefix, tag.Label) {
+			continue
+		}
+		err := client.DeleteTag(context.Background(), tag.Label)
+
+		if err != nil {
+			return fmt.Errorf("Error destroying %s during sweep: %s", tag.Label, err)
+		}
+	}
+
+	return nil
+}
+
+func TestAccLinodeTag_basic(t *testing.T) {
+	t.Parallel()
+
+	resName := "linode_tag.foobar"
+	var tagName = acctest.RandomWithPrefix("tf_test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLinodeTagDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeTagConfigInstances(tagName, "linode_instance.foo.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", tagName),
+					resource.TestCheckResourceAttr(resName, "linodes.#", "1"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeTagConfigInstances(tagName, "linode_instance.foo.id", "linode_instance.bar.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "linodes.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestLinodeTag_mock(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resName := "linode_tag.foobar"
+	var tagName = acctest.RandomWithPrefix("tf_test")
+
+	// The tagged instances are created outside of Terraform, so the tags of instances are only managed by the tag
+	client := api.client()
+	var instanceIDs []int
+	for _, label := range []string{"foo", "bar"} {
+		instance, err := client.CreateInstance(context.Background(), linodego.InstanceCreateOptions{
+			Label:  acctest.RandomWithPrefix("tf_test_" + label),
+			Type:   "g6-nanode-1",
+			Region: "us-east",
+			Tags:   []string{"unrelated"},
+		})
+		if err != nil {
+			t.Fatalf("Error creating the instance: %s", err)
+		}
+		instanceIDs = append(instanceIDs, instance.ID)
+	}
+	foo, bar := strconv.Itoa(instanceIDs[0]), strconv.Itoa(instanceIDs[1])
+
+	checkInstanceTags := func(id int, expected ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if tags := api.instances[id].Tags; strings.Join(tags, ",") != strings.Join(expected, ",") {
+				return fmt.Errorf("Expected instance %d to have tags %v, got %v", id, expected, tags)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		CheckDestroy: func(*terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if api.tagExists(tagName) {
+				return fmt.Errorf("Linode Tag %s still exists", tagName)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLinodeTagConfigBasic(tagName, foo),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "label", tagName),
+					resource.TestCheckResourceAttr(resName, "linodes.#", "1"),
+					checkInstanceTags(instanceIDs[0], "unrelated", tagName),
+					checkInstanceTags(instanceIDs[1], "unrelated"),
+				),
+			},
+			{
+				Config: testAccCheckLinodeTagConfigBasic(tagName, bar),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "linodes.#", "1"),
+					checkInstanceTags(instanceIDs[0], "unrelated"),
+					checkInstanceTags(instanceIDs[1], "unrelated", tagName),
+				),
+			},
+			{
+				// The tag is applied to an instance outside of Terraform, which is found by Read and removed
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.instances[instanceIDs[0]].Tags = append(api.instances[instanceIDs[0]].Tags, tagName)
+				},
+				Config: testAccCheckLinodeTagConfigBasic(tagName, bar),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "linodes.#", "1"),
+					checkInstanceTags(instanceIDs[0], "unrelated"),
+					checkInstanceTags(instanceIDs[1], "unrelated", tagName),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckLinodeTagDestroy(s *terraform.State) error {
+	client, ok := testAccProvider.Meta().(*ProviderMeta)
+	if !ok {
+		return fmt.Errorf("Error getting Linode client")
+	}
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "linode_tag" {
+			continue
+		}
+
+		_, err := client.Client.ListTaggedObjects(context.Background(), rs.Primary.ID, nil)
+
+		if err == nil {
+			return fmt.Errorf("Linode Tag %s still exists", rs.Primary.ID)
+		}
+
+		if apiErr, ok := err.(*linodego.Error); ok && apiErr.Code != 404 {
+			return fmt.Errorf("Error requesting Linode Tag %s: %s", rs.Primary.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckLinodeTagConfigBasic(label string, linodes ...string) string {
+	return fmt.Sprintf(`
+resource "linode_tag" "foobar" {
+	label = "%s"
+	linodes = [%s]
+}`, label, strings.Join(linodes, ", "))
+}
+
+func testAccCheckLinodeTagConfigInstances(label string, linodes ...string) string {
+	return fmt.Sprintf(`
+resource "linode_instance" "foo" {
+	label = "%s_foo"
+	type = "g6-nanode-1"
+	region = "us-east"
+
+	lifecycle {
+		ignore_changes = ["tags"]
+	}
+}
+
+resource "linode_instance" "bar" {
+	label = "%s_bar"
+	type = "g6-nanode-1"
+	region = "us-east"
+
+	lifecycle {
+		ignore_changes = ["tags"]
+	}
+}
+
+resource "linode_tag" "foobar" {
+	label = "%s"
+	linodes = ["${%s}"]
+}`, label, label, label, strings.Join(linodes, "}\", \"${"))
+}
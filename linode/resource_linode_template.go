@@ -45,7 +45,7 @@ func resourceLinodeTemplate() *schema.Resource {
 }
 
 func resourceLinodeTemplateExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode Template ID %s as int: %s", d.Id(), err)
@@ -64,7 +64,7 @@ func resourceLinodeTemplateExists(d *schema.ResourceData, meta interface{}) (boo
 }
 
 func resourceLinodeTemplateRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Template ID %s as int: %s", d.Id(), err)
@@ -83,10 +83,7 @@ func resourceLinodeTemplateRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeTemplateCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Template")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	createOpts := linodego.TemplateCreateOptions{
 		Label: d.Get("label").(string),
@@ -102,7 +99,7 @@ func resourceLinodeTemplateCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceLinodeTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -125,7 +122,7 @@ func resourceLinodeTemplateUpdate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceLinodeTemplateDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Template id %s as int", d.Id())
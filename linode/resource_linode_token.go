@@ -81,7 +81,7 @@ func validDateTime(i interface{}, k string) (s []string, es []error) {
 }
 
 func resourceLinodeTokenExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode Token ID %s as int: %s", d.Id(), err)
@@ -100,7 +100,7 @@ func resourceLinodeTokenExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceLinodeTokenRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Token ID %s as int: %s", d.Id(), err)
@@ -121,10 +121,7 @@ func resourceLinodeTokenRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeTokenCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Token")
-	}
+	client := meta.(*ProviderMeta).Client
 
 	createOpts := linodego.TokenCreateOptions{
 		Label:  d.Get("label").(string),
@@ -152,7 +149,7 @@ func resourceLinodeTokenCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeTokenUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -178,7 +175,7 @@ func resourceLinodeTokenUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceLinodeTokenDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Token id %s as int", d.Id())
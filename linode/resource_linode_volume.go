@@ -19,11 +19,12 @@ const (
 
 func resourceLinodeVolume() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceLinodeVolumeCreate,
-		Read:   resourceLinodeVolumeRead,
-		Update: resourceLinodeVolumeUpdate,
-		Delete: resourceLinodeVolumeDelete,
-		Exists: resourceLinodeVolumeExists,
+		Create:        resourceLinodeVolumeCreate,
+		Read:          resourceLinodeVolumeRead,
+		Update:        resourceLinodeVolumeUpdate,
+		Delete:        resourceLinodeVolumeDelete,
+		CustomizeDiff: customizeResourceTagsAllDiff,
+		Exists:        resourceLinodeVolumeExists,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -67,18 +68,14 @@ func resourceLinodeVolume() *schema.Resource {
 				Description: "The full filesystem path for the Volume based on the Volume's label. Path is /dev/disk/by-id/scsi-0Linode_Volume_ + Volume label.",
 				Computed:    true,
 			},
-			"tags": {
-				Type:        schema.TypeSet,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Optional:    true,
-				Description: "An array of tags applied to this object. Tags are for organizational purposes only.",
-			},
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
 
 func resourceLinodeVolumeExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return false, fmt.Errorf("Error parsing Linode Volume ID %s as int: %s", d.Id(), err)
@@ -96,7 +93,7 @@ func resourceLinodeVolumeExists(d *schema.ResourceData, meta interface{}) (bool,
 }
 
 func resourceLinodeVolumeRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Volume ID %s as int: %s", d.Id(), err)
@@ -119,16 +116,15 @@ func resourceLinodeVolumeRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("size", volume.Size)
 	d.Set("linode_id", volume.LinodeID)
 	d.Set("filesystem_path", volume.FilesystemPath)
-	d.Set("tags", volume.Tags)
+	if err := readTags(d, meta, "volume", volume.ID, volume.Tags); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func resourceLinodeVolumeCreate(d *schema.ResourceData, meta interface{}) error {
-	client, ok := meta.(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Invalid Client when creating Linode Volume")
-	}
+	client := meta.(*ProviderMeta).Client
 	d.Partial(true)
 
 	var linodeID *int
@@ -145,10 +141,8 @@ func resourceLinodeVolumeCreate(d *schema.ResourceData, meta interface{}) error
 		createOpts.LinodeID = *linodeID
 	}
 
-	if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
-		for _, tag := range tagsRaw.(*schema.Set).List() {
-			createOpts.Tags = append(createOpts.Tags, tag.(string))
-		}
+	if tags := expandResourceTags(d, meta); len(tags) > 0 {
+		createOpts.Tags = tags
 	}
 
 	volume, err := client.CreateVolume(context.Background(), createOpts)
@@ -178,7 +172,7 @@ func resourceLinodeVolumeCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	d.Partial(true)
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
@@ -205,29 +199,23 @@ func resourceLinodeVolumeUpdate(d *schema.ResourceData, meta interface{}) error
 		d.SetPartial("size")
 	}
 
-	updateOpts := linodego.VolumeUpdateOptions{}
-	doUpdate := false
-	if d.HasChange("tags") {
-		tags := []string{}
-		for _, tag := range d.Get("tags").(*schema.Set).List() {
-			tags = append(tags, tag.(string))
+	if d.HasChange("tags") || d.HasChange("tags_all") {
+		tags, err := applyTags(client, "volume", volume.ID, volume.Tags, expandResourceTags(d, meta))
+		if err != nil {
+			return err
 		}
-
-		updateOpts.Tags = &tags
-		doUpdate = true
+		if err = readTags(d, meta, "volume", volume.ID, tags); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+		d.SetPartial("tags_all")
 	}
 
 	if d.HasChange("label") {
-		updateOpts.Label = d.Get("label").(string)
-		doUpdate = true
-	}
-
-	if doUpdate {
+		updateOpts := linodego.VolumeUpdateOptions{Label: d.Get("label").(string)}
 		if volume, err = client.UpdateVolume(context.Background(), volume.ID, updateOpts); err != nil {
 			return err
 		}
-		d.Set("tags", volume.Tags)
-		d.SetPartial("tags")
 		d.Set("label", volume.Label)
 		d.SetPartial("label")
 	}
@@ -282,7 +270,7 @@ func resourceLinodeVolumeUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceLinodeVolumeDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(linodego.Client)
+	client := meta.(*ProviderMeta).Client
 	id64, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("Error parsing Linode Volume id %s as int", d.Id())
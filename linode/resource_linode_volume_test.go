@@ -3,6 +3,7 @@ package linode
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -276,9 +277,31 @@ func TestAccLinodeVolume_reattachedBetweenInstances(t *testing.T) {
 	})
 }
 
+func TestLinodeVolume_mockTagValidation(t *testing.T) {
+	api := newMockLinodeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: api.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckLinodeVolumeConfigTags(`["tf_test", "tf"]`),
+				ExpectError: regexp.MustCompile(`must be between 3 and 50 characters, got "tf"`),
+			},
+			{
+				Config:      testAccCheckLinodeVolumeConfigTags(`["tf_test", "tf_test_2 "]`),
+				ExpectError: regexp.MustCompile(`must not begin or end with whitespace, got "tf_test_2 "`),
+			},
+		},
+	})
+
+	if calls := api.callCount("POST volumes"); calls != 0 {
+		t.Errorf("Expected no volumes to be created, got %d create calls", calls)
+	}
+}
+
 func testAccCheckLinodeVolumeExists(name string, volume *linodego.Volume) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(linodego.Client)
+		client := testAccProvider.Meta().(*ProviderMeta).Client
 
 		rs, ok := s.RootModule().Resources[name]
 		if !ok {
@@ -306,10 +329,7 @@ func testAccCheckLinodeVolumeExists(name string, volume *linodego.Volume) resour
 }
 
 func testAccCheckLinodeVolumeDestroy(s *terraform.State) error {
-	client, ok := testAccProvider.Meta().(linodego.Client)
-	if !ok {
-		return fmt.Errorf("Error getting Linode client")
-	}
+	client := testAccProvider.Meta().(*ProviderMeta).Client
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "linode_volume" {
 			continue
@@ -425,3 +445,12 @@ resource "linode_volume" "foobaz" {
 }
 `, volume, volume)
 }
+
+func testAccCheckLinodeVolumeConfigTags(tags string) string {
+	return fmt.Sprintf(`
+resource "linode_volume" "foobar" {
+	label = "tf_test_tags"
+	region = "us-west"
+	tags = %s
+}`, tags)
+}